@@ -0,0 +1,11 @@
+package v1alpha1
+
+const (
+	// AnnotationKeyOwner is the annotation of the generated CRDs recording the RestDefinition owning them,
+	// as '<namespace>/<name>'. Only the owner updates and removes the CRDs.
+	AnnotationKeyOwner = "ogen.krateo.io/owner"
+
+	// AnnotationKeyAdoptFrom is the annotation of a RestDefinition taking over the generated CRDs owned by
+	// another RestDefinition, as '<namespace>/<name>' of the current owner.
+	AnnotationKeyAdoptFrom = "ogen.krateo.io/adopt-from"
+)
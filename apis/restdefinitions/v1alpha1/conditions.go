@@ -0,0 +1,174 @@
+package v1alpha1
+
+import (
+	rtv1 "github.com/krateoplatformops/provider-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TypeSchemaSynced indicates whether the CRD schemas generated from the OAS are applied to the installed CRDs.
+const TypeSchemaSynced rtv1.ConditionType = "SchemaSynced"
+
+// Reasons the generated CRD schemas are or are not applied to the installed CRDs.
+const (
+	ReasonSchemaUpToDate           rtv1.ConditionReason = "SchemaUpToDate"
+	ReasonIncompatibleSchemaChange rtv1.ConditionReason = "IncompatibleSchemaChange"
+	ReasonSchemaVersionRequired    rtv1.ConditionReason = "SchemaVersionRequired"
+)
+
+// SchemaUpToDate returns a condition that indicates the installed CRDs match the schemas generated from the OAS.
+func SchemaUpToDate() rtv1.Condition {
+	return rtv1.Condition{
+		Type:               TypeSchemaSynced,
+		Status:             metav1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonSchemaUpToDate,
+	}
+}
+
+// SchemaIncompatible returns a condition that indicates the schemas generated from the OAS
+// cannot be applied in place to the installed CRDs, since existing resources would not be valid anymore.
+func SchemaIncompatible() rtv1.Condition {
+	return rtv1.Condition{
+		Type:               TypeSchemaSynced,
+		Status:             metav1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonIncompatibleSchemaChange,
+	}
+}
+
+// SchemaVersionRequired returns a condition that indicates the schemas generated from the OAS
+// contain breaking changes that require a new API version of the installed CRDs.
+func SchemaVersionRequired() rtv1.Condition {
+	return rtv1.Condition{
+		Type:               TypeSchemaSynced,
+		Status:             metav1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonSchemaVersionRequired,
+	}
+}
+
+// TypeResourcesReleased indicates whether the generated CRDs and their resources are released by a RestDefinition
+// being deleted, according to its deletion policy.
+const TypeResourcesReleased rtv1.ConditionType = "ResourcesReleased"
+
+// Reasons the generated CRDs and their resources are or are not released by a RestDefinition being deleted.
+const (
+	ReasonResourcesOrphaned   rtv1.ConditionReason = "ResourcesOrphaned"
+	ReasonResourcesDeleted    rtv1.ConditionReason = "ResourcesDeleted"
+	ReasonDeletingResources   rtv1.ConditionReason = "DeletingResources"
+	ReasonResourcesStillExist rtv1.ConditionReason = "ResourcesStillExist"
+)
+
+// ResourcesOrphaned returns a condition that indicates the generated CRDs and their resources are kept,
+// while the dynamic controller is removed.
+func ResourcesOrphaned() rtv1.Condition {
+	return rtv1.Condition{
+		Type:               TypeResourcesReleased,
+		Status:             metav1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonResourcesOrphaned,
+	}
+}
+
+// ResourcesDeleted returns a condition that indicates no resources are left, and the generated CRDs
+// and the dynamic controller are removed.
+func ResourcesDeleted() rtv1.Condition {
+	return rtv1.Condition{
+		Type:               TypeResourcesReleased,
+		Status:             metav1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonResourcesDeleted,
+	}
+}
+
+// DeletingResources returns a condition that indicates the resources are being deleted from the external system.
+func DeletingResources() rtv1.Condition {
+	return rtv1.Condition{
+		Type:               TypeResourcesReleased,
+		Status:             metav1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonDeletingResources,
+	}
+}
+
+// ResourcesStillExist returns a condition that indicates the deletion is blocked until no resources are left.
+func ResourcesStillExist() rtv1.Condition {
+	return rtv1.Condition{
+		Type:               TypeResourcesReleased,
+		Status:             metav1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonResourcesStillExist,
+	}
+}
+
+// TypeCRDsOwned indicates whether the generated CRDs are owned by the RestDefinition (see AnnotationKeyOwner).
+const TypeCRDsOwned rtv1.ConditionType = "CRDsOwned"
+
+// Reasons the generated CRDs are or are not owned by the RestDefinition.
+const (
+	ReasonOwned                        rtv1.ConditionReason = "Owned"
+	ReasonOwnedByAnotherRestDefinition rtv1.ConditionReason = "OwnedByAnotherRestDefinition"
+)
+
+// CRDsOwned returns a condition that indicates the generated CRDs are owned by the RestDefinition.
+func CRDsOwned() rtv1.Condition {
+	return rtv1.Condition{
+		Type:               TypeCRDsOwned,
+		Status:             metav1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonOwned,
+	}
+}
+
+// CRDsOwnedByAnother returns a condition that indicates the CRDs to generate are owned by another RestDefinition,
+// so they are not generated nor updated.
+func CRDsOwnedByAnother() rtv1.Condition {
+	return rtv1.Condition{
+		Type:               TypeCRDsOwned,
+		Status:             metav1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonOwnedByAnotherRestDefinition,
+	}
+}
+
+// TypeOASAccepted indicates whether the fetched OAS document is accepted, i.e., it matches the pinned checksum, if any.
+const TypeOASAccepted rtv1.ConditionType = "OASAccepted"
+
+// Reasons the fetched OAS document is or is not accepted.
+const (
+	ReasonChecksumMatched   rtv1.ConditionReason = "ChecksumMatched"
+	ReasonChecksumNotPinned rtv1.ConditionReason = "ChecksumNotPinned"
+	ReasonChecksumMismatch  rtv1.ConditionReason = "ChecksumMismatch"
+)
+
+// ChecksumMatched returns a condition that indicates the OAS document matches the pinned checksum.
+func ChecksumMatched() rtv1.Condition {
+	return rtv1.Condition{
+		Type:               TypeOASAccepted,
+		Status:             metav1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonChecksumMatched,
+	}
+}
+
+// ChecksumNotPinned returns a condition that indicates the OAS document is accepted without verification,
+// since no checksum is pinned.
+func ChecksumNotPinned() rtv1.Condition {
+	return rtv1.Condition{
+		Type:               TypeOASAccepted,
+		Status:             metav1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonChecksumNotPinned,
+	}
+}
+
+// ChecksumMismatch returns a condition that indicates the OAS document does not match the pinned checksum,
+// so it is refused and the CRDs are not generated nor updated from it.
+func ChecksumMismatch() rtv1.Condition {
+	return rtv1.Condition{
+		Type:               TypeOASAccepted,
+		Status:             metav1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonChecksumMismatch,
+	}
+}
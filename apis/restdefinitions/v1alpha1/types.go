@@ -2,6 +2,7 @@ package v1alpha1
 
 import (
 	rtv1 "github.com/krateoplatformops/provider-runtime/apis/common/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -124,11 +125,62 @@ type VerbsDescription struct {
 	Pagination *Pagination `json:"pagination,omitempty"`
 }
 
+// FieldMapping defines how a field of the resource is converted from one API version to another.
+// +kubebuilder:validation:XValidation:rule="self.type == 'default' ? has(self.value) : has(self.from)",message="from must be set for 'rename' and 'move' mappings, value must be set for 'default' mappings"
+type FieldMapping struct {
+	// Type: the kind of mapping [rename, move, default]
+	// - 'rename': the field at 'from' is renamed to 'to', in the same parent object.
+	// - 'move': the field at 'from' is moved to the path 'to'.
+	// - 'default': the field at 'to' is set to 'value' if missing.
+	// +kubebuilder:validation:Enum=rename;move;default
+	// +required
+	Type string `json:"type"`
+	// From: the path of the field in the 'from' version (for example 'spec.name'). Not used by 'default' mappings.
+	// +optional
+	From string `json:"from,omitempty"`
+	// To: the new name of the field for 'rename' mappings, the path of the field in the 'to' version otherwise.
+	// +required
+	To string `json:"to"`
+	// Value: the value set by 'default' mappings.
+	// +optional
+	Value *apiextensionsv1.JSON `json:"value,omitempty"`
+}
+
+// VersionConversion defines how resources are converted from one API version to another.
+// Resources are converted back using the inverse of the 'rename' and 'move' mappings.
+type VersionConversion struct {
+	// From: the API version the resources are converted from
+	// +required
+	From string `json:"from"`
+	// To: the API version the resources are converted to
+	// +required
+	To string `json:"to"`
+	// Fields: the mappings applied, in order, to the fields of the resources
+	// +optional
+	Fields []FieldMapping `json:"fields,omitempty"`
+}
+
 type Resource struct {
 	// Name: the name of the resource to manage
 	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Kind is immutable, you cannot change that once the CRD has been generated"
 	// +required
 	Kind string `json:"kind"`
+	// Version: the API version of the generated CRDs (e.g., 'v1alpha1', 'v1beta1' or 'v1').
+	// When it changes, the new version is served alongside the previous ones, so the API of the resource can be migrated.
+	// +kubebuilder:validation:Pattern=`^v[1-9][0-9]*((alpha|beta)[1-9][0-9]*)?$`
+	// +kubebuilder:default=v1alpha1
+	// +optional
+	Version string `json:"version,omitempty"`
+	// Scope: whether the resources of the generated CRD are cluster-scoped or namespaced.
+	// Cluster-scoped resources fit external objects that are global (e.g., organizations or regions).
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Scope is immutable, you cannot change it once the CRD has been generated"
+	// +kubebuilder:default=Namespaced
+	// +optional
+	Scope ResourceScope `json:"scope,omitempty"`
+	// Conversions: the field mappings used by the conversion webhook to convert resources between the served versions.
+	// Versions without a declared conversion path are converted without changing their fields.
+	// +optional
+	Conversions []VersionConversion `json:"conversions,omitempty"`
 	// VerbsDescription: the list of verbs to use on this resource
 	// +required
 	VerbsDescription []VerbsDescription `json:"verbsDescription"`
@@ -148,17 +200,76 @@ type Resource struct {
 	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="ExcludedSpecFields are immutable, you cannot change them once the CRD has been generated"
 	// +optional
 	ExcludedSpecFields []string `json:"excludedSpecFields,omitempty"`
+	// CoerceNumberToInteger: if true, every 'number' type of the OAS is converted to 'integer' in the generated CRD (legacy behaviour).
+	// By default the 'number' type is preserved.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="CoerceNumberToInteger is immutable, you cannot change it once the CRD has been generated"
+	// +optional
+	CoerceNumberToInteger bool `json:"coerceNumberToInteger,omitempty"`
 }
 
+// ResourceScope is the scope of the resources of a generated CRD.
+// +kubebuilder:validation:Enum=Cluster;Namespaced
+type ResourceScope string
+
+const (
+	// ResourceScopeCluster generates a cluster-scoped CRD.
+	ResourceScopeCluster ResourceScope = "Cluster"
+	// ResourceScopeNamespaced generates a namespaced CRD.
+	ResourceScopeNamespaced ResourceScope = "Namespaced"
+)
+
+// BreakingChangePolicy defines what to do with the breaking changes of the schemas generated from the OAS.
+// +kubebuilder:validation:Enum=Allow;Block;Version
+type BreakingChangePolicy string
+
+const (
+	// BreakingChangePolicyAllow applies breaking changes to the installed CRDs in place.
+	BreakingChangePolicyAllow BreakingChangePolicy = "Allow"
+	// BreakingChangePolicyBlock does not apply breaking changes to the installed CRDs.
+	BreakingChangePolicyBlock BreakingChangePolicy = "Block"
+	// BreakingChangePolicyVersion does not apply breaking changes to the served version of the installed CRDs,
+	// as they require a new API version (see Resource.Version).
+	BreakingChangePolicyVersion BreakingChangePolicy = "Version"
+)
+
+// DeletionPolicy defines what to do with the generated CRDs and their resources when the RestDefinition is deleted.
+// +kubebuilder:validation:Enum=Orphan;Cascade;Block
+type DeletionPolicy string
+
+const (
+	// DeletionPolicyOrphan keeps the generated CRDs and their resources, removing only the dynamic controller.
+	DeletionPolicyOrphan DeletionPolicy = "Orphan"
+	// DeletionPolicyCascade deletes the resources first, waiting for the dynamic controller to delete them from the
+	// external system, then removes the generated CRDs and the dynamic controller.
+	DeletionPolicyCascade DeletionPolicy = "Cascade"
+	// DeletionPolicyBlock refuses to delete the RestDefinition while resources of its CRD exist: the deletion is
+	// completed, and the dynamic controller removed, only once no resources are left. The CRD of the resource is
+	// removed right away, so no new resources can be created in the meantime.
+	DeletionPolicyBlock DeletionPolicy = "Block"
+)
+
 // RestDefinitionSpec is the specification of a RestDefinition.
 type RestDefinitionSpec struct {
 	// Path to the OpenAPI specification. This value can change over time, for example if the OAS file is updated but be sure to not change the requestbody of the `create` verb.
 	// +required
 	// - configmap://<namespace>/<name>/<key>
+	// - secret://<namespace>/<name>/<key> (the Secret must be in the namespace of the RestDefinition)
 	// - http(s)://<url>
+	// ConfigMap and Secret keys can be gzip compressed (e.g., ConfigMap binaryData keys), to store documents larger than 1 MiB.
 	// +kubebuilder:validation:Required
-	// +kubebuilder:validation:Pattern=`^(configmap:\/\/([a-z0-9-]+)\/([a-z0-9-]+)\/([a-zA-Z0-9.-_]+)|https?:\/\/\S+)$`
+	// +kubebuilder:validation:Pattern=`^((configmap|secret):\/\/([a-z0-9-]+)\/([a-z0-9-]+)\/([a-zA-Z0-9.-_]+)|https?:\/\/\S+)$`
 	OASPath string `json:"oasPath"`
+	// OASChecksum: the SHA-256 checksum (hex encoded) the OAS document must match, to pin it (e.g., when served from a moving branch).
+	// Mismatching documents are refused. It covers the document at oasPath along with the files it references;
+	// for a document made of a single file, it is the SHA-256 checksum of the file.
+	// It is compared with the digest recorded in status.oasDigest, which can be copied here to pin the document in use.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[a-fA-F0-9]{64}$`
+	OASChecksum string `json:"oasChecksum,omitempty"`
+	// OASAuth: the credentials used to download the OAS document (and the files it references) over HTTP(S).
+	// They are read from Secrets in the namespace of the RestDefinition.
+	// +optional
+	OASAuth *OASAuth `json:"oasAuth,omitempty"`
 	// Group: the group of the resource to manage
 	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="ResourceGroup is immutable, you cannot change that once the CRD has been generated"
 	// +required
@@ -166,6 +277,70 @@ type RestDefinitionSpec struct {
 	// The resource to manage
 	// +required
 	Resource Resource `json:"resource"`
+	// BreakingChangePolicy: what to do when the schemas generated from the OAS contain breaking changes
+	// (narrowing or destructive) compared to the installed CRDs. Compatible changes are always applied in place.
+	// - 'Block': breaking changes are not applied (the default).
+	// - 'Allow': breaking changes are applied in place, existing resources may not be valid anymore.
+	// - 'Version': breaking changes are not applied to the served version, they are served with a new 'resource.version'.
+	// +kubebuilder:default=Block
+	// +optional
+	BreakingChangePolicy BreakingChangePolicy `json:"breakingChangePolicy,omitempty"`
+	// DeletionPolicy: what to do with the generated CRDs and their resources when the RestDefinition is deleted.
+	// - 'Block': the deletion is refused while resources exist, and completed once no resources are left (the default). The CRD of the resource is removed right away.
+	// - 'Orphan': the generated CRDs and their resources are kept, only the dynamic controller is removed.
+	// - 'Cascade': the resources are deleted first, then the generated CRDs and the dynamic controller are removed once they are deleted from the external system.
+	// +kubebuilder:default=Block
+	// +optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// SecretKeySelector selects a key of a Secret in the namespace of the RestDefinition.
+type SecretKeySelector struct {
+	// Name: the name of the Secret
+	// +required
+	Name string `json:"name"`
+	// Key: the key of the Secret
+	// +required
+	Key string `json:"key"`
+}
+
+// OASAuth defines the credentials used to download the OAS document. Exactly one method must be set.
+// +kubebuilder:validation:XValidation:rule="[has(self.basic), has(self.bearer), has(self.header)].filter(x, x).size() == 1",message="exactly one of basic, bearer and header must be set"
+type OASAuth struct {
+	// Basic: HTTP basic authentication
+	// +optional
+	Basic *OASBasicAuth `json:"basic,omitempty"`
+	// Bearer: bearer token authentication ('Authorization: Bearer <token>')
+	// +optional
+	Bearer *OASBearerAuth `json:"bearer,omitempty"`
+	// Header: authentication with a custom header (e.g., 'PRIVATE-TOKEN: <token>')
+	// +optional
+	Header *OASHeaderAuth `json:"header,omitempty"`
+}
+
+type OASBasicAuth struct {
+	// UsernameRef: the Secret key holding the username
+	// +required
+	UsernameRef SecretKeySelector `json:"usernameRef"`
+	// PasswordRef: the Secret key holding the password
+	// +required
+	PasswordRef SecretKeySelector `json:"passwordRef"`
+}
+
+type OASBearerAuth struct {
+	// TokenRef: the Secret key holding the token
+	// +required
+	TokenRef SecretKeySelector `json:"tokenRef"`
+}
+
+type OASHeaderAuth struct {
+	// Name: the name of the header
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	Name string `json:"name"`
+	// ValueRef: the Secret key holding the value of the header
+	// +required
+	ValueRef SecretKeySelector `json:"valueRef"`
 }
 
 type ConfigurationField struct {
@@ -195,6 +370,28 @@ type KindApiVersion struct {
 	Kind string `json:"kind,omitempty"`
 }
 
+// SchemaChange is a change between the schema of an installed CRD and the schema generated from the OAS.
+type SchemaChange struct {
+	// CRD: the name of the changed CRD
+	CRD string `json:"crd"`
+	// Path: the path of the changed field
+	Path string `json:"path"`
+	// Type: the impact of the change on existing resources [Additive, Narrowing, Destructive]
+	Type string `json:"type"`
+	// Description: a human readable description of the change
+	Description string `json:"description"`
+}
+
+// InventoryEntry identifies an object applied for a RestDefinition.
+type InventoryEntry struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	// Namespace: the namespace of the object, empty for cluster-scoped objects
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+}
+
 // RestDefinitionStatus is the status of a RestDefinition.
 type RestDefinitionStatus struct {
 	rtv1.ConditionedStatus `json:",inline"`
@@ -214,10 +411,28 @@ type RestDefinitionStatus struct {
 	// +optional
 	Digest string `json:"digest,omitempty"`
 
+	// OASDigest: the SHA-256 digest of the OAS document (including the files it references) the CRDs were generated from.
+	// It can be copied to spec.oasChecksum to pin the document.
+	// +optional
+	OASDigest string `json:"oasDigest,omitempty"`
+
+	// SchemaDigest: the SHA-256 digest of the schemas of the CRDs generated from the OAS document
+	// +optional
+	SchemaDigest string `json:"schemaDigest,omitempty"`
+
 	// HasSecuritySchemes: whether the OAS document defines security schemes.
-	// Cached here so Observe does not need to re-fetch the OAS document on every reconcile.
+	// Saved here so it is known even when the OAS document cannot be fetched (e.g., during uninstall).
 	// +optional
 	HasSecuritySchemes *bool `json:"hasSecuritySchemes,omitempty"`
+
+	// Inventory: the objects applied for the RestDefinition (the CRDs, and the RBAC resources, ConfigMap and Deployment
+	// of the dynamic controller). They are exactly the objects removed when the RestDefinition is deleted.
+	// +optional
+	Inventory []InventoryEntry `json:"inventory,omitempty"`
+
+	// SchemaChanges: the changes between the installed CRDs and the schemas generated from the OAS not applied yet.
+	// +optional
+	SchemaChanges []SchemaChange `json:"schemaChanges,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -228,6 +443,7 @@ type RestDefinitionStatus struct {
 // +kubebuilder:printcolumn:name="API VERSION",type="string",JSONPath=".status.resource.apiVersion",priority=10
 // +kubebuilder:printcolumn:name="KIND",type="string",JSONPath=".status.resource.kind",priority=10
 // +kubebuilder:printcolumn:name="OAS PATH",type="string",JSONPath=".status.oasPath",priority=10
+// +kubebuilder:printcolumn:name="OAS DIGEST",type="string",JSONPath=".status.oasDigest",priority=10
 // RestDefinition is a RestDefinition type with a spec and a status.
 type RestDefinition struct {
 	metav1.TypeMeta   `json:",inline"`
@@ -21,6 +21,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -88,6 +89,26 @@ func (in *ContinuationTokenResponse) DeepCopy() *ContinuationTokenResponse {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FieldMapping) DeepCopyInto(out *FieldMapping) {
+	*out = *in
+	if in.Value != nil {
+		in, out := &in.Value, &out.Value
+		*out = new(v1.JSON)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FieldMapping.
+func (in *FieldMapping) DeepCopy() *FieldMapping {
+	if in == nil {
+		return nil
+	}
+	out := new(FieldMapping)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FromOpenAPI) DeepCopyInto(out *FromOpenAPI) {
 	*out = *in
@@ -123,6 +144,21 @@ func (in *FromRestDefinition) DeepCopy() *FromRestDefinition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InventoryEntry) DeepCopyInto(out *InventoryEntry) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InventoryEntry.
+func (in *InventoryEntry) DeepCopy() *InventoryEntry {
+	if in == nil {
+		return nil
+	}
+	out := new(InventoryEntry)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KindApiVersion) DeepCopyInto(out *KindApiVersion) {
 	*out = *in
@@ -138,6 +174,85 @@ func (in *KindApiVersion) DeepCopy() *KindApiVersion {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OASAuth) DeepCopyInto(out *OASAuth) {
+	*out = *in
+	if in.Basic != nil {
+		in, out := &in.Basic, &out.Basic
+		*out = new(OASBasicAuth)
+		**out = **in
+	}
+	if in.Bearer != nil {
+		in, out := &in.Bearer, &out.Bearer
+		*out = new(OASBearerAuth)
+		**out = **in
+	}
+	if in.Header != nil {
+		in, out := &in.Header, &out.Header
+		*out = new(OASHeaderAuth)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OASAuth.
+func (in *OASAuth) DeepCopy() *OASAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(OASAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OASBasicAuth) DeepCopyInto(out *OASBasicAuth) {
+	*out = *in
+	out.UsernameRef = in.UsernameRef
+	out.PasswordRef = in.PasswordRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OASBasicAuth.
+func (in *OASBasicAuth) DeepCopy() *OASBasicAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(OASBasicAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OASBearerAuth) DeepCopyInto(out *OASBearerAuth) {
+	*out = *in
+	out.TokenRef = in.TokenRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OASBearerAuth.
+func (in *OASBearerAuth) DeepCopy() *OASBearerAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(OASBearerAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OASHeaderAuth) DeepCopyInto(out *OASHeaderAuth) {
+	*out = *in
+	out.ValueRef = in.ValueRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OASHeaderAuth.
+func (in *OASHeaderAuth) DeepCopy() *OASHeaderAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(OASHeaderAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Pagination) DeepCopyInto(out *Pagination) {
 	*out = *in
@@ -176,6 +291,13 @@ func (in *RequestFieldMappingItem) DeepCopy() *RequestFieldMappingItem {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Resource) DeepCopyInto(out *Resource) {
 	*out = *in
+	if in.Conversions != nil {
+		in, out := &in.Conversions, &out.Conversions
+		*out = make([]VersionConversion, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.VerbsDescription != nil {
 		in, out := &in.VerbsDescription, &out.VerbsDescription
 		*out = make([]VerbsDescription, len(*in))
@@ -279,6 +401,11 @@ func (in *RestDefinitionList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RestDefinitionSpec) DeepCopyInto(out *RestDefinitionSpec) {
 	*out = *in
+	if in.OASAuth != nil {
+		in, out := &in.OASAuth, &out.OASAuth
+		*out = new(OASAuth)
+		(*in).DeepCopyInto(*out)
+	}
 	in.Resource.DeepCopyInto(&out.Resource)
 }
 
@@ -303,6 +430,16 @@ func (in *RestDefinitionStatus) DeepCopyInto(out *RestDefinitionStatus) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.Inventory != nil {
+		in, out := &in.Inventory, &out.Inventory
+		*out = make([]InventoryEntry, len(*in))
+		copy(*out, *in)
+	}
+	if in.SchemaChanges != nil {
+		in, out := &in.SchemaChanges, &out.SchemaChanges
+		*out = make([]SchemaChange, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestDefinitionStatus.
@@ -315,6 +452,36 @@ func (in *RestDefinitionStatus) DeepCopy() *RestDefinitionStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchemaChange) DeepCopyInto(out *SchemaChange) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchemaChange.
+func (in *SchemaChange) DeepCopy() *SchemaChange {
+	if in == nil {
+		return nil
+	}
+	out := new(SchemaChange)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretKeySelector) DeepCopyInto(out *SecretKeySelector) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretKeySelector.
+func (in *SecretKeySelector) DeepCopy() *SecretKeySelector {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretKeySelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VerbsDescription) DeepCopyInto(out *VerbsDescription) {
 	*out = *in
@@ -339,3 +506,25 @@ func (in *VerbsDescription) DeepCopy() *VerbsDescription {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VersionConversion) DeepCopyInto(out *VersionConversion) {
+	*out = *in
+	if in.Fields != nil {
+		in, out := &in.Fields, &out.Fields
+		*out = make([]FieldMapping, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VersionConversion.
+func (in *VersionConversion) DeepCopy() *VersionConversion {
+	if in == nil {
+		return nil
+	}
+	out := new(VersionConversion)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,76 @@
+package restdefinition
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	definitionv1alpha1 "github.com/krateoplatformops/oasgen-provider/apis/restdefinitions/v1alpha1"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/filegetter"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/oas2jsonschema"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ChecksumMismatchError is returned when the OAS document does not match the pinned checksum.
+type ChecksumMismatchError struct {
+	Src      string
+	Expected string
+	Actual   string
+}
+
+func (e *ChecksumMismatchError) Error() string {
+	return fmt.Sprintf("checksum mismatch for %s: expected sha256 %s, got %s", e.Src, e.Expected, e.Actual)
+}
+
+// loadOASBundle loads the OAS document at the oasPath of the RestDefinition along with the files it references,
+// refusing it if it does not match the pinned checksum (spec.oasChecksum).
+// The checksum is the digest of the whole bundle (see oas2jsonschema.Bundle.Digest), so the referenced files are pinned too;
+// for a document made of a single file, it is the SHA-256 checksum of the file.
+// It is the same digest recorded in status.oasDigest once the CRDs are generated from the document, so that it can be pinned;
+// whether the document is accepted is reported with the OASAccepted condition.
+func (e *external) loadOASBundle(ctx context.Context, cr *definitionv1alpha1.RestDefinition, getter *filegetter.Filegetter, auth *filegetter.AuthConfig) (*oas2jsonschema.Bundle, error) {
+	OASPath := cr.Spec.OASPath
+
+	// The OAS document can be split across multiple files (a bundle), referenced with relative $refs.
+	// The files are resolved relative to the OASPath (other keys of the same ConfigMap or Secret, or URLs relative to the OASPath).
+	// They are kept in memory, so concurrent reconciles never share files on disk.
+	entry := path.Clean(path.Base(OASPath))
+	bundle, err := oas2jsonschema.LoadBundle(entry, func(rel string) ([]byte, error) {
+		src := OASPath
+		if rel != entry {
+			var err error
+			src, err = filegetter.ResolveSibling(OASPath, rel)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		contents, err := getter.Get(ctx, src, auth)
+		if err != nil {
+			return nil, fmt.Errorf("failed to download file: %w", err)
+		}
+		return contents, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	digest := bundle.Digest()
+	if cr.Spec.OASChecksum == "" {
+		cr.SetConditions(definitionv1alpha1.ChecksumNotPinned())
+		return bundle, nil
+	}
+	if strings.EqualFold(digest, cr.Spec.OASChecksum) {
+		cr.SetConditions(definitionv1alpha1.ChecksumMatched())
+		return bundle, nil
+	}
+
+	mismatch := &ChecksumMismatchError{Src: OASPath, Expected: cr.Spec.OASChecksum, Actual: digest}
+	msg := fmt.Sprintf("OAS document checksum %s does not match the pinned checksum %s", mismatch.Actual, mismatch.Expected)
+	if cr.GetCondition(definitionv1alpha1.TypeOASAccepted).Reason != definitionv1alpha1.ReasonChecksumMismatch {
+		e.rec.Eventf(cr, corev1.EventTypeWarning, string(definitionv1alpha1.ReasonChecksumMismatch), msg)
+	}
+	cr.SetConditions(definitionv1alpha1.ChecksumMismatch().WithMessage(msg))
+	return nil, mismatch
+}
@@ -0,0 +1,123 @@
+package restdefinition
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	definitionv1alpha1 "github.com/krateoplatformops/oasgen-provider/apis/restdefinitions/v1alpha1"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/filegetter"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/oas2jsonschema"
+	rtv1 "github.com/krateoplatformops/provider-runtime/apis/common/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestLoadOASBundle(t *testing.T) {
+	const document = `
+openapi: 3.0.3
+info:
+  title: Repos
+  version: 1.0.0
+paths:
+  /repos:
+    get:
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: './repo.yaml#/Repo'
+`
+	repo := "Repo:\n  type: object\n"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/openapi.yaml":
+			w.Write([]byte(document))
+		case "/repo.yaml":
+			w.Write([]byte(repo))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	getter := &filegetter.Filegetter{
+		Client:     http.DefaultClient,
+		KubeClient: fake.NewClientBuilder().Build(),
+	}
+	load := func(e *external, cr *definitionv1alpha1.RestDefinition) (*oas2jsonschema.Bundle, error) {
+		return e.loadOASBundle(context.Background(), cr, getter, nil)
+	}
+
+	// The checksum is the digest of the whole bundle
+	probe := &definitionv1alpha1.RestDefinition{Spec: definitionv1alpha1.RestDefinitionSpec{OASPath: server.URL + "/openapi.yaml"}}
+	bundle, err := load(&external{rec: record.NewFakeRecorder(10)}, probe)
+	require.NoError(t, err)
+	require.Len(t, bundle.Files, 2)
+	checksum := bundle.Digest()
+
+	tests := []struct {
+		name       string
+		pinned     string
+		repo       string
+		wantReason rtv1.ConditionReason
+		wantErr    bool
+	}{
+		{name: "Not pinned", wantReason: definitionv1alpha1.ReasonChecksumNotPinned},
+		{name: "Matching checksum", pinned: checksum, wantReason: definitionv1alpha1.ReasonChecksumMatched},
+		{name: "Matching uppercase checksum", pinned: strings.ToUpper(checksum), wantReason: definitionv1alpha1.ReasonChecksumMatched},
+		{name: "Mismatching checksum", pinned: strings.Repeat("0", 64), wantReason: definitionv1alpha1.ReasonChecksumMismatch, wantErr: true},
+		{name: "Referenced file changed", pinned: checksum, repo: "Repo:\n  type: string\n", wantReason: definitionv1alpha1.ReasonChecksumMismatch, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.repo != "" {
+				previous := repo
+				repo = tt.repo
+				defer func() { repo = previous }()
+			}
+
+			rec := record.NewFakeRecorder(10)
+			e := &external{rec: rec}
+			cr := &definitionv1alpha1.RestDefinition{
+				ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "gh-system"},
+				Spec: definitionv1alpha1.RestDefinitionSpec{
+					OASPath:     server.URL + "/openapi.yaml",
+					OASChecksum: tt.pinned,
+				},
+			}
+
+			bundle, err := load(e, cr)
+			assert.Equal(t, tt.wantReason, cr.GetCondition(definitionv1alpha1.TypeOASAccepted).Reason)
+			if !tt.wantErr {
+				require.NoError(t, err)
+				assert.Equal(t, document, string(bundle.Files["openapi.yaml"]))
+				assert.Empty(t, rec.Events)
+				return
+			}
+
+			var mismatch *ChecksumMismatchError
+			require.True(t, errors.As(err, &mismatch))
+			if tt.repo == "" {
+				assert.Equal(t, checksum, mismatch.Actual)
+			} else {
+				assert.NotEqual(t, checksum, mismatch.Actual)
+			}
+			assert.Len(t, rec.Events, 1)
+
+			// The event is recorded only when the document is first refused
+			_, err = load(e, cr)
+			assert.Error(t, err)
+			assert.Len(t, rec.Events, 1)
+		})
+	}
+}
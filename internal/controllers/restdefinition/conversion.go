@@ -0,0 +1,152 @@
+package restdefinition
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	definitionv1alpha1 "github.com/krateoplatformops/oasgen-provider/apis/restdefinitions/v1alpha1"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/conversion"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/crd"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/text"
+	"github.com/krateoplatformops/plumbing/env"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// conversionWebhookConf returns the conversion configuration of the generated CRDs serving multiple versions,
+// or nil if the conversion webhook is not enabled (CONVERSION_WEBHOOK_SERVICE is not set).
+// The webhook is reached by the API server through the Service CONVERSION_WEBHOOK_SERVICE ('<namespace>/<name>')
+// and its certificate is verified with the CA bundle at CONVERSION_WEBHOOK_CA_BUNDLE_PATH.
+func conversionWebhookConf() (*apiextensionsv1.CustomResourceConversion, error) {
+	svc := env.String("CONVERSION_WEBHOOK_SERVICE", "")
+	if svc == "" {
+		return nil, nil
+	}
+	namespace, name, ok := strings.Cut(svc, "/")
+	if !ok || namespace == "" || name == "" {
+		return nil, fmt.Errorf("invalid conversion webhook service '%s', expected '<namespace>/<name>'", svc)
+	}
+
+	caBundlePath := env.String("CONVERSION_WEBHOOK_CA_BUNDLE_PATH", path.Join(os.TempDir(), "k8s-webhook-server/serving-certs/ca.crt"))
+	caBundle, err := os.ReadFile(caBundlePath)
+	if err != nil {
+		return nil, fmt.Errorf("reading conversion webhook CA bundle: %w", err)
+	}
+
+	port := int32(env.Int("CONVERSION_WEBHOOK_SERVICE_PORT", 443))
+	webhookPath := conversion.Path
+	return &apiextensionsv1.CustomResourceConversion{
+		Strategy: apiextensionsv1.WebhookConverter,
+		Webhook: &apiextensionsv1.WebhookConversion{
+			ClientConfig: &apiextensionsv1.WebhookClientConfig{
+				Service: &apiextensionsv1.ServiceReference{
+					Namespace: namespace,
+					Name:      name,
+					Path:      &webhookPath,
+					Port:      &port,
+				},
+				CABundle: caBundle,
+			},
+			ConversionReviewVersions: []string{"v1"},
+		},
+	}, nil
+}
+
+// conversionKindIndex indexes the RestDefinitions by the group kinds ('<kind>.<group>') of the CRDs they generate.
+const conversionKindIndex = "spec.resource.groupKind"
+
+// indexConversionKinds returns the group kinds of the resource and configuration CRDs generated by the RestDefinition.
+func indexConversionKinds(obj client.Object) []string {
+	cr, ok := obj.(*definitionv1alpha1.RestDefinition)
+	if !ok {
+		return nil
+	}
+	return []string{
+		schema.GroupKind{Group: cr.Spec.ResourceGroup, Kind: text.CapitaliseFirstLetter(cr.Spec.Resource.Kind)}.String(),
+		getConfigurationGVK(cr).GroupKind().String(),
+	}
+}
+
+// setupConversionWebhook serves the conversion webhook of the generated CRDs with the webhook server of the manager.
+// The RestDefinitions are looked up in the cache of the manager, indexed by the group kinds they generate.
+func setupConversionWebhook(mgr ctrl.Manager, log func(msg string, keysAndValues ...any)) error {
+	err := mgr.GetFieldIndexer().IndexField(context.Background(), &definitionv1alpha1.RestDefinition{}, conversionKindIndex, indexConversionKinds)
+	if err != nil {
+		return fmt.Errorf("failed to index RestDefinitions by generated kind: %w", err)
+	}
+
+	kube := mgr.GetClient()
+	mgr.GetWebhookServer().Register(conversion.Path, conversion.NewWebhook(func(ctx context.Context, gk schema.GroupKind) ([]conversion.Conversion, error) {
+		return lookupConversions(ctx, kube, gk)
+	}, log))
+	return nil
+}
+
+// lookupConversions returns the conversions declared by the RestDefinition generating the given group kind.
+// Configuration kinds have no declared conversions.
+func lookupConversions(ctx context.Context, kube client.Client, gk schema.GroupKind) ([]conversion.Conversion, error) {
+	list := definitionv1alpha1.RestDefinitionList{}
+	if err := kube.List(ctx, &list, client.MatchingFields{conversionKindIndex: gk.String()}); err != nil {
+		return nil, fmt.Errorf("listing RestDefinitions: %w", err)
+	}
+
+	for i := range list.Items {
+		cr := &list.Items[i]
+		switch gk.Kind {
+		case text.CapitaliseFirstLetter(cr.Spec.Resource.Kind):
+			return toConversions(cr.Spec.Resource.Conversions)
+		case getConfigurationGVK(cr).Kind:
+			return nil, nil
+		}
+	}
+	return nil, fmt.Errorf("no RestDefinition found for %s", gk.String())
+}
+
+// Shim needed to convert definitionv1alpha1.VersionConversion to conversion.Conversion
+// so the conversion package is not tied with the RestDefinition CRD
+func toConversions(conversions []definitionv1alpha1.VersionConversion) ([]conversion.Conversion, error) {
+	res := make([]conversion.Conversion, 0, len(conversions))
+	for _, c := range conversions {
+		mappings := make([]conversion.Mapping, 0, len(c.Fields))
+		for _, f := range c.Fields {
+			m := conversion.Mapping{
+				Type: f.Type,
+				From: f.From,
+				To:   f.To,
+			}
+			if f.Value != nil {
+				if err := json.Unmarshal(f.Value.Raw, &m.Value); err != nil {
+					return nil, fmt.Errorf("decoding default value of '%s': %w", f.To, err)
+				}
+			}
+			mappings = append(mappings, m)
+		}
+		res = append(res, conversion.Conversion{
+			From:     c.From,
+			To:       c.To,
+			Mappings: mappings,
+		})
+	}
+	return res, nil
+}
+
+// desiredConversion returns the conversion configuration of the installed CRD: the conversion webhook
+// if the CRD serves multiple versions and the webhook is enabled, no conversion otherwise.
+func (e *external) desiredConversion(installed *apiextensionsv1.CustomResourceDefinition) *apiextensionsv1.CustomResourceConversion {
+	versions := 0
+	for _, ver := range installed.Spec.Versions {
+		if ver.Name != crd.VacuumVersion {
+			versions++
+		}
+	}
+	if versions > 1 && e.conversion != nil {
+		return e.conversion
+	}
+	return &apiextensionsv1.CustomResourceConversion{Strategy: apiextensionsv1.NoneConverter}
+}
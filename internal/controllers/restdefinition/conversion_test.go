@@ -0,0 +1,95 @@
+package restdefinition
+
+import (
+	"context"
+	"testing"
+
+	"github.com/krateoplatformops/oasgen-provider/apis"
+	definitionv1alpha1 "github.com/krateoplatformops/oasgen-provider/apis/restdefinitions/v1alpha1"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/conversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestLookupConversions(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, apis.AddToScheme(scheme))
+
+	cr := &definitionv1alpha1.RestDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "gh-system"},
+		Spec: definitionv1alpha1.RestDefinitionSpec{
+			ResourceGroup: "github.ogen.krateo.io",
+			Resource: definitionv1alpha1.Resource{
+				Kind:    "repo",
+				Version: "v1beta1",
+				Conversions: []definitionv1alpha1.VersionConversion{
+					{
+						From: "v1alpha1",
+						To:   "v1beta1",
+						Fields: []definitionv1alpha1.FieldMapping{
+							{Type: "rename", From: "spec.name", To: "displayName"},
+							{Type: "default", To: "spec.private", Value: &apiextensionsv1.JSON{Raw: []byte("true")}},
+						},
+					},
+				},
+			},
+		},
+	}
+	// A RestDefinition generating the same kind in another group must not match
+	other := cr.DeepCopy()
+	other.Name = "other"
+	other.Spec.ResourceGroup = "gitlab.ogen.krateo.io"
+	other.Spec.Resource.Conversions = nil
+	kube := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(other, cr).
+		WithIndex(&definitionv1alpha1.RestDefinition{}, conversionKindIndex, indexConversionKinds).
+		Build()
+
+	conversions, err := lookupConversions(context.Background(), kube, schema.GroupKind{Group: "github.ogen.krateo.io", Kind: "Repo"})
+	require.NoError(t, err)
+	assert.Equal(t, []conversion.Conversion{
+		{
+			From: "v1alpha1",
+			To:   "v1beta1",
+			Mappings: []conversion.Mapping{
+				{Type: "rename", From: "spec.name", To: "displayName"},
+				{Type: "default", To: "spec.private", Value: true},
+			},
+		},
+	}, conversions)
+
+	conversions, err = lookupConversions(context.Background(), kube, schema.GroupKind{Group: "github.ogen.krateo.io", Kind: "RepoConfiguration"})
+	require.NoError(t, err)
+	assert.Empty(t, conversions)
+
+	conversions, err = lookupConversions(context.Background(), kube, schema.GroupKind{Group: "gitlab.ogen.krateo.io", Kind: "Repo"})
+	require.NoError(t, err)
+	assert.Empty(t, conversions)
+
+	_, err = lookupConversions(context.Background(), kube, schema.GroupKind{Group: "github.ogen.krateo.io", Kind: "Team"})
+	assert.Error(t, err)
+}
+
+func TestDesiredConversion(t *testing.T) {
+	withVersions := func(names ...string) *apiextensionsv1.CustomResourceDefinition {
+		res := &apiextensionsv1.CustomResourceDefinition{}
+		for _, name := range names {
+			res.Spec.Versions = append(res.Spec.Versions, apiextensionsv1.CustomResourceDefinitionVersion{Name: name})
+		}
+		return res
+	}
+	none := &apiextensionsv1.CustomResourceConversion{Strategy: apiextensionsv1.NoneConverter}
+	webhook := &apiextensionsv1.CustomResourceConversion{Strategy: apiextensionsv1.WebhookConverter}
+
+	e := &external{conversion: webhook}
+	assert.Equal(t, none, e.desiredConversion(withVersions("v1alpha1")))
+	assert.Equal(t, webhook, e.desiredConversion(withVersions("v1alpha1", "v1beta1", "vacuum")))
+
+	e = &external{}
+	assert.Equal(t, none, e.desiredConversion(withVersions("v1alpha1", "v1beta1", "vacuum")))
+}
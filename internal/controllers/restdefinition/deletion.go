@@ -0,0 +1,94 @@
+package restdefinition
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	definitionv1alpha1 "github.com/krateoplatformops/oasgen-provider/apis/restdefinitions/v1alpha1"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/deploy"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// getDeletionPolicy returns the deletion policy of the RestDefinition, Block by default.
+func getDeletionPolicy(cr *definitionv1alpha1.RestDefinition) definitionv1alpha1.DeletionPolicy {
+	if cr.Spec.DeletionPolicy == "" {
+		return definitionv1alpha1.DeletionPolicyBlock
+	}
+	return cr.Spec.DeletionPolicy
+}
+
+// listRestResources returns the resources of the CRD generated for the RestDefinition (see restResourceListOptions).
+// No resources are returned if the CRD does not exist.
+func listRestResources(ctx context.Context, kubecli client.Client, cr *definitionv1alpha1.RestDefinition, log func(msg string, keysAndValues ...any)) ([]unstructured.Unstructured, error) {
+	gvk := getResourceGVK(cr)
+
+	uli := unstructured.UnstructuredList{}
+	uli.SetGroupVersionKind(gvk)
+	err := kubecli.List(ctx, &uli, restResourceListOptions(cr)...)
+	if err != nil && !strings.Contains(err.Error(), "no matches for") {
+
+		// If the CRD is missing, we assume no resources exist
+		if strings.Contains(err.Error(), "the server could not find the requested resource") {
+			log("CRD not found, treating as no resources exist",
+				"Group", cr.Spec.ResourceGroup,
+				"Kind", cr.Spec.Resource.Kind,
+				"Version", gvk.Version,
+				"error", err.Error())
+			return nil, nil
+		}
+		return nil, fmt.Errorf("listing RestResources: %w", err)
+	}
+
+	return uli.Items, nil
+}
+
+// restResourceListOptions returns the options to list the resources of the CRD generated for the RestDefinition,
+// given its scope: resources of namespaced kinds can be created in any namespace (not only the one of the RestDefinition),
+// so they are listed in all of them, while cluster-scoped resources cannot be listed by namespace.
+func restResourceListOptions(cr *definitionv1alpha1.RestDefinition) []client.ListOption {
+	if getResourceScope(cr) == apiextensionsv1.ClusterScoped {
+		return nil
+	}
+	return []client.ListOption{client.InNamespace(metav1.NamespaceAll)}
+}
+
+// deleteRestResources requests the deletion of the resources of the CRD generated for the RestDefinition,
+// returning how many of them are left. The resources are removed by the dynamic controller once deleted
+// from the external system.
+func (e *external) deleteRestResources(ctx context.Context, cr *definitionv1alpha1.RestDefinition) (int, error) {
+	items, err := listRestResources(ctx, e.kube, cr, e.log.Debug)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range items {
+		el := &items[i]
+		if el.GetDeletionTimestamp() != nil {
+			continue
+		}
+		e.log.Debug("Deleting RestResource", "Kind", el.GetKind(), "name", el.GetName(), "namespace", el.GetNamespace())
+		err := e.kube.Delete(ctx, el)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return 0, fmt.Errorf("deleting %s %s: %w", el.GetKind(), client.ObjectKeyFromObject(el).String(), err)
+		}
+	}
+
+	return len(items), nil
+}
+
+// orphanInventory removes the dynamic controller recorded in the inventory of the RestDefinition,
+// keeping the generated CRDs and their resources.
+func (e *external) orphanInventory(ctx context.Context, cr *definitionv1alpha1.RestDefinition) error {
+	var controller []definitionv1alpha1.InventoryEntry
+	for _, entry := range cr.Status.Inventory {
+		if !isCRD(entry) {
+			controller = append(controller, entry)
+		}
+	}
+	return deploy.UndeployReferences(ctx, e.kube, toReferences(controller), e.log.Debug)
+}
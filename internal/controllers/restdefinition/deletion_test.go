@@ -0,0 +1,120 @@
+package restdefinition
+
+import (
+	"context"
+	"testing"
+
+	definitionv1alpha1 "github.com/krateoplatformops/oasgen-provider/apis/restdefinitions/v1alpha1"
+	"github.com/krateoplatformops/provider-runtime/pkg/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestGetDeletionPolicy(t *testing.T) {
+	cr := &definitionv1alpha1.RestDefinition{}
+	assert.Equal(t, definitionv1alpha1.DeletionPolicyBlock, getDeletionPolicy(cr))
+
+	cr.Spec.DeletionPolicy = definitionv1alpha1.DeletionPolicyCascade
+	assert.Equal(t, definitionv1alpha1.DeletionPolicyCascade, getDeletionPolicy(cr))
+}
+
+func TestDeleteRestResources(t *testing.T) {
+	cr := &definitionv1alpha1.RestDefinition{
+		Spec: definitionv1alpha1.RestDefinitionSpec{
+			ResourceGroup: "github.ogen.krateo.io",
+			Resource:      definitionv1alpha1.Resource{Kind: "Repo"},
+		},
+	}
+	gvk := getResourceGVK(cr)
+
+	scheme := runtime.NewScheme()
+	scheme.AddKnownTypeWithName(gvk, &unstructured.Unstructured{})
+	scheme.AddKnownTypeWithName(gvk.GroupVersion().WithKind(gvk.Kind+"List"), &unstructured.UnstructuredList{})
+
+	repo := func(namespace, name string) *unstructured.Unstructured {
+		res := &unstructured.Unstructured{}
+		res.SetGroupVersionKind(gvk)
+		res.SetNamespace(namespace)
+		res.SetName(name)
+		return res
+	}
+
+	kube := fake.NewClientBuilder().WithScheme(scheme).WithObjects(repo("demo", "first"), repo("other", "second")).Build()
+	e := &external{kube: kube, log: logging.NewNopLogger()}
+
+	left, err := e.deleteRestResources(context.Background(), cr)
+	require.NoError(t, err)
+	assert.Equal(t, 2, left)
+
+	left, err = e.deleteRestResources(context.Background(), cr)
+	require.NoError(t, err)
+	assert.Equal(t, 0, left)
+}
+
+func TestListRestResourcesClusterScoped(t *testing.T) {
+	cr := &definitionv1alpha1.RestDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "org", Namespace: "demo"},
+		Spec: definitionv1alpha1.RestDefinitionSpec{
+			ResourceGroup: "github.ogen.krateo.io",
+			Resource:      definitionv1alpha1.Resource{Kind: "Org", Scope: definitionv1alpha1.ResourceScopeCluster},
+		},
+	}
+	gvk := getResourceGVK(cr)
+
+	scheme := runtime.NewScheme()
+	scheme.AddKnownTypeWithName(gvk, &unstructured.Unstructured{})
+	scheme.AddKnownTypeWithName(gvk.GroupVersion().WithKind(gvk.Kind+"List"), &unstructured.UnstructuredList{})
+
+	org := &unstructured.Unstructured{}
+	org.SetGroupVersionKind(gvk)
+	org.SetName("krateoplatformops")
+
+	// Cluster-scoped resources are not listed by namespace
+	assert.Empty(t, restResourceListOptions(cr))
+
+	kube := fake.NewClientBuilder().WithScheme(scheme).WithObjects(org).Build()
+	items, err := listRestResources(context.Background(), kube, cr, func(string, ...any) {})
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, "krateoplatformops", items[0].GetName())
+	assert.Empty(t, items[0].GetNamespace())
+
+	// The RestDefinition is blocked from deletion while the cluster-scoped resources exist
+	require.NoError(t, definitionv1alpha1.SchemeBuilder.AddToScheme(scheme))
+	kube = fake.NewClientBuilder().WithScheme(scheme).WithObjects(org, cr).Build()
+	require.NoError(t, manageFinalizers(context.Background(), kube, cr, func(string, ...any) {}))
+	assert.Contains(t, cr.GetFinalizers(), restresourcesStillExistFinalizer)
+}
+
+func TestOrphanInventory(t *testing.T) {
+	crd := &apiextensionsv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: "repos.github.ogen.krateo.io"}}
+	sa := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "repos-controller", Namespace: "demo"}}
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, apiextensionsv1.AddToScheme(scheme))
+	kube := fake.NewClientBuilder().WithScheme(scheme).WithObjects(crd, sa).Build()
+
+	cr := &definitionv1alpha1.RestDefinition{}
+	cr.Status.Inventory = []definitionv1alpha1.InventoryEntry{
+		crdInventoryEntry(schema.GroupResource{Group: "github.ogen.krateo.io", Resource: "repos"}),
+		{APIVersion: "v1", Kind: "ServiceAccount", Namespace: "demo", Name: sa.Name},
+	}
+
+	e := &external{kube: kube, log: logging.NewNopLogger()}
+	require.NoError(t, e.orphanInventory(context.Background(), cr))
+
+	assert.NoError(t, kube.Get(context.Background(), client.ObjectKeyFromObject(crd), &apiextensionsv1.CustomResourceDefinition{}))
+	err := kube.Get(context.Background(), client.ObjectKeyFromObject(sa), &corev1.ServiceAccount{})
+	assert.True(t, apierrors.IsNotFound(err))
+}
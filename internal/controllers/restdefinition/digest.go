@@ -0,0 +1,147 @@
+package restdefinition
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	definitionv1alpha1 "github.com/krateoplatformops/oasgen-provider/apis/restdefinitions/v1alpha1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// defaultOASRefetchInterval is the default minimum interval between two fetches of the OAS document of a RestDefinition.
+const defaultOASRefetchInterval = 5 * time.Minute
+
+// schemaDigest returns the SHA-256 digest of the schemas of the given CRDs, hex encoded. Nil CRDs are skipped.
+// Schemas are normalized first (see normalizeSchema), so the digest does not depend on the order of required and enum.
+func schemaDigest(crds ...*apiextensionsv1.CustomResourceDefinition) (string, error) {
+	h := sha256.New()
+	for _, c := range crds {
+		if c == nil {
+			continue
+		}
+		for _, ver := range c.Spec.Versions {
+			if ver.Schema == nil {
+				continue
+			}
+			dat, err := json.Marshal(normalizeSchema(ver.Schema.OpenAPIV3Schema))
+			if err != nil {
+				return "", err
+			}
+			h.Write([]byte(c.Name + "/" + ver.Name + "\x00"))
+			h.Write(dat)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// normalizeSchema returns a copy of the schema with the required fields and the enum values sorted, recursively.
+// Their order carries no meaning for validation.
+func normalizeSchema(in *apiextensionsv1.JSONSchemaProps) *apiextensionsv1.JSONSchemaProps {
+	if in == nil {
+		return nil
+	}
+	out := in.DeepCopy()
+	normalizeSchemaInPlace(out)
+	return out
+}
+
+func normalizeSchemaInPlace(s *apiextensionsv1.JSONSchemaProps) {
+	if s == nil {
+		return
+	}
+	sort.Strings(s.Required)
+	sort.SliceStable(s.Enum, func(i, j int) bool {
+		return string(s.Enum[i].Raw) < string(s.Enum[j].Raw)
+	})
+
+	normalizeMap := func(m map[string]apiextensionsv1.JSONSchemaProps) {
+		for k, v := range m {
+			normalizeSchemaInPlace(&v)
+			m[k] = v
+		}
+	}
+	normalizeSlice := func(l []apiextensionsv1.JSONSchemaProps) {
+		for i := range l {
+			normalizeSchemaInPlace(&l[i])
+		}
+	}
+
+	normalizeMap(s.Properties)
+	normalizeMap(s.PatternProperties)
+	normalizeMap(s.Definitions)
+	normalizeSlice(s.AllOf)
+	normalizeSlice(s.OneOf)
+	normalizeSlice(s.AnyOf)
+	normalizeSchemaInPlace(s.Not)
+	if s.Items != nil {
+		normalizeSchemaInPlace(s.Items.Schema)
+		normalizeSlice(s.Items.JSONSchemas)
+	}
+	if s.AdditionalProperties != nil {
+		normalizeSchemaInPlace(s.AdditionalProperties.Schema)
+	}
+	if s.AdditionalItems != nil {
+		normalizeSchemaInPlace(s.AdditionalItems.Schema)
+	}
+}
+
+type oasFetch struct {
+	at         time.Time
+	generation int64
+}
+
+// oasFetches records when the OAS document of each RestDefinition was last fetched, so that Observe
+// fetches it again only once the re-fetch interval has elapsed. Records are kept in memory, as losing
+// them (e.g., on restart) only causes the documents to be fetched again.
+type oasFetches struct {
+	mu       sync.Mutex
+	interval time.Duration
+	fetches  map[types.UID]oasFetch
+}
+
+func newOASFetches(interval time.Duration) *oasFetches {
+	return &oasFetches{
+		interval: interval,
+		fetches:  map[types.UID]oasFetch{},
+	}
+}
+
+// due reports whether the OAS document of the RestDefinition should be fetched: the interval is not set,
+// the document was never fetched or has been fetched more than an interval ago, the spec of the RestDefinition
+// changed since then, or the document is stored in a ConfigMap or Secret (which are watched, and cheap to read).
+func (f *oasFetches) due(cr *definitionv1alpha1.RestDefinition, now time.Time) bool {
+	if f == nil || f.interval <= 0 || strings.HasPrefix(cr.Spec.OASPath, "configmap://") || strings.HasPrefix(cr.Spec.OASPath, "secret://") {
+		return true
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	last, ok := f.fetches[cr.UID]
+	return !ok || last.generation != cr.Generation || now.Sub(last.at) >= f.interval
+}
+
+// record records that the OAS document of the RestDefinition has been fetched.
+func (f *oasFetches) record(cr *definitionv1alpha1.RestDefinition, now time.Time) {
+	if f == nil {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.fetches[cr.UID] = oasFetch{at: now, generation: cr.Generation}
+}
+
+// forget removes the record of the RestDefinition (e.g., once deleted).
+func (f *oasFetches) forget(cr *definitionv1alpha1.RestDefinition) {
+	if f == nil {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.fetches, cr.UID)
+}
@@ -0,0 +1,128 @@
+package restdefinition
+
+import (
+	"testing"
+	"time"
+
+	definitionv1alpha1 "github.com/krateoplatformops/oasgen-provider/apis/restdefinitions/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSchemaDigest(t *testing.T) {
+	withSchema := func(typ string) *apiextensionsv1.CustomResourceDefinition {
+		return &apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: "repoes.github.ogen.krateo.io"},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+					{
+						Name: "v1alpha1",
+						Schema: &apiextensionsv1.CustomResourceValidation{
+							OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{
+								Type: "object",
+								Properties: map[string]apiextensionsv1.JSONSchemaProps{
+									"name": {Type: typ},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	digest, err := schemaDigest(withSchema("string"), nil)
+	require.NoError(t, err)
+	same, err := schemaDigest(withSchema("string"))
+	require.NoError(t, err)
+	changed, err := schemaDigest(withSchema("integer"))
+	require.NoError(t, err)
+
+	assert.Len(t, digest, 64)
+	assert.Equal(t, digest, same)
+	assert.NotEqual(t, digest, changed)
+}
+
+func TestSchemaDigestNormalized(t *testing.T) {
+	withOrder := func(required []string, enum ...string) *apiextensionsv1.CustomResourceDefinition {
+		values := make([]apiextensionsv1.JSON, 0, len(enum))
+		for _, v := range enum {
+			values = append(values, apiextensionsv1.JSON{Raw: []byte(`"` + v + `"`)})
+		}
+		return &apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: "repoes.github.ogen.krateo.io"},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+					{
+						Name: "v1alpha1",
+						Schema: &apiextensionsv1.CustomResourceValidation{
+							OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{
+								Type: "object",
+								Properties: map[string]apiextensionsv1.JSONSchemaProps{
+									"spec": {
+										Type:     "object",
+										Required: required,
+										Properties: map[string]apiextensionsv1.JSONSchemaProps{
+											"name":       {Type: "string"},
+											"visibility": {Type: "string", Enum: values},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	original := withOrder([]string{"visibility", "name"}, "public", "private")
+	digest, err := schemaDigest(original)
+	require.NoError(t, err)
+	reordered, err := schemaDigest(withOrder([]string{"name", "visibility"}, "private", "public"))
+	require.NoError(t, err)
+	changed, err := schemaDigest(withOrder([]string{"name"}, "public", "private"))
+	require.NoError(t, err)
+
+	assert.Equal(t, digest, reordered)
+	assert.NotEqual(t, digest, changed)
+	assert.Equal(t, []string{"visibility", "name"}, original.Spec.Versions[0].Schema.OpenAPIV3Schema.Properties["spec"].Required, "the CRD is not modified")
+}
+
+func TestOASFetchesDue(t *testing.T) {
+	now := time.Now()
+	cr := &definitionv1alpha1.RestDefinition{
+		ObjectMeta: metav1.ObjectMeta{UID: "uid", Generation: 1},
+		Spec:       definitionv1alpha1.RestDefinitionSpec{OASPath: "https://example.com/openapi.yaml"},
+	}
+
+	f := newOASFetches(5 * time.Minute)
+	assert.True(t, f.due(cr, now), "never fetched")
+
+	f.record(cr, now)
+	assert.False(t, f.due(cr, now.Add(time.Minute)), "fetched recently")
+	assert.True(t, f.due(cr, now.Add(5*time.Minute)), "interval elapsed")
+
+	changed := cr.DeepCopy()
+	changed.Generation = 2
+	assert.True(t, f.due(changed, now.Add(time.Minute)), "spec changed")
+
+	inConfigMap := cr.DeepCopy()
+	inConfigMap.Spec.OASPath = "configmap://default/specs/openapi.yaml"
+	f.record(inConfigMap, now)
+	assert.True(t, f.due(inConfigMap, now.Add(time.Minute)), "stored in a ConfigMap")
+
+	inSecret := cr.DeepCopy()
+	inSecret.Spec.OASPath = "secret://default/specs/openapi.yaml"
+	f.record(inSecret, now)
+	assert.True(t, f.due(inSecret, now.Add(time.Minute)), "stored in a Secret")
+
+	f.forget(cr)
+	assert.True(t, f.due(cr, now.Add(time.Minute)), "forgotten")
+
+	assert.True(t, newOASFetches(0).due(cr, now), "interval not set")
+	var disabled *oasFetches
+	assert.True(t, disabled.due(cr, now), "nil")
+}
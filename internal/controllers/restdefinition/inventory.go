@@ -0,0 +1,148 @@
+package restdefinition
+
+import (
+	"context"
+	"fmt"
+
+	definitionv1alpha1 "github.com/krateoplatformops/oasgen-provider/apis/restdefinitions/v1alpha1"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/deploy"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/plurals"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// crdInventoryEntry returns the inventory entry of the CRD of the given group resource.
+func crdInventoryEntry(gr schema.GroupResource) definitionv1alpha1.InventoryEntry {
+	return definitionv1alpha1.InventoryEntry{
+		APIVersion: apiextensionsv1.SchemeGroupVersion.String(),
+		Kind:       "CustomResourceDefinition",
+		Name:       gr.String(),
+	}
+}
+
+// crdInventoryEntries returns the inventory entries of the CRDs of the given GVRs, skipping the empty ones.
+func crdInventoryEntries(gvrs ...schema.GroupVersionResource) []definitionv1alpha1.InventoryEntry {
+	var res []definitionv1alpha1.InventoryEntry
+	for _, gvr := range gvrs {
+		if gvr.Resource != "" {
+			res = append(res, crdInventoryEntry(gvr.GroupResource()))
+		}
+	}
+	return res
+}
+
+func isCRD(entry definitionv1alpha1.InventoryEntry) bool {
+	return entry.APIVersion == apiextensionsv1.SchemeGroupVersion.String() && entry.Kind == "CustomResourceDefinition"
+}
+
+// Shim needed to convert the references of the objects applied by deploy.Deploy to inventory entries
+// so the deploy package is not tied with the RestDefinition CRD
+func toInventory(refs []corev1.ObjectReference) []definitionv1alpha1.InventoryEntry {
+	res := make([]definitionv1alpha1.InventoryEntry, 0, len(refs))
+	for _, ref := range refs {
+		res = append(res, definitionv1alpha1.InventoryEntry{
+			APIVersion: ref.APIVersion,
+			Kind:       ref.Kind,
+			Namespace:  ref.Namespace,
+			Name:       ref.Name,
+		})
+	}
+	return res
+}
+
+func toReferences(entries []definitionv1alpha1.InventoryEntry) []corev1.ObjectReference {
+	res := make([]corev1.ObjectReference, 0, len(entries))
+	for _, entry := range entries {
+		res = append(res, corev1.ObjectReference{
+			APIVersion: entry.APIVersion,
+			Kind:       entry.Kind,
+			Namespace:  entry.Namespace,
+			Name:       entry.Name,
+		})
+	}
+	return res
+}
+
+// mergeInventory returns the inventory with the given entries appended, skipping the ones already recorded.
+func mergeInventory(inventory []definitionv1alpha1.InventoryEntry, entries ...definitionv1alpha1.InventoryEntry) []definitionv1alpha1.InventoryEntry {
+	res := append([]definitionv1alpha1.InventoryEntry{}, inventory...)
+	for _, entry := range entries {
+		if !containsEntry(res, entry) {
+			res = append(res, entry)
+		}
+	}
+	return res
+}
+
+func containsEntry(inventory []definitionv1alpha1.InventoryEntry, entry definitionv1alpha1.InventoryEntry) bool {
+	for _, el := range inventory {
+		if el == entry {
+			return true
+		}
+	}
+	return false
+}
+
+// hasControllerInventory reports whether the inventory records the objects of the dynamic controller.
+// RestDefinitions created before the inventory was introduced only record them once updated.
+func hasControllerInventory(inventory []definitionv1alpha1.InventoryEntry) bool {
+	for _, entry := range inventory {
+		if !isCRD(entry) {
+			return true
+		}
+	}
+	return false
+}
+
+// updateInventory records the objects applied by Create or Update in the inventory of the RestDefinition, removing
+// the objects of the dynamic controller recorded before but not applied anymore (e.g., the RBAC resources of a
+// previous API version). CRDs are never removed here, as they still serve the previous versions of the resources.
+func (e *external) updateInventory(ctx context.Context, cr *definitionv1alpha1.RestDefinition, applied []definitionv1alpha1.InventoryEntry) error {
+	var stale []definitionv1alpha1.InventoryEntry
+	var crds []definitionv1alpha1.InventoryEntry
+	for _, entry := range cr.Status.Inventory {
+		switch {
+		case isCRD(entry):
+			crds = append(crds, entry)
+		case !containsEntry(applied, entry):
+			stale = append(stale, entry)
+		}
+	}
+
+	if len(stale) > 0 {
+		e.log.Debug("Removing objects not applied anymore", "count", len(stale))
+		if err := deploy.UndeployReferences(ctx, e.kube, toReferences(stale), e.log.Debug); err != nil {
+			return err
+		}
+	}
+
+	cr.Status.Inventory = mergeInventory(crds, applied...)
+	return nil
+}
+
+// deleteInventory removes the objects recorded in the inventory of the RestDefinition. The CRD of the resource is
+// removed first, while the other objects (including the dynamic controller, which finalizes the resources, and the
+// Configuration CRD, which holds the configuration needed to do so) are removed only once no resources are left.
+func (e *external) deleteInventory(ctx context.Context, cr *definitionv1alpha1.RestDefinition, resourcesLeft bool) error {
+	resourceCRD := crdInventoryEntry(plurals.ToGroupVersionResource(getResourceGVK(cr)).GroupResource())
+
+	var others []definitionv1alpha1.InventoryEntry
+	for _, entry := range cr.Status.Inventory {
+		if entry == resourceCRD {
+			err := deploy.UndeployReferences(ctx, e.kube, toReferences([]definitionv1alpha1.InventoryEntry{entry}), e.log.Debug)
+			if err != nil {
+				return fmt.Errorf("uninstalling CRD: %w", err)
+			}
+			continue
+		}
+		others = append(others, entry)
+	}
+
+	if resourcesLeft {
+		e.log.Debug("Skipping removal of the dynamic controller, resources still exist")
+		return nil
+	}
+
+	return deploy.UndeployReferences(ctx, e.kube, toReferences(others), e.log.Debug)
+}
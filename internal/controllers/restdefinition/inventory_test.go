@@ -0,0 +1,110 @@
+package restdefinition
+
+import (
+	"context"
+	"testing"
+
+	definitionv1alpha1 "github.com/krateoplatformops/oasgen-provider/apis/restdefinitions/v1alpha1"
+	"github.com/krateoplatformops/provider-runtime/pkg/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestMergeInventory(t *testing.T) {
+	sa := definitionv1alpha1.InventoryEntry{APIVersion: "v1", Kind: "ServiceAccount", Namespace: "demo", Name: "orgs-controller"}
+	crd := crdInventoryEntry(schema.GroupResource{Group: "github.ogen.krateo.io", Resource: "orgs"})
+
+	inventory := mergeInventory(nil, crd)
+	inventory = mergeInventory(inventory, crd, sa, sa)
+	assert.Equal(t, []definitionv1alpha1.InventoryEntry{crd, sa}, inventory)
+}
+
+func TestCRDInventoryEntries(t *testing.T) {
+	entries := crdInventoryEntries(
+		schema.GroupVersionResource{Group: "github.ogen.krateo.io", Version: "v1alpha1", Resource: "orgs"},
+		schema.GroupVersionResource{},
+	)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "apiextensions.k8s.io/v1", entries[0].APIVersion)
+	assert.Equal(t, "CustomResourceDefinition", entries[0].Kind)
+	assert.Equal(t, "orgs.github.ogen.krateo.io", entries[0].Name)
+	assert.Empty(t, entries[0].Namespace)
+}
+
+func TestHasControllerInventory(t *testing.T) {
+	crd := crdInventoryEntry(schema.GroupResource{Group: "github.ogen.krateo.io", Resource: "orgs"})
+	sa := definitionv1alpha1.InventoryEntry{APIVersion: "v1", Kind: "ServiceAccount", Namespace: "demo", Name: "orgs-controller"}
+
+	assert.False(t, hasControllerInventory(nil))
+	assert.False(t, hasControllerInventory([]definitionv1alpha1.InventoryEntry{crd}))
+	assert.True(t, hasControllerInventory([]definitionv1alpha1.InventoryEntry{crd, sa}))
+}
+
+func newInventoryClient(t *testing.T, objs ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, apiextensionsv1.AddToScheme(scheme))
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+}
+
+func TestUpdateInventory(t *testing.T) {
+	oldRole := &rbacv1.Role{ObjectMeta: metav1.ObjectMeta{Name: "orgs-v1alpha1-controller", Namespace: "demo"}}
+	newRole := &rbacv1.Role{ObjectMeta: metav1.ObjectMeta{Name: "orgs-v1beta1-controller", Namespace: "demo"}}
+	kube := newInventoryClient(t, oldRole, newRole)
+
+	crd := crdInventoryEntry(schema.GroupResource{Group: "github.ogen.krateo.io", Resource: "orgs"})
+	roleEntry := func(name string) definitionv1alpha1.InventoryEntry {
+		return definitionv1alpha1.InventoryEntry{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "Role", Namespace: "demo", Name: name}
+	}
+
+	cr := &definitionv1alpha1.RestDefinition{}
+	cr.Status.Inventory = []definitionv1alpha1.InventoryEntry{crd, roleEntry(oldRole.Name)}
+
+	e := &external{kube: kube, log: logging.NewNopLogger()}
+	err := e.updateInventory(context.Background(), cr, []definitionv1alpha1.InventoryEntry{roleEntry(newRole.Name)})
+	require.NoError(t, err)
+
+	assert.Equal(t, []definitionv1alpha1.InventoryEntry{crd, roleEntry(newRole.Name)}, cr.Status.Inventory)
+
+	err = kube.Get(context.Background(), client.ObjectKeyFromObject(oldRole), &rbacv1.Role{})
+	assert.True(t, apierrors.IsNotFound(err))
+	assert.NoError(t, kube.Get(context.Background(), client.ObjectKeyFromObject(newRole), &rbacv1.Role{}))
+}
+
+func TestDeleteInventory(t *testing.T) {
+	resourceCRD := &apiextensionsv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: "orgs.github.ogen.krateo.io"}}
+	sa := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "orgs-controller", Namespace: "demo"}}
+
+	cr := &definitionv1alpha1.RestDefinition{
+		Spec: definitionv1alpha1.RestDefinitionSpec{
+			ResourceGroup: "github.ogen.krateo.io",
+			Resource:      definitionv1alpha1.Resource{Kind: "Org"},
+		},
+	}
+	cr.Status.Inventory = []definitionv1alpha1.InventoryEntry{
+		crdInventoryEntry(schema.GroupResource{Group: "github.ogen.krateo.io", Resource: "orgs"}),
+		{APIVersion: "v1", Kind: "ServiceAccount", Namespace: "demo", Name: sa.Name},
+	}
+
+	kube := newInventoryClient(t, resourceCRD, sa)
+	e := &external{kube: kube, log: logging.NewNopLogger()}
+
+	require.NoError(t, e.deleteInventory(context.Background(), cr, true))
+	err := kube.Get(context.Background(), client.ObjectKeyFromObject(resourceCRD), &apiextensionsv1.CustomResourceDefinition{})
+	assert.True(t, apierrors.IsNotFound(err))
+	assert.NoError(t, kube.Get(context.Background(), client.ObjectKeyFromObject(sa), &corev1.ServiceAccount{}))
+
+	require.NoError(t, e.deleteInventory(context.Background(), cr, false))
+	err = kube.Get(context.Background(), client.ObjectKeyFromObject(sa), &corev1.ServiceAccount{})
+	assert.True(t, apierrors.IsNotFound(err))
+}
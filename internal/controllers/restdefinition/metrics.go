@@ -0,0 +1,44 @@
+package restdefinition
+
+import (
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/cache"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// defaultOASCacheSize is the default size in bytes of the caches of the OAS documents (see OAS_CACHE_SIZE).
+const defaultOASCacheSize = 64 << 20
+
+// registerCacheMetrics registers the metrics reporting the usage of a cache of the OAS documents,
+// labelled with the name of the cache. The hit rate is hits / (hits + misses).
+func registerCacheMetrics(registry prometheus.Registerer, name string, stats func() cache.Stats) error {
+	labels := prometheus.Labels{"cache": name}
+	collectors := []prometheus.Collector{
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Name:        "oasgen_oas_cache_hits_total",
+			Help:        "Number of OAS documents found in the cache.",
+			ConstLabels: labels,
+		}, func() float64 { return float64(stats().Hits) }),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Name:        "oasgen_oas_cache_misses_total",
+			Help:        "Number of OAS documents not found in the cache.",
+			ConstLabels: labels,
+		}, func() float64 { return float64(stats().Misses) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name:        "oasgen_oas_cache_entries",
+			Help:        "Number of OAS documents in the cache.",
+			ConstLabels: labels,
+		}, func() float64 { return float64(stats().Entries) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name:        "oasgen_oas_cache_size_bytes",
+			Help:        "Total size of the OAS documents in the cache.",
+			ConstLabels: labels,
+		}, func() float64 { return float64(stats().Size) }),
+	}
+
+	for _, c := range collectors {
+		if err := registry.Register(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
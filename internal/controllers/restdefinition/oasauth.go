@@ -0,0 +1,80 @@
+package restdefinition
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	definitionv1alpha1 "github.com/krateoplatformops/oasgen-provider/apis/restdefinitions/v1alpha1"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/filegetter"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// getOASAuth returns the credentials used to download the OAS document of the RestDefinition, reading them from
+// Secrets in its namespace. It returns nil if the RestDefinition declares no credentials.
+func (e *external) getOASAuth(ctx context.Context, cr *definitionv1alpha1.RestDefinition) (*filegetter.AuthConfig, error) {
+	auth := cr.Spec.OASAuth
+	if auth == nil {
+		return nil, nil
+	}
+
+	switch {
+	case auth.Basic != nil:
+		username, err := e.getSecretKey(ctx, cr.Namespace, auth.Basic.UsernameRef)
+		if err != nil {
+			return nil, err
+		}
+		password, err := e.getSecretKey(ctx, cr.Namespace, auth.Basic.PasswordRef)
+		if err != nil {
+			return nil, err
+		}
+		return &filegetter.AuthConfig{Type: filegetter.BasicAuth, Username: username, Password: password}, nil
+	case auth.Bearer != nil:
+		token, err := e.getSecretKey(ctx, cr.Namespace, auth.Bearer.TokenRef)
+		if err != nil {
+			return nil, err
+		}
+		return &filegetter.AuthConfig{Type: filegetter.BearerToken, Token: token}, nil
+	case auth.Header != nil:
+		value, err := e.getSecretKey(ctx, cr.Namespace, auth.Header.ValueRef)
+		if err != nil {
+			return nil, err
+		}
+		return &filegetter.AuthConfig{Type: filegetter.HeaderAuth, HeaderName: auth.Header.Name, HeaderValue: value}, nil
+	}
+
+	return nil, fmt.Errorf("oasAuth must set one of basic, bearer and header")
+}
+
+// getSecretKey returns the value of a key of a Secret in the given namespace, without leading and trailing whitespace.
+func (e *external) getSecretKey(ctx context.Context, namespace string, ref definitionv1alpha1.SecretKeySelector) (string, error) {
+	secret := corev1.Secret{}
+	err := e.kube.Get(ctx, types.NamespacedName{Namespace: namespace, Name: ref.Name}, &secret)
+	if err != nil {
+		return "", fmt.Errorf("getting secret %s/%s: %w", namespace, ref.Name, err)
+	}
+
+	value, ok := secret.Data[ref.Key]
+	if !ok {
+		return "", fmt.Errorf("key %s not found in secret %s/%s", ref.Key, namespace, ref.Name)
+	}
+	// Values often end with a newline (e.g., when created from a file), which is not valid in a header
+	return strings.TrimSpace(string(value)), nil
+}
+
+// checkOASSecret checks that the OAS document of the RestDefinition, if stored in a Secret, is stored in its namespace:
+// as for the credentials, a RestDefinition cannot read Secrets in other namespaces.
+func checkOASSecret(cr *definitionv1alpha1.RestDefinition) error {
+	if !strings.HasPrefix(cr.Spec.OASPath, "secret://") {
+		return nil
+	}
+	namespace, _, _, err := filegetter.ParseSecretSource(cr.Spec.OASPath)
+	if err != nil {
+		return err
+	}
+	if namespace != cr.Namespace {
+		return fmt.Errorf("the OAS secret must be in the namespace of the RestDefinition (%s), got %s", cr.Namespace, namespace)
+	}
+	return nil
+}
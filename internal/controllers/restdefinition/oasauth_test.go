@@ -0,0 +1,129 @@
+package restdefinition
+
+import (
+	"context"
+	"testing"
+
+	definitionv1alpha1 "github.com/krateoplatformops/oasgen-provider/apis/restdefinitions/v1alpha1"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/filegetter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestGetOASAuth(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "oas-credentials", Namespace: "demo"},
+		Data: map[string][]byte{
+			"username": []byte("user"),
+			"password": []byte("pass"),
+			"token":    []byte("secret-token"),
+			"apikey":   []byte(" secret-key\n"),
+		},
+	}
+	ref := func(key string) definitionv1alpha1.SecretKeySelector {
+		return definitionv1alpha1.SecretKeySelector{Name: "oas-credentials", Key: key}
+	}
+
+	tests := []struct {
+		name        string
+		auth        *definitionv1alpha1.OASAuth
+		namespace   string
+		expected    *filegetter.AuthConfig
+		expectError bool
+	}{
+		{
+			name:      "no auth",
+			namespace: "demo",
+		},
+		{
+			name:      "basic",
+			auth:      &definitionv1alpha1.OASAuth{Basic: &definitionv1alpha1.OASBasicAuth{UsernameRef: ref("username"), PasswordRef: ref("password")}},
+			namespace: "demo",
+			expected:  &filegetter.AuthConfig{Type: filegetter.BasicAuth, Username: "user", Password: "pass"},
+		},
+		{
+			name:      "bearer",
+			auth:      &definitionv1alpha1.OASAuth{Bearer: &definitionv1alpha1.OASBearerAuth{TokenRef: ref("token")}},
+			namespace: "demo",
+			expected:  &filegetter.AuthConfig{Type: filegetter.BearerToken, Token: "secret-token"},
+		},
+		{
+			name:      "header",
+			auth:      &definitionv1alpha1.OASAuth{Header: &definitionv1alpha1.OASHeaderAuth{Name: "PRIVATE-TOKEN", ValueRef: ref("token")}},
+			namespace: "demo",
+			expected:  &filegetter.AuthConfig{Type: filegetter.HeaderAuth, HeaderName: "PRIVATE-TOKEN", HeaderValue: "secret-token"},
+		},
+		{
+			name:      "header with trailing newline",
+			auth:      &definitionv1alpha1.OASAuth{Header: &definitionv1alpha1.OASHeaderAuth{Name: "X-Api-Key", ValueRef: ref("apikey")}},
+			namespace: "demo",
+			expected:  &filegetter.AuthConfig{Type: filegetter.HeaderAuth, HeaderName: "X-Api-Key", HeaderValue: "secret-key"},
+		},
+		{
+			name:        "missing key",
+			auth:        &definitionv1alpha1.OASAuth{Bearer: &definitionv1alpha1.OASBearerAuth{TokenRef: ref("missing")}},
+			namespace:   "demo",
+			expectError: true,
+		},
+		{
+			name:        "secret in another namespace",
+			auth:        &definitionv1alpha1.OASAuth{Bearer: &definitionv1alpha1.OASBearerAuth{TokenRef: ref("token")}},
+			namespace:   "other",
+			expectError: true,
+		},
+	}
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	e := &external{kube: fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &definitionv1alpha1.RestDefinition{
+				ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: tt.namespace},
+				Spec:       definitionv1alpha1.RestDefinitionSpec{OASAuth: tt.auth},
+			}
+
+			got, err := e.getOASAuth(context.Background(), cr)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestCheckOASSecret(t *testing.T) {
+	tests := []struct {
+		name    string
+		oasPath string
+		wantErr bool
+	}{
+		{name: "URL", oasPath: "https://example.com/openapi.yaml"},
+		{name: "ConfigMap in another namespace", oasPath: "configmap://other/specs/openapi.yaml"},
+		{name: "Secret in the same namespace", oasPath: "secret://gh-system/specs/openapi.yaml"},
+		{name: "Secret in another namespace", oasPath: "secret://other/specs/openapi.yaml", wantErr: true},
+		{name: "Invalid Secret source", oasPath: "secret://gh-system/specs", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &definitionv1alpha1.RestDefinition{
+				ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "gh-system"},
+				Spec:       definitionv1alpha1.RestDefinitionSpec{OASPath: tt.oasPath},
+			}
+			err := checkOASSecret(cr)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
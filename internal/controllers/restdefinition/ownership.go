@@ -0,0 +1,109 @@
+package restdefinition
+
+import (
+	"context"
+	"fmt"
+
+	definitionv1alpha1 "github.com/krateoplatformops/oasgen-provider/apis/restdefinitions/v1alpha1"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/crd"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/deploy"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/plurals"
+	"github.com/krateoplatformops/provider-runtime/pkg/meta"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// ownerKey returns the value of the owner annotation of the CRDs generated for the RestDefinition.
+func ownerKey(cr *definitionv1alpha1.RestDefinition) string {
+	return types.NamespacedName{Namespace: cr.Namespace, Name: cr.Name}.String()
+}
+
+// setCRDOwner records the RestDefinition as the owner of the CRD.
+func setCRDOwner(obj *apiextensionsv1.CustomResourceDefinition, cr *definitionv1alpha1.RestDefinition) {
+	meta.AddAnnotations(obj, map[string]string{definitionv1alpha1.AnnotationKeyOwner: ownerKey(cr)})
+}
+
+// crdGroupResources returns the group resources of the CRDs that can be generated for the RestDefinition.
+func crdGroupResources(cr *definitionv1alpha1.RestDefinition) []schema.GroupResource {
+	return []schema.GroupResource{
+		plurals.ToGroupVersionResource(getResourceGVK(cr)).GroupResource(),
+		plurals.ToGroupVersionResource(getConfigurationGVK(cr)).GroupResource(),
+	}
+}
+
+// crdOwnership checks the owner of the installed CRDs of the RestDefinition. It returns the other RestDefinition
+// owning them, if any, and whether they are all owned by the RestDefinition already. CRDs with no owner (e.g.,
+// generated by previous releases of the provider) or owned by the RestDefinition named by the adoption annotation
+// are not owned by the RestDefinition yet, but can be claimed (see claimCRDs).
+func (e *external) crdOwnership(ctx context.Context, cr *definitionv1alpha1.RestDefinition) (foreign string, owned bool, err error) {
+	owned = true
+	for _, gr := range crdGroupResources(cr) {
+		installed, err := crd.Get(ctx, e.kube, gr)
+		if err != nil {
+			return "", false, fmt.Errorf("getting CRD %s: %w", gr.String(), err)
+		}
+		if installed == nil {
+			continue
+		}
+
+		owner := installed.GetAnnotations()[definitionv1alpha1.AnnotationKeyOwner]
+		switch owner {
+		case ownerKey(cr):
+		case "", cr.GetAnnotations()[definitionv1alpha1.AnnotationKeyAdoptFrom]:
+			owned = false
+		default:
+			return owner, false, nil
+		}
+	}
+	return "", owned, nil
+}
+
+// claimCRDs records the RestDefinition as the owner of its installed CRDs.
+func (e *external) claimCRDs(ctx context.Context, cr *definitionv1alpha1.RestDefinition) error {
+	for _, gr := range crdGroupResources(cr) {
+		installed, err := crd.Get(ctx, e.kube, gr)
+		if err != nil {
+			return fmt.Errorf("getting CRD %s: %w", gr.String(), err)
+		}
+		if installed == nil || installed.GetAnnotations()[definitionv1alpha1.AnnotationKeyOwner] == ownerKey(cr) {
+			continue
+		}
+
+		e.log.Debug("Claiming CRD", "CRD", installed.Name,
+			"previousOwner", installed.GetAnnotations()[definitionv1alpha1.AnnotationKeyOwner], "owner", ownerKey(cr))
+		setCRDOwner(installed, cr)
+		if err := e.kube.Update(ctx, installed); err != nil {
+			return fmt.Errorf("updating CRD %s: %w", installed.Name, err)
+		}
+	}
+	return nil
+}
+
+// releaseCRDs completes the deletion of a RestDefinition whose CRDs are owned by another RestDefinition: the CRDs,
+// their resources and the cluster-scoped RBAC resources (named after the resource) belong to the owner, so only the
+// namespaced objects recorded in the inventory are removed.
+func (e *external) releaseCRDs(ctx context.Context, cr *definitionv1alpha1.RestDefinition, owner string) error {
+	if meta.FinalizerExists(cr, restresourcesStillExistFinalizer) {
+		e.log.Debug("Removing finalizer from RestDefinition not owning its CRDs", "finalizer", restresourcesStillExistFinalizer, "owner", owner)
+		meta.RemoveFinalizer(cr, restresourcesStillExistFinalizer)
+		if err := e.kube.Update(ctx, cr); err != nil {
+			return fmt.Errorf("removing restresources finalizer: %w", err)
+		}
+	}
+
+	var namespaced []definitionv1alpha1.InventoryEntry
+	for _, entry := range cr.Status.Inventory {
+		if !isCRD(entry) && entry.Namespace != "" {
+			namespaced = append(namespaced, entry)
+		}
+	}
+	if err := deploy.UndeployReferences(ctx, e.kube, toReferences(namespaced), e.log.Debug); err != nil {
+		return fmt.Errorf("uninstalling inventory: %w", err)
+	}
+
+	e.fetches.forget(cr)
+	cr.SetConditions(definitionv1alpha1.ResourcesOrphaned().
+		WithMessage(fmt.Sprintf("CRDs are owned by RestDefinition '%s'", owner)))
+	return nil
+}
@@ -0,0 +1,78 @@
+package restdefinition
+
+import (
+	"context"
+	"testing"
+
+	definitionv1alpha1 "github.com/krateoplatformops/oasgen-provider/apis/restdefinitions/v1alpha1"
+	"github.com/krateoplatformops/provider-runtime/pkg/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestCRDOwnership(t *testing.T) {
+	withOwner := func(owner string) *apiextensionsv1.CustomResourceDefinition {
+		res := &apiextensionsv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: "orgs.github.ogen.krateo.io"}}
+		if owner != "" {
+			res.SetAnnotations(map[string]string{definitionv1alpha1.AnnotationKeyOwner: owner})
+		}
+		return res
+	}
+
+	tests := []struct {
+		name      string
+		installed []client.Object
+		adoptFrom string
+		foreign   string
+		owned     bool
+	}{
+		{name: "not installed", owned: true},
+		{name: "owned", installed: []client.Object{withOwner("team-a/org")}, owned: true},
+		{name: "no owner", installed: []client.Object{withOwner("")}, owned: false},
+		{name: "owned by another", installed: []client.Object{withOwner("team-b/org")}, foreign: "team-b/org"},
+		{name: "adopted", installed: []client.Object{withOwner("team-b/org")}, adoptFrom: "team-b/org", owned: false},
+		{name: "adopted from another", installed: []client.Object{withOwner("team-b/org")}, adoptFrom: "team-c/org", foreign: "team-b/org"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			require.NoError(t, apiextensionsv1.AddToScheme(scheme))
+
+			cr := &definitionv1alpha1.RestDefinition{
+				ObjectMeta: metav1.ObjectMeta{Name: "org", Namespace: "team-a"},
+				Spec: definitionv1alpha1.RestDefinitionSpec{
+					ResourceGroup: "github.ogen.krateo.io",
+					Resource:      definitionv1alpha1.Resource{Kind: "Org"},
+				},
+			}
+			if tt.adoptFrom != "" {
+				cr.SetAnnotations(map[string]string{definitionv1alpha1.AnnotationKeyAdoptFrom: tt.adoptFrom})
+			}
+
+			e := &external{
+				kube: fake.NewClientBuilder().WithScheme(scheme).WithObjects(tt.installed...).Build(),
+				log:  logging.NewNopLogger(),
+			}
+			foreign, owned, err := e.crdOwnership(context.Background(), cr)
+			require.NoError(t, err)
+			assert.Equal(t, tt.foreign, foreign)
+			assert.Equal(t, tt.owned, owned)
+
+			if tt.foreign != "" {
+				return
+			}
+
+			require.NoError(t, e.claimCRDs(context.Background(), cr))
+			foreign, owned, err = e.crdOwnership(context.Background(), cr)
+			require.NoError(t, err)
+			assert.Empty(t, foreign)
+			assert.True(t, owned)
+		})
+	}
+}
@@ -9,7 +9,6 @@ import (
 
 	"os"
 	"path"
-	"strings"
 
 	appsv1 "k8s.io/api/apps/v1"
 
@@ -23,19 +22,21 @@ import (
 	"github.com/krateoplatformops/provider-runtime/pkg/meta"
 	"github.com/krateoplatformops/provider-runtime/pkg/ratelimiter"
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 
 	"github.com/krateoplatformops/oasgen-provider/internal/tools/crd"
 	"github.com/krateoplatformops/oasgen-provider/internal/tools/deploy"
 	"github.com/krateoplatformops/oasgen-provider/internal/tools/deployment"
 	"github.com/krateoplatformops/oasgen-provider/internal/tools/filegetter"
-	"github.com/krateoplatformops/oasgen-provider/internal/tools/kube"
 	"github.com/krateoplatformops/oasgen-provider/internal/tools/oas2jsonschema"
 	"github.com/krateoplatformops/oasgen-provider/internal/tools/objects"
 	"github.com/krateoplatformops/oasgen-provider/internal/tools/plurals"
@@ -43,7 +44,6 @@ import (
 	"github.com/krateoplatformops/provider-runtime/pkg/resource"
 
 	"github.com/krateoplatformops/oasgen-provider/internal/tools/text"
-	"github.com/krateoplatformops/plumbing/crdgen"
 )
 
 const (
@@ -53,7 +53,8 @@ const (
 
 const (
 	errNotRestDefinition = "managed resource is not a RestDefinition"
-	resourceVersion      = "v1alpha1"
+	// defaultResourceVersion is the API version of the generated CRDs when the RestDefinition does not declare one
+	defaultResourceVersion = "v1alpha1"
 
 	restresourcesStillExistFinalizer = "composition.krateo.io/restresources-still-exist-finalizer"
 )
@@ -82,14 +83,55 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		return fmt.Errorf("failed to create discovery client: %w", err)
 	}
 
+	conversionConf, err := conversionWebhookConf()
+	if err != nil {
+		return fmt.Errorf("failed to configure conversion webhook: %w", err)
+	}
+	if conversionConf != nil {
+		if err := setupConversionWebhook(mgr, log.Debug); err != nil {
+			return fmt.Errorf("failed to configure conversion webhook: %w", err)
+		}
+	}
+
+	// OAS_REFETCH_INTERVAL limits how often Observe fetches the OAS documents (0 means on every reconcile)
+	fetches := newOASFetches(env.Duration("OAS_REFETCH_INTERVAL", defaultOASRefetchInterval))
+
+	// OAS_CACHE_SIZE bounds the size in bytes of the documents kept by the process-wide caches of the parsed OAS
+	// documents and of the files downloaded over HTTP, shared by all RestDefinitions (0 disables the caches)
+	cacheSize := int64(env.Int("OAS_CACHE_SIZE", defaultOASCacheSize))
+	parser := oas2jsonschema.NewCachedParser(oas2jsonschema.NewLibOASParser(), cacheSize)
+	files := filegetter.NewHTTPCache(cacheSize)
+	err = registerCacheMetrics(metrics.Registry, "documents", parser.Stats)
+	if err != nil {
+		return fmt.Errorf("failed to register OAS cache metrics: %w", err)
+	}
+	err = registerCacheMetrics(metrics.Registry, "http", files.Stats)
+	if err != nil {
+		return fmt.Errorf("failed to register OAS cache metrics: %w", err)
+	}
+
+	// RestDefinitions are indexed by the ConfigMap or Secret holding their OAS document, so that an edit of the document
+	// triggers their reconciliation without waiting for the next poll
+	err = mgr.GetFieldIndexer().IndexField(context.Background(), &definitionv1alpha1.RestDefinition{}, oasConfigMapIndex, indexOASConfigMap)
+	if err != nil {
+		return fmt.Errorf("failed to index RestDefinitions by OAS ConfigMap: %w", err)
+	}
+	err = mgr.GetFieldIndexer().IndexField(context.Background(), &definitionv1alpha1.RestDefinition{}, oasSecretIndex, indexOASSecret)
+	if err != nil {
+		return fmt.Errorf("failed to index RestDefinitions by OAS Secret: %w", err)
+	}
+
 	r := reconciler.NewReconciler(mgr,
 		resource.ManagedKind(definitionv1alpha1.RestDefinitionGroupVersionKind),
 		reconciler.WithExternalConnecter(&connector{
-			kube:     cli,
-			log:      log,
-			recorder: recorder,
-			disc:     discovery,
-			parser:   oas2jsonschema.NewLibOASParser(),
+			kube:       cli,
+			log:        log,
+			recorder:   recorder,
+			disc:       discovery,
+			parser:     parser,
+			files:      files,
+			conversion: conversionConf,
+			fetches:    fetches,
 		}),
 		reconciler.WithTimeout(reconcileTimeout),
 		reconciler.WithCreationGracePeriod(reconcileGracePeriod),
@@ -101,6 +143,12 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		Named(name).
 		WithOptions(o.ForControllerRuntime()).
 		For(&definitionv1alpha1.RestDefinition{}).
+		Watches(&corev1.ConfigMap{},
+			handler.EnqueueRequestsFromMapFunc(restDefinitionsForObject(mgr.GetClient(), oasConfigMapIndex, log.Debug)),
+			builder.OnlyMetadata).
+		Watches(&corev1.Secret{},
+			handler.EnqueueRequestsFromMapFunc(restDefinitionsForObject(mgr.GetClient(), oasSecretIndex, log.Debug)),
+			builder.OnlyMetadata).
 		Complete(ratelimiter.New(name, r, o.GlobalRateLimiter))
 }
 
@@ -110,6 +158,11 @@ type connector struct {
 	recorder record.EventRecorder
 	disc     discovery.DiscoveryInterface
 	parser   oas2jsonschema.Parser
+	// files caches the OAS files downloaded over HTTP
+	files *filegetter.HTTPCache
+	// conversion is the conversion configuration of the generated CRDs serving multiple versions (nil if the webhook is disabled)
+	conversion *apiextensionsv1.CustomResourceConversion
+	fetches    *oasFetches
 }
 
 func (c *connector) Connect(ctx context.Context, mg resource.Managed) (reconciler.ExternalClient, error) {
@@ -124,22 +177,28 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (reconcile
 	log := c.log.WithValues("name", cr.Name, "namespace", cr.Namespace)
 
 	return &external{
-		kube:   c.kube,
-		log:    log,
-		rec:    c.recorder,
-		disc:   c.disc,
-		parser: c.parser,
+		kube:       c.kube,
+		log:        log,
+		rec:        c.recorder,
+		disc:       c.disc,
+		parser:     c.parser,
+		files:      c.files,
+		conversion: c.conversion,
+		fetches:    c.fetches,
 	}, nil
 }
 
 // An ExternalClient observes, then either creates, updates, or deletes an
 // external resource to ensure it reflects the managed resource's desired state.
 type external struct {
-	kube   client.Client
-	log    logging.Logger
-	rec    record.EventRecorder
-	disc   discovery.DiscoveryInterface
-	parser oas2jsonschema.Parser
+	kube       client.Client
+	log        logging.Logger
+	rec        record.EventRecorder
+	disc       discovery.DiscoveryInterface
+	parser     oas2jsonschema.Parser
+	files      *filegetter.HTTPCache
+	conversion *apiextensionsv1.CustomResourceConversion
+	fetches    *oasFetches
 }
 
 func (e *external) Observe(ctx context.Context, mg resource.Managed) (reconciler.ExternalObservation, error) {
@@ -148,13 +207,24 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (reconciler
 		return reconciler.ExternalObservation{}, errors.New(errNotRestDefinition)
 	}
 
-	gvk := schema.GroupVersionKind{
-		Group:   cr.Spec.ResourceGroup,
-		Version: resourceVersion,
-		Kind:    text.CapitaliseFirstLetter(cr.Spec.Resource.Kind),
+	gvk := getResourceGVK(cr)
+
+	// CRDs are cluster-scoped, so RestDefinitions in different namespaces may generate the same ones:
+	// only the RestDefinition owning them manages them
+	foreignOwner, crdsOwned, err := e.crdOwnership(ctx, cr)
+	if err != nil {
+		return reconciler.ExternalObservation{}, fmt.Errorf("checking CRD ownership: %w", err)
 	}
 
 	if meta.WasDeleted(cr) {
+		if foreignOwner != "" {
+			e.log.Debug("RestDefinition was deleted, CRDs are owned by another RestDefinition", "owner", foreignOwner)
+			return reconciler.ExternalObservation{
+				ResourceExists:   false,
+				ResourceUpToDate: true,
+			}, e.releaseCRDs(ctx, cr, foreignOwner)
+		}
+
 		e.log.Debug("RestDefinition was deleted, skipping observation")
 		err := manageFinalizers(ctx, e.kube, cr, e.log.Debug)
 		if err != nil {
@@ -166,24 +236,42 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (reconciler
 		}, e.Delete(ctx, cr)
 	}
 
-	// Read hasSecuritySchemes from status (saved by Create/Update) to avoid
-	// re-fetching and parsing the OAS document on every Observe cycle.
-	// If the status field is not yet set (nil), fetch the OAS document once to
-	// determine the correct value. This only happens on the first Observe before
-	// Create has had a chance to populate the status field.
-	var hasSecuritySchemes bool
-	if cr.Status.HasSecuritySchemes != nil {
-		hasSecuritySchemes = *cr.Status.HasSecuritySchemes
-		e.log.Debug("Using saved HasSecuritySchemes from status", "HasSecuritySchemes", hasSecuritySchemes)
-	} else {
-		hasSecuritySchemes = true // Safe default to true
-		doc, err := e.getDocumentModelFromCR(ctx, cr)
+	if foreignOwner != "" {
+		msg := fmt.Sprintf("CRDs of '%s' are owned by RestDefinition '%s'. Set the '%s' annotation to '%s' to adopt them",
+			gvk.GroupKind().String(), foreignOwner, definitionv1alpha1.AnnotationKeyAdoptFrom, foreignOwner)
+		e.log.Debug("CRDs owned by another RestDefinition, skipping observation", "owner", foreignOwner)
+		if cr.GetCondition(definitionv1alpha1.TypeCRDsOwned).Reason != definitionv1alpha1.ReasonOwnedByAnotherRestDefinition {
+			e.rec.Eventf(cr, corev1.EventTypeWarning, string(definitionv1alpha1.ReasonOwnedByAnotherRestDefinition), msg)
+		}
+		cr.SetConditions(rtv1.Unavailable().WithMessage(msg), definitionv1alpha1.CRDsOwnedByAnother().WithMessage(msg))
+		return reconciler.ExternalObservation{
+			ResourceExists:   true,
+			ResourceUpToDate: true,
+		}, nil
+	}
+	cr.SetConditions(definitionv1alpha1.CRDsOwned())
+
+	// The OAS document is fetched again once the re-fetch interval has elapsed, to detect changes in the
+	// document and in the schemas generated from it.
+	var doc oas2jsonschema.OASDocument
+	var oasDigest string
+	if e.fetches.due(cr, time.Now()) {
+		doc, oasDigest, err = e.getDocumentModelFromCR(ctx, cr)
 		if err != nil {
-			e.log.Debug("Failed to get document model from CR, defaulting HasSecuritySchemes to true", "error", err)
-		} else {
-			hasSecuritySchemes = doc.SecuritySchemes() != nil && len(doc.SecuritySchemes()) > 0
+			e.log.Debug("Failed to get document model from CR, skipping CRD schema comparison", "error", err)
 		}
-		e.log.Debug("HasSecuritySchemes not yet saved in status, resolved from OAS document (or defaulted to true if failed to get document model from CR)", "HasSecuritySchemes", hasSecuritySchemes)
+	} else {
+		e.log.Debug("OAS document fetched recently, skipping CRD schema comparison")
+	}
+
+	// Resolve hasSecuritySchemes from the OAS document. If the document cannot be fetched, use the value
+	// saved in status by Create/Update, or default to true if the status field is not yet set.
+	hasSecuritySchemes := true
+	if doc != nil {
+		hasSecuritySchemes = doc.SecuritySchemes() != nil && len(doc.SecuritySchemes()) > 0
+	} else if cr.Status.HasSecuritySchemes != nil {
+		hasSecuritySchemes = *cr.Status.HasSecuritySchemes
+		e.log.Debug("Using saved HasSecuritySchemes from status", "HasSecuritySchemes", hasSecuritySchemes)
 	}
 
 	configurationGVR := getConfigurationGVR(cr, hasSecuritySchemes)
@@ -212,6 +300,58 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (reconciler
 			ResourceUpToDate: true,
 		}, nil
 	}
+	if !crdsOwned {
+		e.log.Debug("CRDs not owned by the RestDefinition yet", "gvr", gvr.String())
+		return reconciler.ExternalObservation{
+			ResourceExists:   true,
+			ResourceUpToDate: false,
+		}, nil
+	}
+	if doc != nil {
+		// The CRDs are generated once, both to compare them with the installed ones and to compute their digest
+		crdu, cfgCRDU, err := e.generateCRDs(cr, doc, hasSecuritySchemes)
+		if err != nil {
+			return reconciler.ExternalObservation{}, fmt.Errorf("generating CRDs: %w", err)
+		}
+		drifts, err := e.schemaDrifts(ctx, cr, crdu, cfgCRDU)
+		if err != nil {
+			return reconciler.ExternalObservation{}, fmt.Errorf("comparing CRD schemas: %w", err)
+		}
+		if e.observeSchemaDrifts(cr, drifts) {
+			e.log.Debug("CRD schemas generated from the OAS changed", "gvr", gvr.String())
+			return reconciler.ExternalObservation{
+				ResourceExists:   true,
+				ResourceUpToDate: false,
+			}, nil
+		}
+
+		generatedDigest, err := schemaDigest(crdu, cfgCRDU)
+		if err != nil {
+			return reconciler.ExternalObservation{}, fmt.Errorf("computing schema digest: %w", err)
+		}
+		if cr.Status.OASDigest != oasDigest || cr.Status.SchemaDigest != generatedDigest {
+			e.log.Debug("OAS document digest changed",
+				"status", cr.Status.OASDigest, "fetched", oasDigest,
+				"statusSchema", cr.Status.SchemaDigest, "generatedSchema", generatedDigest)
+			return reconciler.ExternalObservation{
+				ResourceExists:   true,
+				ResourceUpToDate: false,
+			}, nil
+		}
+	}
+
+	conversionDrifts, err := e.conversionDrifts(ctx, gvr, configurationGVR)
+	if err != nil {
+		return reconciler.ExternalObservation{}, fmt.Errorf("comparing CRD conversions: %w", err)
+	}
+	if len(conversionDrifts) > 0 {
+		e.log.Debug("CRD conversion configuration changed", "gvr", gvr.String())
+		return reconciler.ExternalObservation{
+			ResourceExists:   true,
+			ResourceUpToDate: false,
+		}, nil
+	}
+
 	e.log.Debug("Searching for Dynamic Controller", "gvr", gvr.String())
 
 	deploymentNSName := types.NamespacedName{
@@ -271,9 +411,10 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (reconciler
 		GVR:          gvr,
 		Log:          e.log.Debug,
 		DryRunServer: true,
+		Scope:        string(getResourceScope(cr)),
 	}
 
-	dig, err := deploy.Deploy(ctx, e.kube, opts)
+	dig, _, err := deploy.Deploy(ctx, e.kube, opts)
 	if err != nil {
 		return reconciler.ExternalObservation{}, err
 	}
@@ -323,7 +464,7 @@ func (e *external) Create(ctx context.Context, mg resource.Managed) error {
 
 	e.log.Info("Creating RestDefinition", "Kind:", cr.Spec.Resource.Kind, "Group:", cr.Spec.ResourceGroup)
 
-	doc, err := e.getDocumentModelFromCR(ctx, cr)
+	doc, oasDigest, err := e.getDocumentModelFromCR(ctx, cr)
 	if err != nil {
 		return fmt.Errorf("getting document model from CR: %w", err)
 	}
@@ -340,160 +481,52 @@ func (e *external) Create(ctx context.Context, mg resource.Managed) error {
 		e.log.Debug("No security schemes found in OAS document")
 	}
 
-	gvk := schema.GroupVersionKind{
-		Group:   cr.Spec.ResourceGroup,
-		Version: resourceVersion,
-		Kind:    text.CapitaliseFirstLetter(cr.Spec.Resource.Kind),
-	}
+	gvk := getResourceGVK(cr)
 	gvr := plurals.ToGroupVersionResource(gvk)
 
+	// The CRDs are generated once, to install them or to compute the digest of their schemas
+	crdu, cfgCRDU, err := e.generateCRDs(cr, doc, hasSecuritySchemes)
+	if err != nil {
+		return err
+	}
+
 	crdOk, err := crd.Lookup(ctx, e.kube, gvr)
 	if err != nil {
 		return err
 	}
 
 	if !crdOk {
-		// Shim needed to convert definitionv1alpha1.VerbsDescription to oas2jsonschema.Verbs
-		// Verbs is a type defined within the oas2jsonschema package
-		// and so it's not tied with the RestDefinition CRD
-		verbs := make([]oas2jsonschema.Verb, len(cr.Spec.Resource.VerbsDescription))
-		for i, v := range cr.Spec.Resource.VerbsDescription {
-			verbs[i] = oas2jsonschema.Verb{
-				Action: v.Action,
-				Method: v.Method,
-				Path:   v.Path,
-			}
-		}
-
-		// Shim needed to convert definitionv1alpha1.ConfigurationFields to oas2jsonschema.ConfigurationFields
-		configurationFields := make([]oas2jsonschema.ConfigurationField, 0, len(cr.Spec.Resource.ConfigurationFields))
-		for _, v := range cr.Spec.Resource.ConfigurationFields {
-			actions, err := expandWildcardActions(v.FromRestDefinition.Actions, cr.Spec.Resource.VerbsDescription)
-			if err != nil {
-				return fmt.Errorf("expanding wildcard for actions in configurationFields: %w", err)
-			}
-
-			configurationFields = append(configurationFields, oas2jsonschema.ConfigurationField{
-				FromOpenAPI: oas2jsonschema.FromOpenAPI{
-					Name: v.FromOpenAPI.Name,
-					In:   v.FromOpenAPI.In,
-				},
-				FromRestDefinition: oas2jsonschema.FromRestDefinition{
-					Actions: actions,
-				},
-			})
-		}
-
-		// Create the resource configuration for the OAS schema generator
-		// We pass only relevant fields from the RestDefinition needed for schema generation
-		resourceConfig := &oas2jsonschema.ResourceConfig{
-			Verbs:                  verbs,
-			Identifiers:            cr.Spec.Resource.Identifiers,
-			AdditionalStatusFields: cr.Spec.Resource.AdditionalStatusFields,
-			ConfigurationFields:    configurationFields,
-			ExcludedSpecFields:     cr.Spec.Resource.ExcludedSpecFields,
-		}
-
-		// Create the OAS schema generator
-		generator := oas2jsonschema.NewOASSchemaGenerator(
-			doc,
-			oas2jsonschema.DefaultGeneratorConfig(),
-			resourceConfig,
-		)
-
-		result, err := generator.Generate()
-		if err != nil {
-			// Fatal error, we cannot continue
-			return fmt.Errorf("generating schemas: %w", err)
-		}
-		if len(result.GenerationWarnings) > 0 {
-			e.log.Debug("Some schema generation warnings were found, below the list")
-			for _, er := range result.GenerationWarnings {
-				e.log.Debug("Schema generation warning", "Warning", er)
-			}
-		}
-		if len(result.ValidationWarnings) > 0 {
-			e.log.Debug("Some schema validation warnings were found, below the list")
-			for _, er := range result.ValidationWarnings {
-				e.log.Debug("Schema validation warning", "Warning", er)
-			}
-		}
-
-		e.log.Debug("Generating CRD for", "Kind:", cr.Spec.Resource.Kind, "Group:", cr.Spec.ResourceGroup)
-
-		opts := crdgen.Options{
-			Group:        gvk.Group,
-			Version:      gvk.Version,
-			Kind:         gvk.Kind,
-			Categories:   []string{strings.ToLower(cr.Spec.Resource.Kind), "restresources", "rr"},
-			SpecSchema:   result.SpecSchema,
-			StatusSchema: result.StatusSchema,
-			Managed:      true,
-		}
-
-		res, err := crdgen.Generate(opts)
-		if err != nil {
-			return fmt.Errorf("generating CRD: %w", err)
-		}
-
-		crdu, err := crd.Unmarshal(res)
-		if err != nil {
-			return fmt.Errorf("unmarshalling CRD: %w", err)
-		}
-
-		e.log.Debug("Applying CRD for", "Kind:", cr.Spec.Resource.Kind, "Group:", cr.Spec.ResourceGroup)
-		err = kube.Apply(ctx, e.kube, crdu, kube.ApplyOptions{})
+		e.log.Debug("Applying CRD for", "Kind:", cr.Spec.Resource.Kind, "Group:", cr.Spec.ResourceGroup, "Version:", gvk.Version)
+		err = e.installCRD(ctx, crdu)
 		if err != nil {
 			return fmt.Errorf("installing CRD: %w", err)
 		}
 
-		// Only generate Configuration CRD if configuration fields are defined or if security schemes are defined
-		if len(configurationFields) > 0 || hasSecuritySchemes {
-			e.log.Debug("Configuration fields or security schemes defined, generating Configuration CRD")
-			e.log.Debug("Configuration fields length", "Length: ", len(configurationFields))
-			e.log.Debug("Has security schemes: ", "HasSecuritySchemes", hasSecuritySchemes)
-
-			cfgGVK := schema.GroupVersionKind{
-				Group:   cr.Spec.ResourceGroup,
-				Version: resourceVersion,
-				Kind:    text.CapitaliseFirstLetter(cr.Spec.Resource.Kind) + "Configuration",
-			}
-
-			e.log.Debug("Generating Configuration CRD", "Kind", cfgGVK.Kind, "Group", cfgGVK.Group)
-
-			e.log.Debug("Configuration Schema", "Schema", string(result.ConfigurationSchema))
-
-			cfgOpts := crdgen.Options{
-				Group:      cfgGVK.Group,
-				Version:    cfgGVK.Version,
-				Kind:       cfgGVK.Kind,
-				Categories: []string{strings.ToLower(cr.Spec.Resource.Kind), "restconfigs", "rc"},
-				SpecSchema: result.ConfigurationSchema,
-				Managed:    false,
-			}
-
-			cfgResource, err := crdgen.Generate(cfgOpts)
-			if err != nil {
-				return fmt.Errorf("generating configuration CRD: %w", err)
-			}
-
-			cfgCRDU, err := crd.Unmarshal(cfgResource)
-			if err != nil {
-				return fmt.Errorf("unmarshalling configuration CRD: %w", err)
-			}
-
-			e.log.Debug("Applying Configuration CRD", "Kind", cfgGVK.Kind, "Group", cfgGVK.Group)
-			err = kube.Apply(ctx, e.kube, cfgCRDU, kube.ApplyOptions{})
+		// Configuration CRD is generated only if configuration fields or security schemes are defined
+		if cfgCRDU != nil {
+			e.log.Debug("Applying Configuration CRD", "Kind", cfgCRDU.Spec.Names.Kind, "Group", cfgCRDU.Spec.Group)
+			err = e.installCRD(ctx, cfgCRDU)
 			if err != nil {
 				return fmt.Errorf("installing configuration CRD: %w", err)
 			}
-			e.log.Debug("Applied Configuration CRD", "Kind", cfgGVK.Kind, "Group", cfgGVK.Group)
+			e.log.Debug("Applied Configuration CRD", "Kind", cfgCRDU.Spec.Names.Kind, "Group", cfgCRDU.Spec.Group)
 		} else {
 			e.log.Debug("No configuration fields defined (No authentication or configurationFields specified), skipping Configuration CRD generation")
 		}
 
+		cr.Status.Inventory = mergeInventory(cr.Status.Inventory, crdInventoryEntry(gvr.GroupResource()))
+		if cfgCRDU != nil {
+			cr.Status.Inventory = mergeInventory(cr.Status.Inventory,
+				crdInventoryEntry(schema.GroupResource{Group: cfgCRDU.Spec.Group, Resource: cfgCRDU.Spec.Names.Plural}))
+		}
+
 		cr.SetConditions(rtv1.Creating())
 		cr.Status.HasSecuritySchemes = &hasSecuritySchemes
+		cr.Status.OASDigest = oasDigest
+		cr.Status.SchemaDigest, err = schemaDigest(crdu, cfgCRDU)
+		if err != nil {
+			return fmt.Errorf("computing schema digest: %w", err)
+		}
 		err = e.kube.Status().Update(ctx, cr)
 		if err != nil {
 			return fmt.Errorf("updating status: %w", err)
@@ -505,7 +538,20 @@ func (e *external) Create(ctx context.Context, mg resource.Managed) error {
 		return nil
 	}
 
+	err = e.claimCRDs(ctx, cr)
+	if err != nil {
+		return fmt.Errorf("claiming CRDs: %w", err)
+	}
+
 	configurationGVR := getConfigurationGVR(cr, hasSecuritySchemes)
+	// With an inventory, the controller of the previous version is removed once the new one is deployed (see updateInventory)
+	if !hasControllerInventory(cr.Status.Inventory) {
+		err = e.undeployPreviousVersion(ctx, cr, gvr, configurationGVR)
+		if err != nil {
+			return fmt.Errorf("uninstalling controller of the previous version: %w", err)
+		}
+	}
+
 	opts := deploy.DeployOptions{
 		ConfigurationGVR:       configurationGVR,
 		RBACFolderPath:         RDCrbacConfigFolder,
@@ -516,13 +562,18 @@ func (e *external) Create(ctx context.Context, mg resource.Managed) error {
 			Namespace: cr.Namespace,
 			Name:      cr.Name,
 		},
-		GVR: gvr,
-		Log: e.log.Debug,
+		GVR:   gvr,
+		Log:   e.log.Debug,
+		Scope: string(getResourceScope(cr)),
 	}
-	dig, err := deploy.Deploy(ctx, e.kube, opts)
+	dig, applied, err := deploy.Deploy(ctx, e.kube, opts)
 	if err != nil {
 		return fmt.Errorf("installing controller: %w", err)
 	}
+	err = e.updateInventory(ctx, cr, append(crdInventoryEntries(gvr, configurationGVR), toInventory(applied)...))
+	if err != nil {
+		return fmt.Errorf("updating inventory: %w", err)
+	}
 
 	cr.SetConditions(rtv1.Creating())
 	cr.Status.Resource = definitionv1alpha1.KindApiVersion{
@@ -541,6 +592,11 @@ func (e *external) Create(ctx context.Context, mg resource.Managed) error {
 	cr.Status.OASPath = cr.Spec.OASPath
 	cr.Status.Digest = dig
 	cr.Status.HasSecuritySchemes = &hasSecuritySchemes
+	cr.Status.OASDigest = oasDigest
+	cr.Status.SchemaDigest, err = schemaDigest(crdu, cfgCRDU)
+	if err != nil {
+		return fmt.Errorf("computing schema digest: %w", err)
+	}
 
 	err = e.kube.Status().Update(ctx, cr)
 	if err != nil {
@@ -559,7 +615,7 @@ func (e *external) Update(ctx context.Context, mg resource.Managed) error {
 		return errors.New(errNotRestDefinition)
 	}
 
-	doc, err := e.getDocumentModelFromCR(ctx, cr)
+	doc, oasDigest, err := e.getDocumentModelFromCR(ctx, cr)
 	if err != nil {
 		return fmt.Errorf("getting document model from CR: %w", err)
 	}
@@ -573,14 +629,49 @@ func (e *external) Update(ctx context.Context, mg resource.Managed) error {
 
 	e.log.Info("Updating RestDefinition", "Kind:", cr.Spec.Resource.Kind, "Group:", cr.Spec.ResourceGroup)
 
-	gvk := schema.GroupVersionKind{
-		Group:   cr.Spec.ResourceGroup,
-		Version: resourceVersion,
-		Kind:    text.CapitaliseFirstLetter(cr.Spec.Resource.Kind),
+	// Apply the changes of the schemas generated from the OAS to the installed CRDs, as allowed by the breaking change policy
+	crdu, cfgCRDU, err := e.generateCRDs(cr, doc, hasSecuritySchemes)
+	if err != nil {
+		return fmt.Errorf("generating CRDs: %w", err)
+	}
+	drifts, err := e.schemaDrifts(ctx, cr, crdu, cfgCRDU)
+	if err != nil {
+		return fmt.Errorf("comparing CRD schemas: %w", err)
+	}
+	err = e.applySchemaDrifts(ctx, cr, drifts)
+	if err != nil {
+		return fmt.Errorf("updating CRD schemas: %w", err)
 	}
+
+	gvk := getResourceGVK(cr)
 	gvr := plurals.ToGroupVersionResource(gvk)
 
 	configurationGVR := getConfigurationGVR(cr, hasSecuritySchemes)
+	conversionDrifts, err := e.conversionDrifts(ctx, gvr, configurationGVR)
+	if err != nil {
+		return fmt.Errorf("comparing CRD conversions: %w", err)
+	}
+	for _, drift := range conversionDrifts {
+		e.log.Debug("Updating CRD conversion", "CRD", drift.Name, "Strategy", drift.Spec.Conversion.Strategy)
+		if err := e.kube.Update(ctx, drift); err != nil {
+			return fmt.Errorf("updating CRD %s: %w", drift.Name, err)
+		}
+	}
+
+	// CRDs with no owner, or adopted from another RestDefinition, are claimed by this one
+	err = e.claimCRDs(ctx, cr)
+	if err != nil {
+		return fmt.Errorf("claiming CRDs: %w", err)
+	}
+
+	// With an inventory, the controller of the previous version is removed once the new one is deployed (see updateInventory)
+	if !hasControllerInventory(cr.Status.Inventory) {
+		err = e.undeployPreviousVersion(ctx, cr, gvr, configurationGVR)
+		if err != nil {
+			return fmt.Errorf("uninstalling controller of the previous version: %w", err)
+		}
+	}
+
 	opts := deploy.DeployOptions{
 		ConfigurationGVR:       configurationGVR,
 		RBACFolderPath:         RDCrbacConfigFolder,
@@ -591,13 +682,18 @@ func (e *external) Update(ctx context.Context, mg resource.Managed) error {
 			Namespace: cr.Namespace,
 			Name:      cr.Name,
 		},
-		GVR: gvr,
-		Log: e.log.Debug,
+		GVR:   gvr,
+		Log:   e.log.Debug,
+		Scope: string(getResourceScope(cr)),
 	}
-	dig, err := deploy.Deploy(ctx, e.kube, opts)
+	dig, applied, err := deploy.Deploy(ctx, e.kube, opts)
 	if err != nil {
 		return fmt.Errorf("installing controller: %w", err)
 	}
+	err = e.updateInventory(ctx, cr, append(crdInventoryEntries(gvr, configurationGVR), toInventory(applied)...))
+	if err != nil {
+		return fmt.Errorf("updating inventory: %w", err)
+	}
 
 	cr.SetConditions(rtv1.Creating())
 	cr.Status.Resource = definitionv1alpha1.KindApiVersion{
@@ -616,6 +712,11 @@ func (e *external) Update(ctx context.Context, mg resource.Managed) error {
 	cr.Status.OASPath = cr.Spec.OASPath
 	cr.Status.Digest = dig
 	cr.Status.HasSecuritySchemes = &hasSecuritySchemes
+	cr.Status.OASDigest = oasDigest
+	cr.Status.SchemaDigest, err = schemaDigest(crdu, cfgCRDU)
+	if err != nil {
+		return fmt.Errorf("computing schema digest: %w", err)
+	}
 
 	err = e.kube.Status().Update(ctx, cr)
 	if err != nil {
@@ -634,11 +735,100 @@ func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
 		return errors.New(errNotRestDefinition)
 	}
 
+	if !meta.IsActionAllowed(cr, meta.ActionDelete) {
+		e.log.Debug("External resource should not be deleted by provider, skip deleting.")
+		return nil
+	}
+
+	e.log.Info("Deleting RestDefinition", "Kind:", cr.Spec.Resource.Kind, "Group:", cr.Spec.ResourceGroup)
+
+	gvr := plurals.ToGroupVersionResource(getResourceGVK(cr))
+
+	switch getDeletionPolicy(cr) {
+	case definitionv1alpha1.DeletionPolicyOrphan:
+		// The CRDs and the resources are kept, only the dynamic controller is removed
+		if hasControllerInventory(cr.Status.Inventory) {
+			err := e.orphanInventory(ctx, cr)
+			if err != nil {
+				return fmt.Errorf("uninstalling inventory: %w", err)
+			}
+		} else {
+			err := e.undeployFromTemplates(ctx, cr, gvr, true, false)
+			if err != nil {
+				return err
+			}
+		}
+		cr.SetConditions(definitionv1alpha1.ResourcesOrphaned().
+			WithMessage(fmt.Sprintf("CRD '%s' and its resources are kept", gvr.GroupResource().String())))
+
+	case definitionv1alpha1.DeletionPolicyCascade:
+		// The resources are deleted first, the dynamic controller is kept to delete them from the external system
+		left, err := e.deleteRestResources(ctx, cr)
+		if err != nil {
+			return err
+		}
+		if left > 0 {
+			e.log.Debug("Waiting for RestResources to be deleted", "Group", gvr.Group, "Resource", gvr.Resource, "Count", left)
+			cr.SetConditions(rtv1.Deleting(), definitionv1alpha1.DeletingResources().
+				WithMessage(fmt.Sprintf("Waiting for %d resources of '%s' to be deleted", left, gvr.GroupResource().String())))
+			return fmt.Errorf("waiting for %d restResources to be deleted", left)
+		}
+		if hasControllerInventory(cr.Status.Inventory) {
+			err = e.deleteInventory(ctx, cr, false)
+			if err != nil {
+				return fmt.Errorf("uninstalling inventory: %w", err)
+			}
+		} else {
+			err = e.undeployFromTemplates(ctx, cr, gvr, false, false)
+			if err != nil {
+				return err
+			}
+		}
+		cr.SetConditions(definitionv1alpha1.ResourcesDeleted())
+
+	default:
+		skipDeploy := meta.FinalizerExists(cr, restresourcesStillExistFinalizer)
+
+		if hasControllerInventory(cr.Status.Inventory) {
+			// The objects to remove are exactly the ones recorded in the inventory by Create and Update
+			err := e.deleteInventory(ctx, cr, skipDeploy)
+			if err != nil {
+				return fmt.Errorf("uninstalling inventory: %w", err)
+			}
+		} else {
+			// RestDefinitions created before the inventory was introduced: the objects to remove are found by rendering the templates
+			err := e.undeployFromTemplates(ctx, cr, gvr, false, skipDeploy)
+			if err != nil {
+				return err
+			}
+		}
+
+		if skipDeploy {
+			e.log.Debug(" RestResources still exist",
+				"Group", gvr.Group, "Resource", gvr.Resource)
+			cr.SetConditions(rtv1.Deleting(), definitionv1alpha1.ResourcesStillExist().
+				WithMessage(fmt.Sprintf("Waiting for the resources of '%s' to be deleted", gvr.GroupResource().String())))
+			return fmt.Errorf("restResources still exist")
+		}
+		cr.SetConditions(definitionv1alpha1.ResourcesDeleted())
+	}
+
+	e.fetches.forget(cr)
+
+	e.log.Debug("Deleting RestDefinition", "Kind:", cr.Spec.Resource.Kind, "Group:", cr.Spec.ResourceGroup)
+	e.rec.Eventf(cr, corev1.EventTypeNormal, "RestDefinitionDeleting",
+		"RestDefinition '%s/%s' deleting", cr.Spec.Resource.Kind, cr.Spec.ResourceGroup)
+	return nil
+}
+
+// undeployFromTemplates removes the CRD and the dynamic controller of a RestDefinition without inventory,
+// rendering the templates to learn the names of the objects.
+func (e *external) undeployFromTemplates(ctx context.Context, cr *definitionv1alpha1.RestDefinition, gvr schema.GroupVersionResource, skipCRD, skipDeploy bool) error {
 	// Set to true by default to avoid issues in case of errors when getting the document model from the CR.
 	// During a helm uninstall of a provider, the ConfigMap containing the OAS document might already be deleted
 	// when the RestDefinition is being deleted, causing an error when trying to get the document model from the CR.
 	hasSecuritySchemes := true
-	doc, err := e.getDocumentModelFromCR(ctx, cr)
+	doc, _, err := e.getDocumentModelFromCR(ctx, cr)
 	if err != nil {
 		e.log.Debug("Failed to get document model from CR", "error", err)
 		// Probably ConfigMap with OAS document is already deleted during a helm uninstall
@@ -648,25 +838,10 @@ func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
 		e.log.Debug("Checked for security schemes in OAS document", "HasSecuritySchemes", hasSecuritySchemes)
 	}
 
-	if !meta.IsActionAllowed(cr, meta.ActionDelete) {
-		e.log.Debug("External resource should not be deleted by provider, skip deleting.")
-		return nil
-	}
-
-	e.log.Info("Deleting RestDefinition", "Kind:", cr.Spec.Resource.Kind, "Group:", cr.Spec.ResourceGroup)
-
-	gvr := plurals.ToGroupVersionResource(schema.GroupVersionKind{
-		Group:   cr.Spec.ResourceGroup,
-		Version: resourceVersion,
-		Kind:    text.CapitaliseFirstLetter(cr.Spec.Resource.Kind),
-	})
-
-	skipDeploy := meta.FinalizerExists(cr, restresourcesStillExistFinalizer)
-
 	configurationGVR := getConfigurationGVR(cr, hasSecuritySchemes)
 	opts := deploy.UndeployOptions{
 		ConfigurationGVR: configurationGVR,
-		SkipCRD:          false,
+		SkipCRD:          skipCRD,
 		SkipDeploy:       skipDeploy,
 		RBACFolderPath:   RDCrbacConfigFolder,
 		KubeClient:       e.kube,
@@ -678,23 +853,10 @@ func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
 		Log:                    e.log.Debug,
 		DeploymentTemplatePath: RDCtemplateDeploymentPath,
 		ConfigmapTemplatePath:  RDCtemplateConfigmapPath,
+		Scope:                  string(getResourceScope(cr)),
 	}
 
-	err = deploy.Undeploy(ctx, e.kube, opts)
-	if err != nil {
-		return fmt.Errorf("uninstalling controller: %w", err)
-	}
-
-	if skipDeploy || opts.SkipCRD {
-		e.log.Debug(" RestResources still exist",
-			"Group", gvr.Group, "Resource", gvr.Resource)
-		return fmt.Errorf("restResources still exist")
-	}
-
-	e.log.Debug("Deleting RestDefinition", "Kind:", cr.Spec.Resource.Kind, "Group:", cr.Spec.ResourceGroup)
-	e.rec.Eventf(cr, corev1.EventTypeNormal, "RestDefinitionDeleting",
-		"RestDefinition '%s/%s' deleting", cr.Spec.Resource.Kind, cr.Spec.ResourceGroup)
-	return err
+	return deploy.Undeploy(ctx, e.kube, opts)
 }
 
 func getConfigurationGVR(cr *definitionv1alpha1.RestDefinition, hasSecuritySchemes bool) schema.GroupVersionResource {
@@ -706,49 +868,58 @@ func getConfigurationGVR(cr *definitionv1alpha1.RestDefinition, hasSecuritySchem
 	return plurals.ToGroupVersionResource(cfgGVK)
 }
 
+func getResourceGVK(cr *definitionv1alpha1.RestDefinition) schema.GroupVersionKind {
+	return schema.GroupVersionKind{
+		Group:   cr.Spec.ResourceGroup,
+		Version: getResourceVersion(cr),
+		Kind:    text.CapitaliseFirstLetter(cr.Spec.Resource.Kind),
+	}
+}
+
 func getConfigurationGVK(cr *definitionv1alpha1.RestDefinition) schema.GroupVersionKind {
 	return schema.GroupVersionKind{
 		Group:   cr.Spec.ResourceGroup,
-		Version: resourceVersion,
+		Version: getResourceVersion(cr),
 		Kind:    text.CapitaliseFirstLetter(cr.Spec.Resource.Kind) + "Configuration",
 	}
 }
 
+// getResourceVersion returns the API version of the generated CRDs declared by the RestDefinition.
+func getResourceVersion(cr *definitionv1alpha1.RestDefinition) string {
+	if cr.Spec.Resource.Version == "" {
+		return defaultResourceVersion
+	}
+	return cr.Spec.Resource.Version
+}
+
+// getResourceScope returns the scope of the resources of the generated CRD, namespaced by default.
+func getResourceScope(cr *definitionv1alpha1.RestDefinition) apiextensionsv1.ResourceScope {
+	if cr.Spec.Resource.Scope == definitionv1alpha1.ResourceScopeCluster {
+		return apiextensionsv1.ClusterScoped
+	}
+	return apiextensionsv1.NamespaceScoped
+}
+
 func manageFinalizers(ctx context.Context, kubecli client.Client, cr *definitionv1alpha1.RestDefinition, log func(msg string, keysAndValues ...any)) error {
 	log("Managing finalizers for RestDefinition", "name", cr.Name, "namespace", cr.Namespace)
 
 	// Check if RestResources still exist for this RestDefinition
-	gvk := schema.GroupVersionKind{
-		Group:   cr.Spec.ResourceGroup,
-		Version: resourceVersion,
-		Kind:    text.CapitaliseFirstLetter(cr.Spec.Resource.Kind),
+	items, err := listRestResources(ctx, kubecli, cr, log)
+	if err != nil {
+		return err
 	}
 
-	uli := unstructured.UnstructuredList{}
-	uli.SetGroupVersionKind(gvk)
-	err := kubecli.List(ctx, &uli)
-	if err != nil && !strings.Contains(err.Error(), "no matches for") {
-
-		// If the CRD is missing, we assume no resources exist
-		if strings.Contains(err.Error(), "the server could not find the requested resource") {
-			log("CRD not found, treating as no resources exist",
-				"Group", cr.Spec.ResourceGroup,
-				"Kind", cr.Spec.Resource.Kind,
-				"Version", resourceVersion,
-				"error", err.Error())
-			uli.Items = nil
-			err = nil
-		} else {
-			return fmt.Errorf("listing RestResources: %w", err)
-		}
-	}
+	restResourceCount := len(items)
 
-	restResourceCount := len(uli.Items)
+	// RestDefinitions orphaning their resources on delete do not wait for them to be deleted
+	if getDeletionPolicy(cr) == definitionv1alpha1.DeletionPolicyOrphan {
+		restResourceCount = 0
+	}
 
 	// Manage restresources-still-exist finalizer
 	if restResourceCount > 0 {
 		if !meta.FinalizerExists(cr, restresourcesStillExistFinalizer) {
-			log("Existing RestResources found", "Group", cr.Spec.ResourceGroup, "Kind", cr.Spec.Resource.Kind, "Count", restResourceCount)
+			log("Existing RestResources found", "Group", cr.Spec.ResourceGroup, "Kind", cr.Spec.Resource.Kind, "Scope", getResourceScope(cr), "Count", restResourceCount)
 			log("Adding finalizer to RestDefinition", "name", cr.Name, "finalizer", restresourcesStillExistFinalizer)
 			meta.AddFinalizer(cr, restresourcesStillExistFinalizer)
 			err = kubecli.Update(ctx, cr)
@@ -770,29 +941,33 @@ func manageFinalizers(ctx context.Context, kubecli client.Client, cr *definition
 	return nil
 }
 
-func (e *external) getDocumentModelFromCR(ctx context.Context, cr *definitionv1alpha1.RestDefinition) (oas2jsonschema.OASDocument, error) {
-	OASPath := cr.Spec.OASPath
-	basePath := "/tmp/ogen-provider"
-	err := os.MkdirAll(basePath, os.ModePerm)
-	defer os.RemoveAll(basePath)
+// getDocumentModelFromCR fetches and parses the OAS document of the RestDefinition, returning it
+// along with its digest (see oas2jsonschema.Bundle.Digest).
+func (e *external) getDocumentModelFromCR(ctx context.Context, cr *definitionv1alpha1.RestDefinition) (oas2jsonschema.OASDocument, string, error) {
+	if err := checkOASSecret(cr); err != nil {
+		return nil, "", err
+	}
+
+	auth, err := e.getOASAuth(ctx, cr)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create directory: %w", err)
+		return nil, "", fmt.Errorf("getting OAS credentials: %w", err)
 	}
 
-	filegetter := &filegetter.Filegetter{
+	getter := &filegetter.Filegetter{
 		Client:     http.DefaultClient,
 		KubeClient: e.kube,
+		Cache:      e.files,
 	}
 
-	err = filegetter.GetFile(ctx, path.Join(basePath, path.Base(OASPath)), OASPath, nil)
+	bundle, err := e.loadOASBundle(ctx, cr, getter, auth)
 	if err != nil {
-		return nil, fmt.Errorf("failed to download file: %w", err)
+		return nil, "", err
 	}
 
-	contents, err := os.ReadFile(path.Join(basePath, path.Base(OASPath)))
+	doc, err := e.parser.ParseBundle(bundle)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read file: %w", err)
+		return nil, "", err
 	}
-
-	return e.parser.Parse(contents)
+	e.fetches.record(cr, time.Now())
+	return doc, bundle.Digest(), nil
 }
@@ -222,7 +222,7 @@ func TestLifecycle_Simple(t *testing.T) {
 		if obs.ResourceExists == true && obs.ResourceUpToDate == true {
 			gvr := plurals.ToGroupVersionResource(schema.GroupVersionKind{
 				Group:   mg.Spec.ResourceGroup,
-				Version: resourceVersion,
+				Version: defaultResourceVersion,
 				Kind:    mg.Spec.Resource.Kind,
 			})
 
@@ -259,7 +259,7 @@ func TestLifecycle_Simple(t *testing.T) {
 
 		gvk := schema.GroupVersionKind{
 			Group:   mg.Spec.ResourceGroup,
-			Version: resourceVersion,
+			Version: defaultResourceVersion,
 			Kind:    mg.Spec.Resource.Kind,
 		}
 
@@ -407,7 +407,7 @@ func TestLifecycle_GitHubWorkflows(t *testing.T) {
 			if obs.ResourceExists == true && obs.ResourceUpToDate == true {
 				gvr := plurals.ToGroupVersionResource(schema.GroupVersionKind{
 					Group:   mg.Spec.ResourceGroup,
-					Version: resourceVersion,
+					Version: defaultResourceVersion,
 					Kind:    mg.Spec.Resource.Kind,
 				})
 
@@ -483,7 +483,7 @@ func TestLifecycle_GitHubWorkflows(t *testing.T) {
 
 			gvk := schema.GroupVersionKind{
 				Group:   mg.Spec.ResourceGroup,
-				Version: resourceVersion,
+				Version: defaultResourceVersion,
 				Kind:    mg.Spec.Resource.Kind,
 			}
 
@@ -630,7 +630,7 @@ func TestLifecycle_ArubaCloudSubnet(t *testing.T) {
 			if obs.ResourceExists == true && obs.ResourceUpToDate == true {
 				gvr := plurals.ToGroupVersionResource(schema.GroupVersionKind{
 					Group:   mg.Spec.ResourceGroup,
-					Version: resourceVersion,
+					Version: defaultResourceVersion,
 					Kind:    mg.Spec.Resource.Kind,
 				})
 
@@ -707,7 +707,7 @@ func TestLifecycle_ArubaCloudSubnet(t *testing.T) {
 
 			gvk := schema.GroupVersionKind{
 				Group:   mg.Spec.ResourceGroup,
-				Version: resourceVersion,
+				Version: defaultResourceVersion,
 				Kind:    mg.Spec.Resource.Kind,
 			}
 
@@ -853,7 +853,7 @@ func TestLifecycle_ArrayDefaults(t *testing.T) {
 		if obs.ResourceExists == true && obs.ResourceUpToDate == true {
 			gvr := plurals.ToGroupVersionResource(schema.GroupVersionKind{
 				Group:   mg.Spec.ResourceGroup,
-				Version: resourceVersion,
+				Version: defaultResourceVersion,
 				Kind:    mg.Spec.Resource.Kind,
 			})
 
@@ -929,7 +929,7 @@ func TestLifecycle_ArrayDefaults(t *testing.T) {
 
 		gvk := schema.GroupVersionKind{
 			Group:   mg.Spec.ResourceGroup,
-			Version: resourceVersion,
+			Version: defaultResourceVersion,
 			Kind:    mg.Spec.Resource.Kind,
 		}
 
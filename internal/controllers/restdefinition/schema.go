@@ -0,0 +1,294 @@
+package restdefinition
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	definitionv1alpha1 "github.com/krateoplatformops/oasgen-provider/apis/restdefinitions/v1alpha1"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/crd"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/oas2jsonschema"
+	"github.com/krateoplatformops/plumbing/crdgen"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// generateCRDs generates the CRD of the resource and, if configuration fields or security schemes are defined,
+// the CRD of its configuration (nil otherwise) from the OAS document.
+func (e *external) generateCRDs(cr *definitionv1alpha1.RestDefinition, doc oas2jsonschema.OASDocument, hasSecuritySchemes bool) (*apiextensionsv1.CustomResourceDefinition, *apiextensionsv1.CustomResourceDefinition, error) {
+	gvk := getResourceGVK(cr)
+
+	// Shim needed to convert definitionv1alpha1.VerbsDescription to oas2jsonschema.Verbs
+	// Verbs is a type defined within the oas2jsonschema package
+	// and so it's not tied with the RestDefinition CRD
+	verbs := make([]oas2jsonschema.Verb, len(cr.Spec.Resource.VerbsDescription))
+	for i, v := range cr.Spec.Resource.VerbsDescription {
+		verbs[i] = oas2jsonschema.Verb{
+			Action: v.Action,
+			Method: v.Method,
+			Path:   v.Path,
+		}
+	}
+
+	// Shim needed to convert definitionv1alpha1.ConfigurationFields to oas2jsonschema.ConfigurationFields
+	configurationFields := make([]oas2jsonschema.ConfigurationField, 0, len(cr.Spec.Resource.ConfigurationFields))
+	for _, v := range cr.Spec.Resource.ConfigurationFields {
+		actions, err := expandWildcardActions(v.FromRestDefinition.Actions, cr.Spec.Resource.VerbsDescription)
+		if err != nil {
+			return nil, nil, fmt.Errorf("expanding wildcard for actions in configurationFields: %w", err)
+		}
+
+		configurationFields = append(configurationFields, oas2jsonschema.ConfigurationField{
+			FromOpenAPI: oas2jsonschema.FromOpenAPI{
+				Name: v.FromOpenAPI.Name,
+				In:   v.FromOpenAPI.In,
+			},
+			FromRestDefinition: oas2jsonschema.FromRestDefinition{
+				Actions: actions,
+			},
+		})
+	}
+
+	// Create the resource configuration for the OAS schema generator
+	// We pass only relevant fields from the RestDefinition needed for schema generation
+	resourceConfig := &oas2jsonschema.ResourceConfig{
+		Verbs:                  verbs,
+		Identifiers:            cr.Spec.Resource.Identifiers,
+		AdditionalStatusFields: cr.Spec.Resource.AdditionalStatusFields,
+		ConfigurationFields:    configurationFields,
+		ExcludedSpecFields:     cr.Spec.Resource.ExcludedSpecFields,
+	}
+
+	// Create the generator configuration, enabling the legacy number coercion if requested
+	generatorConfig := oas2jsonschema.DefaultGeneratorConfig()
+	generatorConfig.CoerceNumberToInteger = cr.Spec.Resource.CoerceNumberToInteger
+
+	// Create the OAS schema generator
+	generator := oas2jsonschema.NewOASSchemaGenerator(
+		doc,
+		generatorConfig,
+		resourceConfig,
+	)
+
+	result, err := generator.Generate()
+	if err != nil {
+		// Fatal error, we cannot continue
+		return nil, nil, fmt.Errorf("generating schemas: %w", err)
+	}
+	if len(result.GenerationWarnings) > 0 {
+		e.log.Debug("Some schema generation warnings were found, below the list")
+		for _, er := range result.GenerationWarnings {
+			e.log.Debug("Schema generation warning", "Warning", er)
+		}
+	}
+	if len(result.ValidationWarnings) > 0 {
+		e.log.Debug("Some schema validation warnings were found, below the list")
+		for _, er := range result.ValidationWarnings {
+			e.log.Debug("Schema validation warning", "Warning", er)
+		}
+	}
+
+	e.log.Debug("Generating CRD for", "Kind:", cr.Spec.Resource.Kind, "Group:", cr.Spec.ResourceGroup)
+
+	opts := crdgen.Options{
+		Group:        gvk.Group,
+		Version:      gvk.Version,
+		Kind:         gvk.Kind,
+		Categories:   []string{strings.ToLower(cr.Spec.Resource.Kind), "restresources", "rr"},
+		SpecSchema:   result.SpecSchema,
+		StatusSchema: result.StatusSchema,
+		Managed:      true,
+	}
+
+	crdu, err := crd.Generate(opts)
+	if err != nil {
+		return nil, nil, fmt.Errorf("generating CRD: %w", err)
+	}
+	crdu.Spec.Scope = getResourceScope(cr)
+	setCRDOwner(crdu, cr)
+
+	// Only generate Configuration CRD if configuration fields are defined or if security schemes are defined
+	if len(configurationFields) == 0 && !hasSecuritySchemes {
+		return crdu, nil, nil
+	}
+
+	e.log.Debug("Configuration fields or security schemes defined, generating Configuration CRD")
+	e.log.Debug("Configuration fields length", "Length: ", len(configurationFields))
+	e.log.Debug("Has security schemes: ", "HasSecuritySchemes", hasSecuritySchemes)
+
+	cfgGVK := getConfigurationGVK(cr)
+
+	e.log.Debug("Generating Configuration CRD", "Kind", cfgGVK.Kind, "Group", cfgGVK.Group)
+
+	e.log.Debug("Configuration Schema", "Schema", string(result.ConfigurationSchema))
+
+	cfgOpts := crdgen.Options{
+		Group:      cfgGVK.Group,
+		Version:    cfgGVK.Version,
+		Kind:       cfgGVK.Kind,
+		Categories: []string{strings.ToLower(cr.Spec.Resource.Kind), "restconfigs", "rc"},
+		SpecSchema: result.ConfigurationSchema,
+		Managed:    false,
+	}
+
+	cfgCRDU, err := crd.Generate(cfgOpts)
+	if err != nil {
+		return nil, nil, fmt.Errorf("generating configuration CRD: %w", err)
+	}
+	// Configurations are namespaced even for cluster-scoped resources, which reference them with 'configurationRef.namespace'
+	cfgCRDU.Spec.Scope = apiextensionsv1.NamespaceScoped
+	setCRDOwner(cfgCRDU, cr)
+
+	return crdu, cfgCRDU, nil
+}
+
+// schemaDrift is the difference between the schema of an installed CRD and the schema generated from the OAS.
+type schemaDrift struct {
+	// installed is the installed CRD, which is updated in place with the desired schema.
+	installed *apiextensionsv1.CustomResourceDefinition
+	desired   *apiextensionsv1.JSONSchemaProps
+	// changes lists the changes from the installed schema to the desired one (see crd.DiffSchemas).
+	changes []crd.SchemaChange
+}
+
+// breaking reports whether the drift contains changes that existing resources could not survive.
+func (d schemaDrift) breaking() bool {
+	return len(crd.BreakingChanges(d.changes)) > 0
+}
+
+// applicable reports whether the drift can be applied in place according to the breaking change policy.
+func (d schemaDrift) applicable(policy definitionv1alpha1.BreakingChangePolicy) bool {
+	return policy == definitionv1alpha1.BreakingChangePolicyAllow || !d.breaking()
+}
+
+// schemaDrifts compares the schemas of the CRDs generated from the OAS document (see generateCRDs) with the installed CRDs.
+// Nil CRDs and CRDs not installed yet are skipped, as the latter are installed by Create.
+func (e *external) schemaDrifts(ctx context.Context, cr *definitionv1alpha1.RestDefinition, crds ...*apiextensionsv1.CustomResourceDefinition) ([]schemaDrift, error) {
+	var drifts []schemaDrift
+	for _, generated := range crds {
+		if generated == nil {
+			continue
+		}
+
+		installed, err := crd.Get(ctx, e.kube, schema.GroupResource{Group: generated.Spec.Group, Resource: generated.Spec.Names.Plural})
+		if err != nil {
+			return nil, fmt.Errorf("getting CRD %s: %w", generated.Name, err)
+		}
+		if installed == nil {
+			continue
+		}
+
+		// Versions not served by the installed CRD yet are appended by Create
+		current := crd.VersionSchema(installed, getResourceVersion(cr))
+		desired := crd.VersionSchema(generated, getResourceVersion(cr))
+		if current == nil || desired == nil || equality.Semantic.DeepEqual(current, desired) {
+			continue
+		}
+
+		drifts = append(drifts, schemaDrift{
+			installed: installed,
+			desired:   desired,
+			changes:   crd.DiffSchemas(current, desired),
+		})
+	}
+
+	return drifts, nil
+}
+
+// observeSchemaDrifts records the schema changes in the status of the RestDefinition, sets its SchemaSynced
+// condition according to the breaking change policy and returns true if there are changes to apply in place.
+func (e *external) observeSchemaDrifts(cr *definitionv1alpha1.RestDefinition, drifts []schemaDrift) bool {
+	policy := breakingChangePolicy(cr)
+
+	cr.Status.SchemaChanges = nil
+	var blocked []string
+	applicable := false
+	for _, drift := range drifts {
+		for _, c := range drift.changes {
+			cr.Status.SchemaChanges = append(cr.Status.SchemaChanges, definitionv1alpha1.SchemaChange{
+				CRD:         drift.installed.Name,
+				Path:        c.Path,
+				Type:        string(c.Type),
+				Description: c.Description,
+			})
+		}
+
+		if !drift.applicable(policy) {
+			for _, c := range crd.BreakingChanges(drift.changes) {
+				blocked = append(blocked, c.String())
+			}
+			continue
+		}
+		applicable = true
+	}
+
+	if len(blocked) == 0 {
+		cr.SetConditions(definitionv1alpha1.SchemaUpToDate())
+		return applicable
+	}
+
+	cond := definitionv1alpha1.SchemaIncompatible()
+	msg := fmt.Sprintf("The schema generated from the OAS contains breaking changes that are not applied to the installed CRD: %s. Existing resources must be migrated before applying them", strings.Join(blocked, "; "))
+	if policy == definitionv1alpha1.BreakingChangePolicyVersion {
+		cond = definitionv1alpha1.SchemaVersionRequired()
+		msg = fmt.Sprintf("The schema generated from the OAS contains breaking changes that require a new API version of the installed CRD: %s. Set a new version in spec.resource.version to serve them", strings.Join(blocked, "; "))
+	}
+	if cr.GetCondition(definitionv1alpha1.TypeSchemaSynced).Reason != cond.Reason {
+		e.rec.Eventf(cr, corev1.EventTypeWarning, string(cond.Reason), msg)
+	}
+	cr.SetConditions(cond.WithMessage(msg))
+	return applicable
+}
+
+// applySchemaDrifts updates the installed CRDs in place with the schema changes allowed by the breaking change policy.
+func (e *external) applySchemaDrifts(ctx context.Context, cr *definitionv1alpha1.RestDefinition, drifts []schemaDrift) error {
+	policy := breakingChangePolicy(cr)
+	for _, drift := range drifts {
+		if !drift.applicable(policy) {
+			e.log.Debug("Skipping breaking schema changes", "CRD", drift.installed.Name, "Policy", policy, "Changes", drift.changes)
+			continue
+		}
+
+		if err := crd.SetVersionSchema(drift.installed, getResourceVersion(cr), drift.desired); err != nil {
+			return err
+		}
+
+		e.log.Debug("Updating CRD schema", "CRD", drift.installed.Name)
+		if err := e.kube.Update(ctx, drift.installed); err != nil {
+			return fmt.Errorf("updating CRD %s: %w", drift.installed.Name, err)
+		}
+
+		// The applied changes are not pending anymore
+		pending := cr.Status.SchemaChanges[:0]
+		for _, c := range cr.Status.SchemaChanges {
+			if c.CRD != drift.installed.Name {
+				pending = append(pending, c)
+			}
+		}
+		cr.Status.SchemaChanges = pending
+
+		if len(drift.changes) == 0 {
+			continue
+		}
+		descriptions := make([]string, 0, len(drift.changes))
+		for _, c := range drift.changes {
+			descriptions = append(descriptions, c.String())
+		}
+		eventType, reason := corev1.EventTypeNormal, "SchemaUpdated"
+		if drift.breaking() {
+			eventType, reason = corev1.EventTypeWarning, "BreakingSchemaChangeApplied"
+		}
+		e.rec.Eventf(cr, eventType, reason, "Schema of CRD '%s' updated: %s", drift.installed.Name, strings.Join(descriptions, "; "))
+	}
+	return nil
+}
+
+// breakingChangePolicy returns the breaking change policy of the RestDefinition, defaulting to Block.
+func breakingChangePolicy(cr *definitionv1alpha1.RestDefinition) definitionv1alpha1.BreakingChangePolicy {
+	if cr.Spec.BreakingChangePolicy == "" {
+		return definitionv1alpha1.BreakingChangePolicyBlock
+	}
+	return cr.Spec.BreakingChangePolicy
+}
@@ -0,0 +1,98 @@
+package restdefinition
+
+import (
+	"testing"
+
+	definitionv1alpha1 "github.com/krateoplatformops/oasgen-provider/apis/restdefinitions/v1alpha1"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/oas2jsonschema"
+	"github.com/krateoplatformops/provider-runtime/pkg/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const numericMismatchOAS = `
+openapi: 3.0.3
+info:
+  title: Counters
+  version: 1.0.0
+paths:
+  /counters:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                name:
+                  type: string
+                value:
+                  type: number
+      responses:
+        '201':
+          description: Created
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  name:
+                    type: string
+                  value:
+                    type: number
+  /counters/{name}:
+    get:
+      parameters:
+        - name: name
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  name:
+                    type: string
+                  value:
+                    type: integer
+`
+
+func TestGenerateCRDs_NumericTypeMismatch(t *testing.T) {
+	doc, err := oas2jsonschema.NewLibOASParser().ParseBundle(&oas2jsonschema.Bundle{
+		Entry: "openapi.yaml",
+		Files: map[string][]byte{"openapi.yaml": []byte(numericMismatchOAS)},
+	})
+	require.NoError(t, err)
+
+	cr := &definitionv1alpha1.RestDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "counter", Namespace: "default"},
+		Spec: definitionv1alpha1.RestDefinitionSpec{
+			ResourceGroup: "counters.ogen.krateo.io",
+			Resource: definitionv1alpha1.Resource{
+				Kind:                   "Counter",
+				Identifiers:            []string{"name"},
+				AdditionalStatusFields: []string{"value"},
+				VerbsDescription: []definitionv1alpha1.VerbsDescription{
+					{Action: "create", Method: "POST", Path: "/counters"},
+					{Action: "get", Method: "GET", Path: "/counters/{name}"},
+				},
+			},
+		},
+	}
+
+	// The number/integer mismatch between the actions is only a validation warning
+	e := &external{log: logging.NewNopLogger()}
+	crdu, _, err := e.generateCRDs(cr, doc, false)
+	require.NoError(t, err)
+	require.NotNil(t, crdu)
+
+	schema := crdu.Spec.Versions[0].Schema.OpenAPIV3Schema
+	assert.Equal(t, "number", schema.Properties["spec"].Properties["value"].Type)
+	assert.Equal(t, "integer", schema.Properties["status"].Properties["value"].Type)
+}
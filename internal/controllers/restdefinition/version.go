@@ -0,0 +1,141 @@
+package restdefinition
+
+import (
+	"context"
+	"fmt"
+
+	definitionv1alpha1 "github.com/krateoplatformops/oasgen-provider/apis/restdefinitions/v1alpha1"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/crd"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/deploy"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/kube"
+	"github.com/krateoplatformops/provider-runtime/pkg/meta"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// installCRD installs the generated CRD. If the CRD is already installed with other versions, the generated
+// version is appended to it and served alongside the previous ones (see crd.AppendVersion).
+func (e *external) installCRD(ctx context.Context, generated *apiextensionsv1.CustomResourceDefinition) error {
+	installed, err := crd.Get(ctx, e.kube, schema.GroupResource{Group: generated.Spec.Group, Resource: generated.Spec.Names.Plural})
+	if err != nil {
+		return fmt.Errorf("getting CRD %s: %w", generated.Name, err)
+	}
+	if installed != nil && installed.Spec.Scope != generated.Spec.Scope {
+		return fmt.Errorf("CRD %s is %s, its scope cannot be changed to %s", installed.Name, installed.Spec.Scope, generated.Spec.Scope)
+	}
+	if installed == nil || servesOnly(installed, generated) {
+		return kube.Apply(ctx, e.kube, generated, kube.ApplyOptions{})
+	}
+
+	for _, ver := range generated.Spec.Versions {
+		if crd.VersionSchema(installed, ver.Name) != nil {
+			e.log.Debug("Updating CRD version schema", "CRD", installed.Name, "Version", ver.Name)
+			if err := crd.SetVersionSchema(installed, ver.Name, ver.Schema.OpenAPIV3Schema); err != nil {
+				return err
+			}
+			continue
+		}
+
+		e.log.Debug("Appending version to CRD", "CRD", installed.Name, "Version", ver.Name)
+		updated, err := crd.AppendVersion(*installed, apiextensionsv1.CustomResourceDefinition{
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{ver},
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("appending version %s to CRD %s: %w", ver.Name, installed.Name, err)
+		}
+		installed = updated
+	}
+
+	if owner := generated.GetAnnotations()[definitionv1alpha1.AnnotationKeyOwner]; owner != "" {
+		meta.AddAnnotations(installed, map[string]string{definitionv1alpha1.AnnotationKeyOwner: owner})
+	}
+
+	// Serving multiple versions, the API server calls the conversion webhook (if enabled) to convert the resources
+	installed = crd.ConversionConf(*installed, e.desiredConversion(installed))
+
+	return e.kube.Update(ctx, installed)
+}
+
+// conversionDrifts returns the installed CRDs whose conversion configuration is not the desired one,
+// updated with the desired configuration (see desiredConversion).
+func (e *external) conversionDrifts(ctx context.Context, gvrs ...schema.GroupVersionResource) ([]*apiextensionsv1.CustomResourceDefinition, error) {
+	var drifts []*apiextensionsv1.CustomResourceDefinition
+	for _, gvr := range gvrs {
+		if gvr.Resource == "" {
+			continue
+		}
+
+		installed, err := crd.Get(ctx, e.kube, gvr.GroupResource())
+		if err != nil {
+			return nil, fmt.Errorf("getting CRD %s: %w", gvr.GroupResource().String(), err)
+		}
+		if installed == nil {
+			continue
+		}
+
+		desired := e.desiredConversion(installed)
+		if equality.Semantic.DeepEqual(installed.Spec.Conversion, desired) {
+			continue
+		}
+		drifts = append(drifts, crd.ConversionConf(*installed, desired))
+	}
+	return drifts, nil
+}
+
+// servesOnly reports whether the installed CRD has exactly the versions of the generated one.
+func servesOnly(installed, generated *apiextensionsv1.CustomResourceDefinition) bool {
+	if len(installed.Spec.Versions) != len(generated.Spec.Versions) {
+		return false
+	}
+	for i := range installed.Spec.Versions {
+		if installed.Spec.Versions[i].Name != generated.Spec.Versions[i].Name {
+			return false
+		}
+	}
+	return true
+}
+
+// undeployPreviousVersion removes the controller deployed for the previous API version of the resource
+// (recorded in status), since its RBAC resources are named after the version. The CRD is kept, as it still
+// serves the previous version.
+func (e *external) undeployPreviousVersion(ctx context.Context, cr *definitionv1alpha1.RestDefinition, gvr, configurationGVR schema.GroupVersionResource) error {
+	if cr.Status.Resource.APIVersion == "" {
+		return nil
+	}
+	prev, err := schema.ParseGroupVersion(cr.Status.Resource.APIVersion)
+	if err != nil {
+		return fmt.Errorf("parsing previous API version: %w", err)
+	}
+	if prev.Version == gvr.Version {
+		return nil
+	}
+
+	e.log.Debug("API version changed, removing controller of the previous version", "Previous", prev.Version, "Current", gvr.Version)
+
+	prevGVR := gvr
+	prevGVR.Version = prev.Version
+	prevConfigurationGVR := configurationGVR
+	if prevConfigurationGVR.Resource != "" {
+		prevConfigurationGVR.Version = prev.Version
+	}
+
+	return deploy.Undeploy(ctx, e.kube, deploy.UndeployOptions{
+		ConfigurationGVR: prevConfigurationGVR,
+		SkipCRD:          true,
+		RBACFolderPath:   RDCrbacConfigFolder,
+		KubeClient:       e.kube,
+		NamespacedName: types.NamespacedName{
+			Namespace: cr.Namespace,
+			Name:      cr.Name,
+		},
+		GVR:                    prevGVR,
+		Log:                    e.log.Debug,
+		DeploymentTemplatePath: RDCtemplateDeploymentPath,
+		ConfigmapTemplatePath:  RDCtemplateConfigmapPath,
+		Scope:                  string(getResourceScope(cr)),
+	})
+}
@@ -0,0 +1,79 @@
+package restdefinition
+
+import (
+	"context"
+	"testing"
+
+	definitionv1alpha1 "github.com/krateoplatformops/oasgen-provider/apis/restdefinitions/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestGetResourceGVK(t *testing.T) {
+	cr := &definitionv1alpha1.RestDefinition{
+		Spec: definitionv1alpha1.RestDefinitionSpec{
+			ResourceGroup: "github.ogen.krateo.io",
+			Resource:      definitionv1alpha1.Resource{Kind: "repo"},
+		},
+	}
+	assert.Equal(t, "github.ogen.krateo.io/v1alpha1, Kind=Repo", getResourceGVK(cr).String())
+	assert.Equal(t, "github.ogen.krateo.io/v1alpha1, Kind=RepoConfiguration", getConfigurationGVK(cr).String())
+
+	cr.Spec.Resource.Version = "v1beta1"
+	assert.Equal(t, "github.ogen.krateo.io/v1beta1, Kind=Repo", getResourceGVK(cr).String())
+	assert.Equal(t, "github.ogen.krateo.io/v1beta1, Kind=RepoConfiguration", getConfigurationGVK(cr).String())
+}
+
+func TestGetResourceScope(t *testing.T) {
+	cr := &definitionv1alpha1.RestDefinition{}
+	assert.Equal(t, apiextensionsv1.NamespaceScoped, getResourceScope(cr))
+
+	cr.Spec.Resource.Scope = definitionv1alpha1.ResourceScopeNamespaced
+	assert.Equal(t, apiextensionsv1.NamespaceScoped, getResourceScope(cr))
+
+	cr.Spec.Resource.Scope = definitionv1alpha1.ResourceScopeCluster
+	assert.Equal(t, apiextensionsv1.ClusterScoped, getResourceScope(cr))
+}
+
+func TestInstallCRDScopeChange(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, apiextensionsv1.AddToScheme(scheme))
+
+	withScope := func(scope apiextensionsv1.ResourceScope) *apiextensionsv1.CustomResourceDefinition {
+		return &apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: "orgs.github.ogen.krateo.io"},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Group: "github.ogen.krateo.io",
+				Names: apiextensionsv1.CustomResourceDefinitionNames{Plural: "orgs", Kind: "Org"},
+				Scope: scope,
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+					{Name: "v1alpha1", Served: true, Storage: true},
+				},
+			},
+		}
+	}
+
+	e := &external{
+		kube: fake.NewClientBuilder().WithScheme(scheme).WithObjects(withScope(apiextensionsv1.NamespaceScoped)).Build(),
+	}
+	err := e.installCRD(context.Background(), withScope(apiextensionsv1.ClusterScoped))
+	assert.ErrorContains(t, err, "scope cannot be changed")
+}
+
+func TestServesOnly(t *testing.T) {
+	withVersions := func(names ...string) *apiextensionsv1.CustomResourceDefinition {
+		res := &apiextensionsv1.CustomResourceDefinition{}
+		for _, name := range names {
+			res.Spec.Versions = append(res.Spec.Versions, apiextensionsv1.CustomResourceDefinitionVersion{Name: name})
+		}
+		return res
+	}
+
+	assert.True(t, servesOnly(withVersions("v1alpha1"), withVersions("v1alpha1")))
+	assert.False(t, servesOnly(withVersions("v1alpha1"), withVersions("v1beta1")))
+	assert.False(t, servesOnly(withVersions("v1alpha1", "v1beta1", "vacuum"), withVersions("v1beta1")))
+}
@@ -0,0 +1,65 @@
+package restdefinition
+
+import (
+	"context"
+	"strings"
+
+	definitionv1alpha1 "github.com/krateoplatformops/oasgen-provider/apis/restdefinitions/v1alpha1"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/filegetter"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// oasConfigMapIndex indexes the RestDefinitions by the ConfigMap ('<namespace>/<name>') holding their OAS document.
+	oasConfigMapIndex = "spec.oasPath.configmap"
+	// oasSecretIndex indexes the RestDefinitions by the Secret ('<namespace>/<name>') holding their OAS document.
+	oasSecretIndex = "spec.oasPath.secret"
+)
+
+// indexOASConfigMap returns the ConfigMap holding the OAS document of the RestDefinition, if any.
+// The files referenced by the document are keys of the same ConfigMap (see filegetter.ResolveSibling).
+func indexOASConfigMap(obj client.Object) []string {
+	return indexOASObject(obj, "configmap://", filegetter.ParseConfigMapSource)
+}
+
+// indexOASSecret returns the Secret holding the OAS document of the RestDefinition, if any.
+func indexOASSecret(obj client.Object) []string {
+	return indexOASObject(obj, "secret://", filegetter.ParseSecretSource)
+}
+
+func indexOASObject(obj client.Object, prefix string, parse func(src string) (string, string, string, error)) []string {
+	cr, ok := obj.(*definitionv1alpha1.RestDefinition)
+	if !ok || !strings.HasPrefix(cr.Spec.OASPath, prefix) {
+		return nil
+	}
+	namespace, name, _, err := parse(cr.Spec.OASPath)
+	if err != nil {
+		return nil
+	}
+	return []string{types.NamespacedName{Namespace: namespace, Name: name}.String()}
+}
+
+// restDefinitionsForObject returns a function mapping a ConfigMap or Secret to the requests of the RestDefinitions
+// whose OAS document it holds (looked up with the given index), so that they are reconciled as soon as the document changes.
+func restDefinitionsForObject(kube client.Client, index string, log func(msg string, keysAndValues ...any)) func(ctx context.Context, obj client.Object) []reconcile.Request {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		key := types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}.String()
+
+		list := definitionv1alpha1.RestDefinitionList{}
+		if err := kube.List(ctx, &list, client.MatchingFields{index: key}); err != nil {
+			log("Error listing RestDefinitions referencing OAS source", "source", key, "index", index, "error", err)
+			return nil
+		}
+
+		requests := make([]reconcile.Request, 0, len(list.Items))
+		for _, cr := range list.Items {
+			log("OAS source changed, requeuing RestDefinition", "source", key, "name", cr.Name, "namespace", cr.Namespace)
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: cr.Namespace, Name: cr.Name},
+			})
+		}
+		return requests
+	}
+}
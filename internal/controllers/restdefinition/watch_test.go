@@ -0,0 +1,106 @@
+package restdefinition
+
+import (
+	"context"
+	"testing"
+
+	"github.com/krateoplatformops/oasgen-provider/apis"
+	definitionv1alpha1 "github.com/krateoplatformops/oasgen-provider/apis/restdefinitions/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestIndexOASConfigMap(t *testing.T) {
+	tests := []struct {
+		name    string
+		oasPath string
+		want    []string
+	}{
+		{name: "ConfigMap", oasPath: "configmap://gh-system/specs/openapi.yaml", want: []string{"gh-system/specs"}},
+		{name: "URL", oasPath: "https://example.com/openapi.yaml"},
+		{name: "Secret", oasPath: "secret://gh-system/specs/openapi.yaml"},
+		{name: "Invalid ConfigMap source", oasPath: "configmap://gh-system/specs"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &definitionv1alpha1.RestDefinition{
+				Spec: definitionv1alpha1.RestDefinitionSpec{OASPath: tt.oasPath},
+			}
+			assert.Equal(t, tt.want, indexOASConfigMap(cr))
+		})
+	}
+}
+
+func TestIndexOASSecret(t *testing.T) {
+	tests := []struct {
+		name    string
+		oasPath string
+		want    []string
+	}{
+		{name: "Secret", oasPath: "secret://gh-system/specs/openapi.yaml", want: []string{"gh-system/specs"}},
+		{name: "ConfigMap", oasPath: "configmap://gh-system/specs/openapi.yaml"},
+		{name: "Invalid Secret source", oasPath: "secret://gh-system/specs"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &definitionv1alpha1.RestDefinition{
+				Spec: definitionv1alpha1.RestDefinitionSpec{OASPath: tt.oasPath},
+			}
+			assert.Equal(t, tt.want, indexOASSecret(cr))
+		})
+	}
+}
+
+func TestRestDefinitionsForObject(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, apis.AddToScheme(scheme))
+
+	restDefinition := func(name, oasPath string) *definitionv1alpha1.RestDefinition {
+		return &definitionv1alpha1.RestDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "gh-system"},
+			Spec:       definitionv1alpha1.RestDefinitionSpec{OASPath: oasPath},
+		}
+	}
+	kube := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithIndex(&definitionv1alpha1.RestDefinition{}, oasConfigMapIndex, indexOASConfigMap).
+		WithIndex(&definitionv1alpha1.RestDefinition{}, oasSecretIndex, indexOASSecret).
+		WithObjects(
+			restDefinition("repo", "configmap://gh-system/specs/repo.yaml"),
+			restDefinition("teamrepo", "configmap://gh-system/specs/teamrepo.yaml"),
+			restDefinition("collaborator", "configmap://gh-system/other-specs/collaborator.yaml"),
+			restDefinition("workflow", "https://example.com/workflow.yaml"),
+			restDefinition("runner", "secret://gh-system/specs/runner.yaml"),
+		).
+		Build()
+
+	mapFunc := restDefinitionsForObject(kube, oasConfigMapIndex, func(string, ...any) {})
+
+	requests := mapFunc(context.Background(), &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "specs", Namespace: "gh-system"},
+	})
+	assert.ElementsMatch(t, []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Namespace: "gh-system", Name: "repo"}},
+		{NamespacedName: types.NamespacedName{Namespace: "gh-system", Name: "teamrepo"}},
+	}, requests)
+
+	requests = mapFunc(context.Background(), &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "specs", Namespace: "default"},
+	})
+	assert.Empty(t, requests)
+
+	requests = restDefinitionsForObject(kube, oasSecretIndex, func(string, ...any) {})(context.Background(), &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "specs", Namespace: "gh-system"},
+	})
+	assert.Equal(t, []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Namespace: "gh-system", Name: "runner"}},
+	}, requests)
+}
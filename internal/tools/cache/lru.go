@@ -0,0 +1,103 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+)
+
+// Stats reports the usage of a cache.
+type Stats struct {
+	Hits   uint64
+	Misses uint64
+	// Entries is the number of cached values.
+	Entries int
+	// Size is the total size of the cached values.
+	Size int64
+}
+
+// LRU is a least recently used cache bounded by the total size of its values (e.g., in bytes).
+// It is safe for concurrent use. A nil LRU caches nothing.
+type LRU[K comparable, V any] struct {
+	mu      sync.Mutex
+	maxSize int64
+	size    int64
+	items   map[K]*list.Element
+	order   *list.List
+}
+
+type entry[K comparable, V any] struct {
+	key   K
+	value V
+	size  int64
+}
+
+// NewLRU returns a cache holding values up to the given total size. A non-positive size disables the cache.
+func NewLRU[K comparable, V any](maxSize int64) *LRU[K, V] {
+	return &LRU[K, V]{
+		maxSize: maxSize,
+		items:   map[K]*list.Element{},
+		order:   list.New(),
+	}
+}
+
+// Get returns the value cached for the key, marking it as the most recently used.
+func (c *LRU[K, V]) Get(key K) (V, bool) {
+	var zero V
+	if c == nil {
+		return zero, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.items[key]
+	if !ok {
+		return zero, false
+	}
+	c.order.MoveToFront(el)
+	return el.Value.(*entry[K, V]).value, true
+}
+
+// Add caches the value for the key, evicting the least recently used values to make room for it.
+// Values larger than the size of the cache are not cached.
+func (c *LRU[K, V]) Add(key K, value V, size int64) {
+	if c == nil || c.maxSize <= 0 || size > c.maxSize {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.items[key]; ok {
+		c.removeElement(el)
+	}
+	for c.size+size > c.maxSize {
+		c.removeElement(c.order.Back())
+	}
+	c.items[key] = c.order.PushFront(&entry[K, V]{key: key, value: value, size: size})
+	c.size += size
+}
+
+// Len returns the number of cached values.
+func (c *LRU[K, V]) Len() int {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.items)
+}
+
+// Size returns the total size of the cached values.
+func (c *LRU[K, V]) Size() int64 {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.size
+}
+
+func (c *LRU[K, V]) removeElement(el *list.Element) {
+	e := c.order.Remove(el).(*entry[K, V])
+	delete(c.items, e.key)
+	c.size -= e.size
+}
@@ -0,0 +1,51 @@
+package cache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRU(t *testing.T) {
+	c := NewLRU[string, string](10)
+
+	c.Add("a", "first", 4)
+	c.Add("b", "second", 4)
+	_, ok := c.Get("a")
+	assert.True(t, ok)
+
+	// "b" is the least recently used value, evicted to make room for "c"
+	c.Add("c", "third", 4)
+	_, ok = c.Get("b")
+	assert.False(t, ok)
+	v, ok := c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, "first", v)
+	assert.Equal(t, 2, c.Len())
+	assert.Equal(t, int64(8), c.Size())
+
+	// Replacing a value updates the size
+	c.Add("a", "replaced", 2)
+	v, _ = c.Get("a")
+	assert.Equal(t, "replaced", v)
+	assert.Equal(t, int64(6), c.Size())
+
+	// Values larger than the cache are not cached
+	c.Add("d", "too large", 11)
+	_, ok = c.Get("d")
+	assert.False(t, ok)
+	assert.Equal(t, 2, c.Len())
+}
+
+func TestLRUDisabled(t *testing.T) {
+	c := NewLRU[string, string](0)
+	c.Add("a", "first", 1)
+	_, ok := c.Get("a")
+	assert.False(t, ok)
+
+	var nilCache *LRU[string, string]
+	nilCache.Add("a", "first", 1)
+	_, ok = nilCache.Get("a")
+	assert.False(t, ok)
+	assert.Equal(t, 0, nilCache.Len())
+}
@@ -0,0 +1,191 @@
+package conversion
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/crd"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// StoredVersionAnnotation records the version of the fields of an object stored with the vacuum version
+// (see crd.AppendVersion), as the vacuum version preserves the fields of any version.
+const StoredVersionAnnotation = "ogen.krateo.io/stored-version"
+
+// Types of field mappings
+const (
+	MappingRename  = "rename"
+	MappingMove    = "move"
+	MappingDefault = "default"
+)
+
+// Mapping defines how a field is converted from one version to another.
+type Mapping struct {
+	Type string
+	// From is the dot-separated path of the field in the source version. Not used by default mappings.
+	From string
+	// To is the new name of the field for rename mappings, the dot-separated path of the field in the target version otherwise.
+	To string
+	// Value is the value set by default mappings.
+	Value any
+}
+
+// Conversion defines how objects are converted from one version to another.
+// Objects are converted back using the inverse of the rename and move mappings.
+type Conversion struct {
+	From     string
+	To       string
+	Mappings []Mapping
+}
+
+// Convert converts the object to the given version, applying the mappings of the conversions
+// on the shortest path from the version of the object. Objects are converted without changing
+// their fields when no path is declared.
+func Convert(obj *unstructured.Unstructured, version string, conversions []Conversion) error {
+	gv, err := schema.ParseGroupVersion(obj.GetAPIVersion())
+	if err != nil {
+		return fmt.Errorf("parsing API version of %s: %w", obj.GetName(), err)
+	}
+
+	from := gv.Version
+	if from == crd.VacuumVersion {
+		from = storedVersion(obj, version)
+	}
+
+	if version == crd.VacuumVersion {
+		if from != crd.VacuumVersion {
+			setStoredVersion(obj, from)
+		}
+	} else {
+		setStoredVersion(obj, "")
+		for _, mappings := range conversionPath(conversions, from, version) {
+			for _, m := range mappings {
+				if err := apply(obj.Object, m); err != nil {
+					return fmt.Errorf("converting %s from %s to %s: %w", obj.GetName(), from, version, err)
+				}
+			}
+		}
+	}
+
+	obj.SetAPIVersion(schema.GroupVersion{Group: gv.Group, Version: version}.String())
+	return nil
+}
+
+// storedVersion returns the version of the fields of an object stored with the vacuum version.
+// Objects stored before the annotation was set are assumed to be already in the given version.
+func storedVersion(obj *unstructured.Unstructured, version string) string {
+	if v := obj.GetAnnotations()[StoredVersionAnnotation]; v != "" {
+		return v
+	}
+	return version
+}
+
+func setStoredVersion(obj *unstructured.Unstructured, version string) {
+	annotations := obj.GetAnnotations()
+	if version == "" {
+		if _, ok := annotations[StoredVersionAnnotation]; !ok {
+			return
+		}
+		delete(annotations, StoredVersionAnnotation)
+	} else {
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[StoredVersionAnnotation] = version
+	}
+	obj.SetAnnotations(annotations)
+}
+
+// conversionPath returns the mappings to apply, step by step, to convert an object from one version to another.
+func conversionPath(conversions []Conversion, from, to string) [][]Mapping {
+	type edge struct {
+		to       string
+		mappings []Mapping
+	}
+	graph := map[string][]edge{}
+	for _, c := range conversions {
+		graph[c.From] = append(graph[c.From], edge{to: c.To, mappings: c.Mappings})
+		graph[c.To] = append(graph[c.To], edge{to: c.From, mappings: inverse(c.Mappings)})
+	}
+
+	// Breadth-first search, so the path with the fewest conversions is used
+	prev := map[string]string{from: ""}
+	steps := map[string][]Mapping{}
+	queue := []string{from}
+	for len(queue) > 0 && queue[0] != to {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, e := range graph[cur] {
+			if _, ok := prev[e.to]; ok {
+				continue
+			}
+			prev[e.to] = cur
+			steps[e.to] = e.mappings
+			queue = append(queue, e.to)
+		}
+	}
+	if _, ok := prev[to]; !ok || from == to {
+		return nil
+	}
+
+	var path [][]Mapping
+	for v := to; v != from; v = prev[v] {
+		path = append([][]Mapping{steps[v]}, path...)
+	}
+	return path
+}
+
+// inverse returns the mappings that undo the given ones. Default mappings have no inverse,
+// as fields unknown to the target version are pruned by the API server.
+func inverse(mappings []Mapping) []Mapping {
+	res := make([]Mapping, 0, len(mappings))
+	for i := len(mappings) - 1; i >= 0; i-- {
+		m := mappings[i]
+		switch m.Type {
+		case MappingRename:
+			res = append(res, Mapping{Type: MappingMove, From: renamedPath(m), To: m.From})
+		case MappingMove:
+			res = append(res, Mapping{Type: MappingMove, From: m.To, To: m.From})
+		}
+	}
+	return res
+}
+
+// renamedPath returns the path of the field renamed by the mapping.
+func renamedPath(m Mapping) string {
+	idx := strings.LastIndex(m.From, ".")
+	if idx < 0 {
+		return m.To
+	}
+	return m.From[:idx+1] + m.To
+}
+
+func apply(obj map[string]any, m Mapping) error {
+	switch m.Type {
+	case MappingRename:
+		return move(obj, m.From, renamedPath(m))
+	case MappingMove:
+		return move(obj, m.From, m.To)
+	case MappingDefault:
+		to := strings.Split(m.To, ".")
+		_, found, err := unstructured.NestedFieldNoCopy(obj, to...)
+		if err != nil || found {
+			return err
+		}
+		return unstructured.SetNestedField(obj, runtime.DeepCopyJSONValue(m.Value), to...)
+	default:
+		return fmt.Errorf("unknown mapping type '%s'", m.Type)
+	}
+}
+
+func move(obj map[string]any, from, to string) error {
+	src := strings.Split(from, ".")
+	val, found, err := unstructured.NestedFieldNoCopy(obj, src...)
+	if err != nil || !found {
+		return err
+	}
+	unstructured.RemoveNestedField(obj, src...)
+	return unstructured.SetNestedField(obj, val, strings.Split(to, ".")...)
+}
@@ -0,0 +1,134 @@
+package conversion
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestConvert(t *testing.T) {
+	conversions := []Conversion{
+		{
+			From: "v1alpha1",
+			To:   "v1beta1",
+			Mappings: []Mapping{
+				{Type: MappingRename, From: "spec.name", To: "displayName"},
+				{Type: MappingMove, From: "spec.owner", To: "spec.ownership.owner"},
+				{Type: MappingDefault, To: "spec.visibility", Value: "private"},
+			},
+		},
+		{
+			From: "v1beta1",
+			To:   "v1",
+			Mappings: []Mapping{
+				{Type: MappingRename, From: "spec.displayName", To: "title"},
+			},
+		},
+	}
+
+	object := func(apiVersion string, spec map[string]any, annotations map[string]string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": apiVersion,
+			"kind":       "Repo",
+			"metadata":   map[string]any{"name": "test"},
+			"spec":       spec,
+		}}
+		if annotations != nil {
+			obj.SetAnnotations(annotations)
+		}
+		return obj
+	}
+
+	tests := []struct {
+		name     string
+		obj      *unstructured.Unstructured
+		version  string
+		expected *unstructured.Unstructured
+	}{
+		{
+			name:    "Forward conversion",
+			obj:     object("github.ogen.krateo.io/v1alpha1", map[string]any{"name": "repo", "owner": "me"}, nil),
+			version: "v1beta1",
+			expected: object("github.ogen.krateo.io/v1beta1", map[string]any{
+				"displayName": "repo",
+				"ownership":   map[string]any{"owner": "me"},
+				"visibility":  "private",
+			}, nil),
+		},
+		{
+			name:    "Default does not override existing value",
+			obj:     object("github.ogen.krateo.io/v1alpha1", map[string]any{"name": "repo", "visibility": "public"}, nil),
+			version: "v1beta1",
+			expected: object("github.ogen.krateo.io/v1beta1", map[string]any{
+				"displayName": "repo",
+				"visibility":  "public",
+			}, nil),
+		},
+		{
+			name: "Backward conversion",
+			obj: object("github.ogen.krateo.io/v1beta1", map[string]any{
+				"displayName": "repo",
+				"ownership":   map[string]any{"owner": "me"},
+				"visibility":  "private",
+			}, nil),
+			version: "v1alpha1",
+			expected: object("github.ogen.krateo.io/v1alpha1", map[string]any{
+				"name":       "repo",
+				"owner":      "me",
+				"ownership":  map[string]any{},
+				"visibility": "private",
+			}, nil),
+		},
+		{
+			name:    "Multiple steps",
+			obj:     object("github.ogen.krateo.io/v1alpha1", map[string]any{"name": "repo"}, nil),
+			version: "v1",
+			expected: object("github.ogen.krateo.io/v1", map[string]any{
+				"title":      "repo",
+				"visibility": "private",
+			}, nil),
+		},
+		{
+			name:     "No conversion path",
+			obj:      object("github.ogen.krateo.io/v1alpha1", map[string]any{"name": "repo"}, nil),
+			version:  "v2",
+			expected: object("github.ogen.krateo.io/v2", map[string]any{"name": "repo"}, nil),
+		},
+		{
+			name:    "To vacuum version",
+			obj:     object("github.ogen.krateo.io/v1beta1", map[string]any{"displayName": "repo"}, nil),
+			version: "vacuum",
+			expected: object("github.ogen.krateo.io/vacuum", map[string]any{"displayName": "repo"},
+				map[string]string{StoredVersionAnnotation: "v1beta1"}),
+		},
+		{
+			name:     "From vacuum version",
+			obj:      object("github.ogen.krateo.io/vacuum", map[string]any{"displayName": "repo"}, map[string]string{StoredVersionAnnotation: "v1beta1"}),
+			version:  "v1",
+			expected: object("github.ogen.krateo.io/v1", map[string]any{"title": "repo"}, map[string]string{}),
+		},
+		{
+			name:     "From vacuum version without stored version",
+			obj:      object("github.ogen.krateo.io/vacuum", map[string]any{"name": "repo"}, nil),
+			version:  "v1beta1",
+			expected: object("github.ogen.krateo.io/v1beta1", map[string]any{"name": "repo"}, nil),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, Convert(tt.obj, tt.version, conversions))
+			assert.Equal(t, tt.expected.Object, tt.obj.Object)
+		})
+	}
+}
+
+func TestConvertUnknownMapping(t *testing.T) {
+	obj := &unstructured.Unstructured{Object: map[string]any{"apiVersion": "github.ogen.krateo.io/v1alpha1"}}
+	err := Convert(obj, "v1beta1", []Conversion{
+		{From: "v1alpha1", To: "v1beta1", Mappings: []Mapping{{Type: "copy", From: "spec.a", To: "spec.b"}}},
+	})
+	assert.ErrorContains(t, err, "unknown mapping type 'copy'")
+}
@@ -0,0 +1,108 @@
+package conversion
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Path is the path the conversion webhook is served at.
+const Path = "/convert"
+
+// ConversionsFunc returns the conversions declared for the objects of the given group kind.
+type ConversionsFunc func(ctx context.Context, gk schema.GroupKind) ([]Conversion, error)
+
+// Webhook is the handler of the ConversionReview requests sent by the API server for CRDs
+// with the Webhook conversion strategy.
+type Webhook struct {
+	conversions ConversionsFunc
+	log         func(msg string, keysAndValues ...any)
+}
+
+func NewWebhook(conversions ConversionsFunc, log func(msg string, keysAndValues ...any)) *Webhook {
+	return &Webhook{
+		conversions: conversions,
+		log:         log,
+	}
+}
+
+func (w *Webhook) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	review := apiextensionsv1.ConversionReview{}
+	if err := json.NewDecoder(req.Body).Decode(&review); err != nil {
+		http.Error(rw, fmt.Sprintf("decoding conversion review: %s", err), http.StatusBadRequest)
+		return
+	}
+	if review.Request == nil {
+		http.Error(rw, "conversion review has no request", http.StatusBadRequest)
+		return
+	}
+
+	review.Response = w.convert(req.Context(), review.Request)
+	review.Request = nil
+
+	rw.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(rw).Encode(&review); err != nil {
+		w.log("Error encoding conversion review", "error", err)
+	}
+}
+
+func (w *Webhook) convert(ctx context.Context, req *apiextensionsv1.ConversionRequest) *apiextensionsv1.ConversionResponse {
+	res := &apiextensionsv1.ConversionResponse{
+		UID: req.UID,
+		Result: metav1.Status{
+			Status: metav1.StatusSuccess,
+		},
+	}
+
+	gv, err := schema.ParseGroupVersion(req.DesiredAPIVersion)
+	if err != nil {
+		return failure(res, fmt.Errorf("parsing desired API version: %w", err))
+	}
+
+	cache := map[schema.GroupKind][]Conversion{}
+	for _, raw := range req.Objects {
+		obj := &unstructured.Unstructured{}
+		if err := obj.UnmarshalJSON(raw.Raw); err != nil {
+			return failure(res, fmt.Errorf("decoding object: %w", err))
+		}
+
+		gk := obj.GroupVersionKind().GroupKind()
+		conversions, ok := cache[gk]
+		if !ok {
+			conversions, err = w.conversions(ctx, gk)
+			if err != nil {
+				return failure(res, fmt.Errorf("getting conversions of %s: %w", gk.String(), err))
+			}
+			cache[gk] = conversions
+		}
+
+		if err := Convert(obj, gv.Version, conversions); err != nil {
+			return failure(res, err)
+		}
+
+		dat, err := obj.MarshalJSON()
+		if err != nil {
+			return failure(res, fmt.Errorf("encoding object: %w", err))
+		}
+		res.ConvertedObjects = append(res.ConvertedObjects, runtime.RawExtension{Raw: dat})
+	}
+
+	w.log("Converted objects", "count", len(res.ConvertedObjects), "desiredAPIVersion", req.DesiredAPIVersion)
+	return res
+}
+
+func failure(res *apiextensionsv1.ConversionResponse, err error) *apiextensionsv1.ConversionResponse {
+	res.ConvertedObjects = nil
+	res.Result = metav1.Status{
+		Status:  metav1.StatusFailure,
+		Message: err.Error(),
+	}
+	return res
+}
@@ -0,0 +1,77 @@
+package conversion
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestWebhook(t *testing.T) {
+	conversions := func(_ context.Context, gk schema.GroupKind) ([]Conversion, error) {
+		if gk.Kind != "Repo" {
+			return nil, fmt.Errorf("unknown kind %s", gk.Kind)
+		}
+		return []Conversion{
+			{From: "v1alpha1", To: "v1beta1", Mappings: []Mapping{{Type: MappingRename, From: "spec.name", To: "displayName"}}},
+		}, nil
+	}
+	wh := NewWebhook(conversions, func(string, ...any) {})
+
+	review := func(kind string) *apiextensionsv1.ConversionReview {
+		obj := fmt.Sprintf(`{"apiVersion":"github.ogen.krateo.io/v1alpha1","kind":"%s","metadata":{"name":"test"},"spec":{"name":"repo"}}`, kind)
+		req := apiextensionsv1.ConversionReview{
+			TypeMeta: metav1.TypeMeta{APIVersion: "apiextensions.k8s.io/v1", Kind: "ConversionReview"},
+			Request: &apiextensionsv1.ConversionRequest{
+				UID:               "uid",
+				DesiredAPIVersion: "github.ogen.krateo.io/v1beta1",
+				Objects:           []runtime.RawExtension{{Raw: []byte(obj)}},
+			},
+		}
+		body, err := json.Marshal(req)
+		require.NoError(t, err)
+
+		rec := httptest.NewRecorder()
+		wh.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, Path, bytes.NewReader(body)))
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		res := &apiextensionsv1.ConversionReview{}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), res))
+		require.NotNil(t, res.Response)
+		assert.Nil(t, res.Request)
+		assert.Equal(t, "uid", string(res.Response.UID))
+		return res
+	}
+
+	t.Run("Converted", func(t *testing.T) {
+		res := review("Repo")
+		assert.Equal(t, metav1.StatusSuccess, res.Response.Result.Status)
+		require.Len(t, res.Response.ConvertedObjects, 1)
+		assert.JSONEq(t,
+			`{"apiVersion":"github.ogen.krateo.io/v1beta1","kind":"Repo","metadata":{"name":"test"},"spec":{"displayName":"repo"}}`,
+			string(res.Response.ConvertedObjects[0].Raw))
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		res := review("Team")
+		assert.Equal(t, metav1.StatusFailure, res.Response.Result.Status)
+		assert.Contains(t, res.Response.Result.Message, "unknown kind Team")
+		assert.Empty(t, res.Response.ConvertedObjects)
+	})
+
+	t.Run("Bad request", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		wh.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, Path, bytes.NewReader([]byte("{}"))))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
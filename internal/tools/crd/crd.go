@@ -65,6 +65,24 @@ func Lookup(ctx context.Context, kube client.Client, gvr schema.GroupVersionReso
 	return false, nil
 }
 
+// Get returns the CRD of the given group resource, or nil if it is not installed.
+func Get(ctx context.Context, kube client.Client, gr schema.GroupResource) (*apiextensionsv1.CustomResourceDefinition, error) {
+	if err := registerEventually(); err != nil {
+		return nil, err
+	}
+
+	res := apiextensionsv1.CustomResourceDefinition{}
+	err := kube.Get(ctx, client.ObjectKey{Name: gr.String()}, &res, &client.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &res, nil
+}
+
 func Unmarshal(dat []byte) (*apiextensionsv1.CustomResourceDefinition, error) {
 	if err := registerEventually(); err != nil {
 		return nil, err
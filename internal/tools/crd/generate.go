@@ -0,0 +1,195 @@
+package crd
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/krateoplatformops/plumbing/crdgen"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+// crdgen converts the JSON schemas into Go types annotated with kubebuilder markers.
+// This conversion loses many keywords (e.g., minLength, minItems, x-kubernetes-validations)
+// and fails on the "number" type (controller-gen does not allow floats).
+// Therefore crdgen is used only to generate the CRD envelope (names, printer columns, subresources,
+// status conditions) from a placeholder schema, and the actual spec and status schemas are set afterwards.
+const placeholderProperty = "placeholder"
+
+var placeholderSchema = []byte(`{"type":"object","properties":{"` + placeholderProperty + `":{"type":"string"}}}`)
+
+// Generate generates a CRD with crdgen and sets the spec and status schemas of the generated version
+// with the JSON schemas in opts, preserving the properties added by crdgen (e.g., status conditions).
+func Generate(opts crdgen.Options) (*apiextensionsv1.CustomResourceDefinition, error) {
+	specSchema, statusSchema := opts.SpecSchema, opts.StatusSchema
+
+	opts.SpecSchema = placeholderSchema
+	if len(statusSchema) > 0 {
+		opts.StatusSchema = placeholderSchema
+	}
+
+	res, err := crdgen.Generate(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	crdu, err := Unmarshal(res)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshalling CRD: %w", err)
+	}
+
+	if err := SetSchema(crdu, opts.Version, "spec", specSchema); err != nil {
+		return nil, err
+	}
+	if err := SetSchema(crdu, opts.Version, "status", statusSchema); err != nil {
+		return nil, err
+	}
+
+	return crdu, nil
+}
+
+// SetSchema sets the schema of the given root field (e.g., "spec") of the given CRD version with the JSON schema.
+// The properties of the current schema that are not defined in the JSON schema are preserved,
+// except for the crdgen placeholder.
+func SetSchema(crd *apiextensionsv1.CustomResourceDefinition, version, field string, schema []byte) error {
+	if len(schema) == 0 {
+		return nil
+	}
+
+	props, err := StructuralSchema(schema)
+	if err != nil {
+		return fmt.Errorf("converting %s schema: %w", field, err)
+	}
+
+	for i := range crd.Spec.Versions {
+		ver := &crd.Spec.Versions[i]
+		if ver.Name != version || ver.Schema == nil || ver.Schema.OpenAPIV3Schema == nil {
+			continue
+		}
+
+		root := ver.Schema.OpenAPIV3Schema
+		current, ok := root.Properties[field]
+		if !ok {
+			return fmt.Errorf("field %q not found in version %q", field, version)
+		}
+
+		for name, prop := range current.Properties {
+			if name == placeholderProperty {
+				continue
+			}
+			if _, exists := props.Properties[name]; !exists {
+				if props.Properties == nil {
+					props.Properties = map[string]apiextensionsv1.JSONSchemaProps{}
+				}
+				props.Properties[name] = prop
+			}
+		}
+		props.Required = append(props.Required, requiredWithout(current.Required, placeholderProperty, props.Required)...)
+
+		root.Properties[field] = *props
+		return nil
+	}
+
+	return fmt.Errorf("version %q not found", version)
+}
+
+// StructuralSchema converts a JSON schema into JSONSchemaProps, normalizing the nodes
+// that would not be valid in a Kubernetes structural schema (see normalizeStructural).
+func StructuralSchema(schema []byte) (*apiextensionsv1.JSONSchemaProps, error) {
+	var m map[string]interface{}
+	if err := json.Unmarshal(schema, &m); err != nil {
+		return nil, err
+	}
+
+	normalizeStructural(m)
+
+	dat, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+
+	props := &apiextensionsv1.JSONSchemaProps{}
+	if err := json.Unmarshal(dat, props); err != nil {
+		return nil, err
+	}
+	return props, nil
+}
+
+// normalizeStructural recursively normalizes a JSON schema node so that it is valid in a structural schema:
+//   - a list of types (OAS 3.1) is replaced by its non-null type, and "null" becomes nullable: true;
+//   - a missing type is inferred from properties, additionalProperties and items, otherwise the node preserves unknown fields;
+//   - objects without properties and boolean additionalProperties become nodes preserving unknown fields;
+//   - arrays without items get items preserving unknown fields.
+//
+// These are the same choices crdgen made for these nodes.
+func normalizeStructural(m map[string]interface{}) {
+	if types, ok := m["type"].([]interface{}); ok {
+		delete(m, "type")
+		for _, t := range types {
+			if t == "null" {
+				m["nullable"] = true
+			} else if _, set := m["type"]; !set {
+				m["type"] = t
+			}
+		}
+	}
+
+	props, hasProps := m["properties"].(map[string]interface{})
+	items, hasItems := m["items"].(map[string]interface{})
+	_, hasMapValues := m["additionalProperties"].(map[string]interface{})
+
+	if _, ok := m["type"]; !ok {
+		switch {
+		case hasProps, hasMapValues:
+			m["type"] = "object"
+		case hasItems:
+			m["type"] = "array"
+		case m["x-kubernetes-int-or-string"] == true:
+			// int-or-string nodes must not have a type
+		default:
+			m["x-kubernetes-preserve-unknown-fields"] = true
+		}
+	}
+
+	if ap, ok := m["additionalProperties"].(bool); ok {
+		delete(m, "additionalProperties")
+		if ap {
+			m["x-kubernetes-preserve-unknown-fields"] = true
+		}
+	}
+
+	switch m["type"] {
+	case "object":
+		if !hasProps && m["additionalProperties"] == nil {
+			m["x-kubernetes-preserve-unknown-fields"] = true
+		}
+	case "array":
+		if !hasItems {
+			items = map[string]interface{}{}
+			m["items"] = items
+		}
+	}
+
+	for _, prop := range props {
+		if pm, ok := prop.(map[string]interface{}); ok {
+			normalizeStructural(pm)
+		}
+	}
+	if items != nil {
+		normalizeStructural(items)
+	}
+	if ap, ok := m["additionalProperties"].(map[string]interface{}); ok {
+		normalizeStructural(ap)
+	}
+}
+
+// requiredWithout returns the elements of required that are not the excluded one and are not already in existing.
+func requiredWithout(required []string, excluded string, existing []string) []string {
+	var res []string
+	for _, r := range required {
+		if r != excluded && !slices.Contains(existing, r) {
+			res = append(res, r)
+		}
+	}
+	return res
+}
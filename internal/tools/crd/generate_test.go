@@ -0,0 +1,151 @@
+package crd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+func TestStructuralSchema(t *testing.T) {
+	schema := []byte(`{
+		"type": "object",
+		"required": ["name"],
+		"properties": {
+			"name": {"type": "string", "minLength": 1, "maxLength": 63, "pattern": "^[a-z]+$"},
+			"ratio": {"type": ["number", "null"], "minimum": 0, "exclusiveMinimum": true, "multipleOf": 0.5},
+			"tags": {"type": "array", "items": {"type": "string"}, "minItems": 1, "x-kubernetes-list-type": "set"},
+			"labels": {"type": "object", "additionalProperties": true},
+			"annotations": {"additionalProperties": {"type": "string", "maxLength": 63}},
+			"free": {"type": "object"},
+			"anything": {},
+			"list": {"type": "array"},
+			"port": {"x-kubernetes-int-or-string": true},
+			"nested": {"properties": {"a": {"type": "string"}}, "x-kubernetes-validations": [{"rule": "has(self.a)"}]}
+		}
+	}`)
+
+	props, err := StructuralSchema(schema)
+	require.NoError(t, err)
+
+	assert.Equal(t, "object", props.Type)
+	assert.Equal(t, []string{"name"}, props.Required)
+
+	name := props.Properties["name"]
+	assert.Equal(t, int64(1), *name.MinLength)
+	assert.Equal(t, int64(63), *name.MaxLength)
+	assert.Equal(t, "^[a-z]+$", name.Pattern)
+
+	ratio := props.Properties["ratio"]
+	assert.Equal(t, "number", ratio.Type)
+	assert.True(t, ratio.Nullable)
+	assert.Equal(t, float64(0), *ratio.Minimum)
+	assert.True(t, ratio.ExclusiveMinimum)
+	assert.Equal(t, 0.5, *ratio.MultipleOf)
+
+	tags := props.Properties["tags"]
+	assert.Equal(t, int64(1), *tags.MinItems)
+	assert.Equal(t, "set", *tags.XListType)
+
+	labels := props.Properties["labels"]
+	assert.Nil(t, labels.AdditionalProperties)
+	assert.True(t, *labels.XPreserveUnknownFields)
+
+	annotations := props.Properties["annotations"]
+	assert.Equal(t, "object", annotations.Type)
+	assert.Nil(t, annotations.XPreserveUnknownFields)
+	require.NotNil(t, annotations.AdditionalProperties)
+	assert.Equal(t, "string", annotations.AdditionalProperties.Schema.Type)
+	assert.Equal(t, int64(63), *annotations.AdditionalProperties.Schema.MaxLength)
+
+	assert.True(t, *props.Properties["free"].XPreserveUnknownFields)
+
+	anything := props.Properties["anything"]
+	assert.Empty(t, anything.Type)
+	assert.True(t, *anything.XPreserveUnknownFields)
+
+	list := props.Properties["list"]
+	require.NotNil(t, list.Items)
+	assert.True(t, *list.Items.Schema.XPreserveUnknownFields)
+
+	port := props.Properties["port"]
+	assert.Empty(t, port.Type)
+	assert.True(t, port.XIntOrString)
+	assert.Nil(t, port.XPreserveUnknownFields)
+
+	nested := props.Properties["nested"]
+	assert.Equal(t, "object", nested.Type)
+	require.Len(t, nested.XValidations, 1)
+	assert.Equal(t, "has(self.a)", nested.XValidations[0].Rule)
+}
+
+func TestSetSchema(t *testing.T) {
+	newCRD := func() *apiextensionsv1.CustomResourceDefinition {
+		return &apiextensionsv1.CustomResourceDefinition{
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+					{
+						Name: "v1alpha1",
+						Schema: &apiextensionsv1.CustomResourceValidation{
+							OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{
+								Type: "object",
+								Properties: map[string]apiextensionsv1.JSONSchemaProps{
+									"spec": {
+										Type: "object",
+										Properties: map[string]apiextensionsv1.JSONSchemaProps{
+											placeholderProperty: {Type: "string"},
+										},
+									},
+									"status": {
+										Type:     "object",
+										Required: []string{"conditions"},
+										Properties: map[string]apiextensionsv1.JSONSchemaProps{
+											placeholderProperty: {Type: "string"},
+											"conditions":        {Type: "array"},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	t.Run("replaces the placeholder and preserves generated properties", func(t *testing.T) {
+		crd := newCRD()
+
+		require.NoError(t, SetSchema(crd, "v1alpha1", "spec", []byte(`{"type":"object","properties":{"name":{"type":"string","minLength":1}}}`)))
+		require.NoError(t, SetSchema(crd, "v1alpha1", "status", []byte(`{"type":"object","required":["id"],"properties":{"id":{"type":"number"}}}`)))
+
+		root := crd.Spec.Versions[0].Schema.OpenAPIV3Schema
+
+		spec := root.Properties["spec"]
+		assert.NotContains(t, spec.Properties, placeholderProperty)
+		assert.Equal(t, int64(1), *spec.Properties["name"].MinLength)
+
+		status := root.Properties["status"]
+		assert.NotContains(t, status.Properties, placeholderProperty)
+		assert.Equal(t, "number", status.Properties["id"].Type)
+		assert.Equal(t, "array", status.Properties["conditions"].Type)
+		assert.Equal(t, []string{"id", "conditions"}, status.Required)
+	})
+
+	t.Run("empty schema is a no-op", func(t *testing.T) {
+		crd := newCRD()
+		require.NoError(t, SetSchema(crd, "v1alpha1", "spec", nil))
+		assert.Contains(t, crd.Spec.Versions[0].Schema.OpenAPIV3Schema.Properties["spec"].Properties, placeholderProperty)
+	})
+
+	t.Run("unknown version", func(t *testing.T) {
+		crd := newCRD()
+		assert.Error(t, SetSchema(crd, "v2", "spec", []byte(`{"type":"object"}`)))
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		crd := newCRD()
+		assert.Error(t, SetSchema(crd, "v1alpha1", "other", []byte(`{"type":"object"}`)))
+	})
+}
@@ -0,0 +1,482 @@
+package crd
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// VersionSchema returns the OpenAPI schema of the given CRD version, or nil if the version does not exist.
+func VersionSchema(crd *apiextensionsv1.CustomResourceDefinition, version string) *apiextensionsv1.JSONSchemaProps {
+	for i := range crd.Spec.Versions {
+		ver := &crd.Spec.Versions[i]
+		if ver.Name == version && ver.Schema != nil {
+			return ver.Schema.OpenAPIV3Schema
+		}
+	}
+	return nil
+}
+
+// SetVersionSchema replaces the OpenAPI schema of the given CRD version.
+func SetVersionSchema(crd *apiextensionsv1.CustomResourceDefinition, version string, schema *apiextensionsv1.JSONSchemaProps) error {
+	for i := range crd.Spec.Versions {
+		ver := &crd.Spec.Versions[i]
+		if ver.Name == version {
+			ver.Schema = &apiextensionsv1.CustomResourceValidation{OpenAPIV3Schema: schema}
+			return nil
+		}
+	}
+	return fmt.Errorf("version %q not found", version)
+}
+
+// ChangeType classifies a change between two versions of a CRD schema by its impact on existing objects.
+type ChangeType string
+
+const (
+	// ChangeAdditive is a change that existing objects survive (e.g., new optional field, widened enum).
+	ChangeAdditive ChangeType = "Additive"
+	// ChangeNarrowing is a change that existing objects could not satisfy anymore
+	// (e.g., new required field, tighter enum, changed type).
+	ChangeNarrowing ChangeType = "Narrowing"
+	// ChangeDestructive is a change that drops data of existing objects (e.g., removed field).
+	ChangeDestructive ChangeType = "Destructive"
+)
+
+// SchemaChange is a single change between two versions of a CRD schema.
+type SchemaChange struct {
+	// Path is the path of the changed field (e.g., 'spec.tags[]' or 'spec.labels{}').
+	Path        string
+	Type        ChangeType
+	Description string
+}
+
+// Breaking reports whether existing objects could not survive the change.
+func (c SchemaChange) Breaking() bool {
+	return c.Type != ChangeAdditive
+}
+
+func (c SchemaChange) String() string {
+	return fmt.Sprintf("%s: %s", c.Type, c.Description)
+}
+
+// DiffSchemas returns the changes from the installed schema to the desired one, classified by their impact
+// on existing objects. Changes that do not affect validation (e.g., descriptions) are not reported.
+func DiffSchemas(installed, desired *apiextensionsv1.JSONSchemaProps) []SchemaChange {
+	var changes []SchemaChange
+	diffSchemasRec("", installed, desired, &changes)
+	return changes
+}
+
+// BreakingChanges returns the changes that existing objects could not survive.
+func BreakingChanges(changes []SchemaChange) []SchemaChange {
+	var res []SchemaChange
+	for _, c := range changes {
+		if c.Breaking() {
+			res = append(res, c)
+		}
+	}
+	return res
+}
+
+func diffSchemasRec(path string, installed, desired *apiextensionsv1.JSONSchemaProps, changes *[]SchemaChange) {
+	if installed == nil || desired == nil {
+		return
+	}
+
+	widened := false
+	if installed.Type != desired.Type {
+		// Every integer is a valid number, so existing objects are still valid
+		if installed.Type != "integer" || desired.Type != "number" {
+			*changes = append(*changes, SchemaChange{
+				Path:        displayPath(path),
+				Type:        ChangeNarrowing,
+				Description: fmt.Sprintf("type of field '%s' changed from '%s' to '%s'", displayPath(path), installed.Type, desired.Type),
+			})
+			return
+		}
+		widened = true
+		*changes = append(*changes, SchemaChange{
+			Path:        displayPath(path),
+			Type:        ChangeAdditive,
+			Description: fmt.Sprintf("type of field '%s' widened from '%s' to '%s'", displayPath(path), installed.Type, desired.Type),
+		})
+	}
+
+	diffEnum(path, installed.Enum, desired.Enum, changes)
+	if widened {
+		// The formats depend on the type (e.g., int64 and double), so they are not compared
+		constrained := *desired
+		constrained.Format = installed.Format
+		diffConstraints(path, installed, &constrained, changes)
+	} else {
+		diffConstraints(path, installed, desired, changes)
+	}
+	diffValidationRules(path, installed.XValidations, desired.XValidations, changes)
+
+	wasRequired := sets.New(installed.Required...)
+	for _, name := range sortedKeys(installed.Properties) {
+		fieldPath := joinPath(path, name)
+		desiredProp, ok := desired.Properties[name]
+		if !ok {
+			*changes = append(*changes, SchemaChange{
+				Path:        fieldPath,
+				Type:        ChangeDestructive,
+				Description: fmt.Sprintf("field '%s' removed", fieldPath),
+			})
+			continue
+		}
+		installedProp := installed.Properties[name]
+		diffSchemasRec(fieldPath, &installedProp, &desiredProp, changes)
+	}
+
+	for _, name := range sortedKeys(desired.Properties) {
+		if _, ok := installed.Properties[name]; ok {
+			continue
+		}
+		fieldPath := joinPath(path, name)
+		*changes = append(*changes, SchemaChange{
+			Path:        fieldPath,
+			Type:        ChangeAdditive,
+			Description: fmt.Sprintf("field '%s' added", fieldPath),
+		})
+	}
+
+	isRequired := sets.New(desired.Required...)
+	for _, name := range sets.List(isRequired.Difference(wasRequired)) {
+		fieldPath := joinPath(path, name)
+		*changes = append(*changes, SchemaChange{
+			Path:        fieldPath,
+			Type:        ChangeNarrowing,
+			Description: fmt.Sprintf("field '%s' is now required", fieldPath),
+		})
+	}
+	for _, name := range sets.List(wasRequired.Difference(isRequired)) {
+		fieldPath := joinPath(path, name)
+		*changes = append(*changes, SchemaChange{
+			Path:        fieldPath,
+			Type:        ChangeAdditive,
+			Description: fmt.Sprintf("field '%s' is not required anymore", fieldPath),
+		})
+	}
+
+	if installed.Items != nil && desired.Items != nil {
+		diffSchemasRec(path+"[]", installed.Items.Schema, desired.Items.Schema, changes)
+	}
+	if installed.AdditionalProperties != nil && desired.AdditionalProperties != nil {
+		diffSchemasRec(path+"{}", installed.AdditionalProperties.Schema, desired.AdditionalProperties.Schema, changes)
+	}
+
+	if !equality.Semantic.DeepEqual(unclassified(installed, desired), unclassified(desired, installed)) {
+		*changes = append(*changes, SchemaChange{
+			Path:        displayPath(path),
+			Type:        ChangeNarrowing,
+			Description: fmt.Sprintf("schema of field '%s' changed in a way that cannot be classified", displayPath(path)),
+		})
+	}
+}
+
+// unclassified returns a copy of the schema without the keywords compared by diffSchemasRec (for this field only)
+// and the ones not affecting validation (e.g., description), given the schema it is compared with.
+// Any difference left cannot be classified, and is assumed to be breaking.
+func unclassified(schema, other *apiextensionsv1.JSONSchemaProps) apiextensionsv1.JSONSchemaProps {
+	res := *schema
+
+	// Not affecting validation
+	res.ID = ""
+	res.Schema = ""
+	res.Description = ""
+	res.Title = ""
+	res.Default = nil
+	res.Example = nil
+	res.ExternalDocs = nil
+
+	// Compared by diffSchemasRec
+	res.Type = ""
+	res.Enum = nil
+	res.Required = nil
+	res.Properties = nil
+	res.Pattern = ""
+	res.Format = ""
+	res.Minimum, res.Maximum, res.MultipleOf = nil, nil, nil
+	res.ExclusiveMinimum, res.ExclusiveMaximum = false, false
+	res.MinLength, res.MaxLength = nil, nil
+	res.MinItems, res.MaxItems = nil, nil
+	res.MinProperties, res.MaxProperties = nil, nil
+	res.UniqueItems = false
+	res.Nullable = false
+	res.XValidations = nil
+	res.XPreserveUnknownFields = nil
+
+	// Items and map values are compared only if both schemas define them with a schema
+	if res.Items != nil && other.Items != nil && res.Items.Schema != nil && other.Items.Schema != nil {
+		res.Items = nil
+	}
+	if res.AdditionalProperties != nil && other.AdditionalProperties != nil &&
+		res.AdditionalProperties.Schema != nil && other.AdditionalProperties.Schema != nil {
+		res.AdditionalProperties = &apiextensionsv1.JSONSchemaPropsOrBool{Allows: res.AdditionalProperties.Allows}
+	}
+	return res
+}
+
+// diffConstraints reports the changes of the validation keywords constraining the values of a field:
+// new or tighter constraints are narrowing, removed or looser ones are additive.
+func diffConstraints(path string, installed, desired *apiextensionsv1.JSONSchemaProps, changes *[]SchemaChange) {
+	diffLowerBound(path, "minimum", installed.Minimum, desired.Minimum, changes)
+	diffUpperBound(path, "maximum", installed.Maximum, desired.Maximum, changes)
+	diffLowerBound(path, "minLength", installed.MinLength, desired.MinLength, changes)
+	diffUpperBound(path, "maxLength", installed.MaxLength, desired.MaxLength, changes)
+	diffLowerBound(path, "minItems", installed.MinItems, desired.MinItems, changes)
+	diffUpperBound(path, "maxItems", installed.MaxItems, desired.MaxItems, changes)
+	diffLowerBound(path, "minProperties", installed.MinProperties, desired.MinProperties, changes)
+	diffUpperBound(path, "maxProperties", installed.MaxProperties, desired.MaxProperties, changes)
+
+	diffConstraint(path, "pattern", installed.Pattern, desired.Pattern, changes)
+	diffConstraint(path, "format", installed.Format, desired.Format, changes)
+	diffConstraint(path, "multipleOf", formatNumber(installed.MultipleOf), formatNumber(desired.MultipleOf), changes)
+
+	diffFlag(path, "exclusiveMinimum", installed.ExclusiveMinimum, desired.ExclusiveMinimum, changes)
+	diffFlag(path, "exclusiveMaximum", installed.ExclusiveMaximum, desired.ExclusiveMaximum, changes)
+	diffFlag(path, "uniqueItems", installed.UniqueItems, desired.UniqueItems, changes)
+
+	if installed.Nullable != desired.Nullable {
+		change := SchemaChange{
+			Path:        displayPath(path),
+			Type:        ChangeAdditive,
+			Description: fmt.Sprintf("field '%s' is now nullable", displayPath(path)),
+		}
+		if !desired.Nullable {
+			change.Type = ChangeNarrowing
+			change.Description = fmt.Sprintf("field '%s' is not nullable anymore", displayPath(path))
+		}
+		*changes = append(*changes, change)
+	}
+
+	// Unknown fields are pruned once x-kubernetes-preserve-unknown-fields is removed, dropping their data
+	wasPreserved := installed.XPreserveUnknownFields != nil && *installed.XPreserveUnknownFields
+	isPreserved := desired.XPreserveUnknownFields != nil && *desired.XPreserveUnknownFields
+	if wasPreserved != isPreserved {
+		change := SchemaChange{
+			Path:        displayPath(path),
+			Type:        ChangeAdditive,
+			Description: fmt.Sprintf("unknown fields of field '%s' are now preserved", displayPath(path)),
+		}
+		if !isPreserved {
+			change.Type = ChangeDestructive
+			change.Description = fmt.Sprintf("unknown fields of field '%s' are not preserved anymore", displayPath(path))
+		}
+		*changes = append(*changes, change)
+	}
+}
+
+// diffLowerBound reports a lower bound (e.g., minimum) as narrowed when introduced or raised,
+// and as widened when removed or lowered.
+func diffLowerBound[T int64 | float64](path, keyword string, installed, desired *T, changes *[]SchemaChange) {
+	diffBound(path, keyword, installed, desired, func(before, after T) bool { return after > before }, changes)
+}
+
+// diffUpperBound reports an upper bound (e.g., maximum) as narrowed when introduced or lowered,
+// and as widened when removed or raised.
+func diffUpperBound[T int64 | float64](path, keyword string, installed, desired *T, changes *[]SchemaChange) {
+	diffBound(path, keyword, installed, desired, func(before, after T) bool { return after < before }, changes)
+}
+
+func diffBound[T int64 | float64](path, keyword string, installed, desired *T, tighter func(before, after T) bool, changes *[]SchemaChange) {
+	switch {
+	case installed == nil && desired == nil:
+		return
+	case installed == nil:
+		*changes = append(*changes, SchemaChange{
+			Path:        displayPath(path),
+			Type:        ChangeNarrowing,
+			Description: fmt.Sprintf("%s of field '%s' set to %v", keyword, displayPath(path), *desired),
+		})
+	case desired == nil:
+		*changes = append(*changes, SchemaChange{
+			Path:        displayPath(path),
+			Type:        ChangeAdditive,
+			Description: fmt.Sprintf("%s of field '%s' removed", keyword, displayPath(path)),
+		})
+	case *installed == *desired:
+		return
+	default:
+		change := SchemaChange{
+			Path:        displayPath(path),
+			Type:        ChangeAdditive,
+			Description: fmt.Sprintf("%s of field '%s' loosened from %v to %v", keyword, displayPath(path), *installed, *desired),
+		}
+		if tighter(*installed, *desired) {
+			change.Type = ChangeNarrowing
+			change.Description = fmt.Sprintf("%s of field '%s' tightened from %v to %v", keyword, displayPath(path), *installed, *desired)
+		}
+		*changes = append(*changes, change)
+	}
+}
+
+// diffConstraint reports a constraint whose values cannot be compared (e.g., pattern) as narrowed when introduced
+// or changed, and as widened when removed.
+func diffConstraint(path, keyword, installed, desired string, changes *[]SchemaChange) {
+	switch {
+	case installed == desired:
+		return
+	case desired == "":
+		*changes = append(*changes, SchemaChange{
+			Path:        displayPath(path),
+			Type:        ChangeAdditive,
+			Description: fmt.Sprintf("%s of field '%s' removed", keyword, displayPath(path)),
+		})
+	case installed == "":
+		*changes = append(*changes, SchemaChange{
+			Path:        displayPath(path),
+			Type:        ChangeNarrowing,
+			Description: fmt.Sprintf("%s of field '%s' set to '%s'", keyword, displayPath(path), desired),
+		})
+	default:
+		*changes = append(*changes, SchemaChange{
+			Path:        displayPath(path),
+			Type:        ChangeNarrowing,
+			Description: fmt.Sprintf("%s of field '%s' changed from '%s' to '%s'", keyword, displayPath(path), installed, desired),
+		})
+	}
+}
+
+// diffFlag reports a flag restricting the values of a field when set (e.g., uniqueItems) as narrowing when set,
+// and as additive when unset.
+func diffFlag(path, keyword string, installed, desired bool, changes *[]SchemaChange) {
+	if installed == desired {
+		return
+	}
+	change := SchemaChange{
+		Path:        displayPath(path),
+		Type:        ChangeAdditive,
+		Description: fmt.Sprintf("%s of field '%s' unset", keyword, displayPath(path)),
+	}
+	if desired {
+		change.Type = ChangeNarrowing
+		change.Description = fmt.Sprintf("%s of field '%s' set", keyword, displayPath(path))
+	}
+	*changes = append(*changes, change)
+}
+
+// diffValidationRules reports the CEL validation rules (x-kubernetes-validations) added as narrowing,
+// and the ones removed as additive. Rules are identified by their expression, so changes of their
+// messages are additive.
+func diffValidationRules(path string, installed, desired apiextensionsv1.ValidationRules, changes *[]SchemaChange) {
+	before := map[string]apiextensionsv1.ValidationRule{}
+	for _, r := range installed {
+		before[r.Rule] = r
+	}
+	after := map[string]apiextensionsv1.ValidationRule{}
+	for _, r := range desired {
+		after[r.Rule] = r
+	}
+
+	for _, r := range desired {
+		old, ok := before[r.Rule]
+		switch {
+		case !ok:
+			*changes = append(*changes, SchemaChange{
+				Path:        displayPath(path),
+				Type:        ChangeNarrowing,
+				Description: fmt.Sprintf("validation rule '%s' added to field '%s'", r.Rule, displayPath(path)),
+			})
+		case !equality.Semantic.DeepEqual(old, r):
+			*changes = append(*changes, SchemaChange{
+				Path:        displayPath(path),
+				Type:        ChangeAdditive,
+				Description: fmt.Sprintf("message of validation rule '%s' of field '%s' changed", r.Rule, displayPath(path)),
+			})
+		}
+	}
+	for _, r := range installed {
+		if _, ok := after[r.Rule]; !ok {
+			*changes = append(*changes, SchemaChange{
+				Path:        displayPath(path),
+				Type:        ChangeAdditive,
+				Description: fmt.Sprintf("validation rule '%s' removed from field '%s'", r.Rule, displayPath(path)),
+			})
+		}
+	}
+}
+
+func formatNumber(n *float64) string {
+	if n == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*n, 'g', -1, 64)
+}
+
+// diffEnum reports an enum as narrowed when values are removed from it or when it is introduced,
+// and as widened when values are added to it or when it is dropped.
+func diffEnum(path string, installed, desired []apiextensionsv1.JSON, changes *[]SchemaChange) {
+	if len(installed) == 0 && len(desired) == 0 {
+		return
+	}
+
+	before := enumValues(installed)
+	after := enumValues(desired)
+	switch {
+	case len(installed) == 0:
+		*changes = append(*changes, SchemaChange{
+			Path:        displayPath(path),
+			Type:        ChangeNarrowing,
+			Description: fmt.Sprintf("values of field '%s' restricted to [%s]", displayPath(path), strings.Join(sets.List(after), ", ")),
+		})
+	case len(desired) == 0:
+		*changes = append(*changes, SchemaChange{
+			Path:        displayPath(path),
+			Type:        ChangeAdditive,
+			Description: fmt.Sprintf("values of field '%s' not restricted anymore", displayPath(path)),
+		})
+	default:
+		if removed := before.Difference(after); removed.Len() > 0 {
+			*changes = append(*changes, SchemaChange{
+				Path:        displayPath(path),
+				Type:        ChangeNarrowing,
+				Description: fmt.Sprintf("values [%s] of field '%s' not allowed anymore", strings.Join(sets.List(removed), ", "), displayPath(path)),
+			})
+		}
+		if added := after.Difference(before); added.Len() > 0 {
+			*changes = append(*changes, SchemaChange{
+				Path:        displayPath(path),
+				Type:        ChangeAdditive,
+				Description: fmt.Sprintf("values [%s] of field '%s' allowed", strings.Join(sets.List(added), ", "), displayPath(path)),
+			})
+		}
+	}
+}
+
+func enumValues(enum []apiextensionsv1.JSON) sets.Set[string] {
+	values := sets.New[string]()
+	for _, v := range enum {
+		values.Insert(string(v.Raw))
+	}
+	return values
+}
+
+func sortedKeys(props map[string]apiextensionsv1.JSONSchemaProps) []string {
+	names := make([]string, 0, len(props))
+	for name := range props {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func displayPath(path string) string {
+	if path == "" {
+		return "."
+	}
+	return path
+}
@@ -0,0 +1,296 @@
+package crd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+func TestVersionSchema(t *testing.T) {
+	schema := &apiextensionsv1.JSONSchemaProps{Type: "object"}
+	crd := &apiextensionsv1.CustomResourceDefinition{
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+				{Name: "v1alpha1", Schema: &apiextensionsv1.CustomResourceValidation{OpenAPIV3Schema: schema}},
+			},
+		},
+	}
+
+	assert.Same(t, schema, VersionSchema(crd, "v1alpha1"))
+	assert.Nil(t, VersionSchema(crd, "v1beta1"))
+
+	updated := &apiextensionsv1.JSONSchemaProps{Type: "object", Description: "updated"}
+	require.NoError(t, SetVersionSchema(crd, "v1alpha1", updated))
+	assert.Same(t, updated, VersionSchema(crd, "v1alpha1"))
+	assert.Error(t, SetVersionSchema(crd, "v1beta1", updated))
+}
+
+func TestDiffSchemas(t *testing.T) {
+	object := func(props map[string]apiextensionsv1.JSONSchemaProps, required ...string) apiextensionsv1.JSONSchemaProps {
+		return apiextensionsv1.JSONSchemaProps{Type: "object", Properties: props, Required: required}
+	}
+	enum := func(values ...string) apiextensionsv1.JSONSchemaProps {
+		res := apiextensionsv1.JSONSchemaProps{Type: "string"}
+		for _, v := range values {
+			res.Enum = append(res.Enum, apiextensionsv1.JSON{Raw: []byte(`"` + v + `"`)})
+		}
+		return res
+	}
+	str := apiextensionsv1.JSONSchemaProps{Type: "string"}
+	integer := apiextensionsv1.JSONSchemaProps{Type: "integer"}
+
+	installed := object(map[string]apiextensionsv1.JSONSchemaProps{
+		"spec": object(map[string]apiextensionsv1.JSONSchemaProps{
+			"name":       str,
+			"owner":      str,
+			"visibility": enum("public", "private"),
+			"tags": {
+				Type:  "array",
+				Items: &apiextensionsv1.JSONSchemaPropsOrArray{Schema: &str},
+			},
+			"labels": {
+				Type:                 "object",
+				AdditionalProperties: &apiextensionsv1.JSONSchemaPropsOrBool{Allows: true, Schema: &str},
+			},
+		}, "name"),
+	})
+
+	tests := []struct {
+		name     string
+		desired  apiextensionsv1.JSONSchemaProps
+		expected []SchemaChange
+	}{
+		{
+			name:    "Unchanged",
+			desired: installed,
+		},
+		{
+			name: "Additive changes",
+			desired: object(map[string]apiextensionsv1.JSONSchemaProps{
+				"spec": object(map[string]apiextensionsv1.JSONSchemaProps{
+					"name":        {Type: "string", Description: "The name"},
+					"owner":       str,
+					"description": str,
+					"visibility":  enum("public", "private", "internal"),
+					"tags":        installed.Properties["spec"].Properties["tags"],
+					"labels":      installed.Properties["spec"].Properties["labels"],
+				}),
+			}),
+			expected: []SchemaChange{
+				{Path: "spec.visibility", Type: ChangeAdditive, Description: `values ["internal"] of field 'spec.visibility' allowed`},
+				{Path: "spec.description", Type: ChangeAdditive, Description: "field 'spec.description' added"},
+				{Path: "spec.name", Type: ChangeAdditive, Description: "field 'spec.name' is not required anymore"},
+			},
+		},
+		{
+			name: "Narrowing changes",
+			desired: object(map[string]apiextensionsv1.JSONSchemaProps{
+				"spec": object(map[string]apiextensionsv1.JSONSchemaProps{
+					"name":       integer,
+					"owner":      enum("me"),
+					"visibility": enum("public"),
+					"tags": {
+						Type:  "array",
+						Items: &apiextensionsv1.JSONSchemaPropsOrArray{Schema: &integer},
+					},
+					"labels": {
+						Type:                 "object",
+						AdditionalProperties: &apiextensionsv1.JSONSchemaPropsOrBool{Allows: true, Schema: &integer},
+					},
+					"region": str,
+				}, "name", "region"),
+			}),
+			expected: []SchemaChange{
+				{Path: "spec.labels{}", Type: ChangeNarrowing, Description: "type of field 'spec.labels{}' changed from 'string' to 'integer'"},
+				{Path: "spec.name", Type: ChangeNarrowing, Description: "type of field 'spec.name' changed from 'string' to 'integer'"},
+				{Path: "spec.owner", Type: ChangeNarrowing, Description: `values of field 'spec.owner' restricted to ["me"]`},
+				{Path: "spec.tags[]", Type: ChangeNarrowing, Description: "type of field 'spec.tags[]' changed from 'string' to 'integer'"},
+				{Path: "spec.visibility", Type: ChangeNarrowing, Description: `values ["private"] of field 'spec.visibility' not allowed anymore`},
+				{Path: "spec.region", Type: ChangeAdditive, Description: "field 'spec.region' added"},
+				{Path: "spec.region", Type: ChangeNarrowing, Description: "field 'spec.region' is now required"},
+			},
+		},
+		{
+			name: "Destructive changes",
+			desired: object(map[string]apiextensionsv1.JSONSchemaProps{
+				"spec": object(map[string]apiextensionsv1.JSONSchemaProps{
+					"name":       str,
+					"visibility": installed.Properties["spec"].Properties["visibility"],
+					"tags":       installed.Properties["spec"].Properties["tags"],
+					"labels":     installed.Properties["spec"].Properties["labels"],
+				}, "name"),
+			}),
+			expected: []SchemaChange{
+				{Path: "spec.owner", Type: ChangeDestructive, Description: "field 'spec.owner' removed"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, DiffSchemas(&installed, &tt.desired))
+		})
+	}
+}
+
+func TestDiffSchemasConstraints(t *testing.T) {
+	i64 := func(v int64) *int64 { return &v }
+	f64 := func(v float64) *float64 { return &v }
+	yes := true
+	spec := func(field apiextensionsv1.JSONSchemaProps) *apiextensionsv1.JSONSchemaProps {
+		return &apiextensionsv1.JSONSchemaProps{
+			Type:       "object",
+			Properties: map[string]apiextensionsv1.JSONSchemaProps{"name": field},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		installed apiextensionsv1.JSONSchemaProps
+		desired   apiextensionsv1.JSONSchemaProps
+		expected  []SchemaChange
+	}{
+		{
+			name:      "Description changed",
+			installed: apiextensionsv1.JSONSchemaProps{Type: "string", Description: "The name"},
+			desired:   apiextensionsv1.JSONSchemaProps{Type: "string", Description: "The name of the repo"},
+		},
+		{
+			name:      "Integer widened to number",
+			installed: apiextensionsv1.JSONSchemaProps{Type: "integer", Format: "int64", Minimum: f64(0)},
+			desired:   apiextensionsv1.JSONSchemaProps{Type: "number", Format: "double", Minimum: f64(0)},
+			expected: []SchemaChange{
+				{Path: "name", Type: ChangeAdditive, Description: "type of field 'name' widened from 'integer' to 'number'"},
+			},
+		},
+		{
+			name:      "Integer widened to number with a tighter minimum",
+			installed: apiextensionsv1.JSONSchemaProps{Type: "integer"},
+			desired:   apiextensionsv1.JSONSchemaProps{Type: "number", Minimum: f64(0)},
+			expected: []SchemaChange{
+				{Path: "name", Type: ChangeAdditive, Description: "type of field 'name' widened from 'integer' to 'number'"},
+				{Path: "name", Type: ChangeNarrowing, Description: "minimum of field 'name' set to 0"},
+			},
+		},
+		{
+			name:      "Number narrowed to integer",
+			installed: apiextensionsv1.JSONSchemaProps{Type: "number"},
+			desired:   apiextensionsv1.JSONSchemaProps{Type: "integer"},
+			expected: []SchemaChange{
+				{Path: "name", Type: ChangeNarrowing, Description: "type of field 'name' changed from 'number' to 'integer'"},
+			},
+		},
+		{
+			name:      "Pattern added",
+			installed: apiextensionsv1.JSONSchemaProps{Type: "string"},
+			desired:   apiextensionsv1.JSONSchemaProps{Type: "string", Pattern: "^[a-z]+$"},
+			expected: []SchemaChange{
+				{Path: "name", Type: ChangeNarrowing, Description: "pattern of field 'name' set to '^[a-z]+$'"},
+			},
+		},
+		{
+			name:      "Pattern removed",
+			installed: apiextensionsv1.JSONSchemaProps{Type: "string", Pattern: "^[a-z]+$"},
+			desired:   apiextensionsv1.JSONSchemaProps{Type: "string"},
+			expected: []SchemaChange{
+				{Path: "name", Type: ChangeAdditive, Description: "pattern of field 'name' removed"},
+			},
+		},
+		{
+			name:      "Lengths tightened",
+			installed: apiextensionsv1.JSONSchemaProps{Type: "string", MinLength: i64(1), MaxLength: i64(64)},
+			desired:   apiextensionsv1.JSONSchemaProps{Type: "string", MinLength: i64(3), MaxLength: i64(32), Format: "hostname"},
+			expected: []SchemaChange{
+				{Path: "name", Type: ChangeNarrowing, Description: "minLength of field 'name' tightened from 1 to 3"},
+				{Path: "name", Type: ChangeNarrowing, Description: "maxLength of field 'name' tightened from 64 to 32"},
+				{Path: "name", Type: ChangeNarrowing, Description: "format of field 'name' set to 'hostname'"},
+			},
+		},
+		{
+			name:      "Bounds loosened",
+			installed: apiextensionsv1.JSONSchemaProps{Type: "number", Minimum: f64(1), Maximum: f64(10), ExclusiveMaximum: true},
+			desired:   apiextensionsv1.JSONSchemaProps{Type: "number", Minimum: f64(0.5)},
+			expected: []SchemaChange{
+				{Path: "name", Type: ChangeAdditive, Description: "minimum of field 'name' loosened from 1 to 0.5"},
+				{Path: "name", Type: ChangeAdditive, Description: "maximum of field 'name' removed"},
+				{Path: "name", Type: ChangeAdditive, Description: "exclusiveMaximum of field 'name' unset"},
+			},
+		},
+		{
+			name:      "Bounds tightened",
+			installed: apiextensionsv1.JSONSchemaProps{Type: "array", MaxItems: i64(10)},
+			desired:   apiextensionsv1.JSONSchemaProps{Type: "array", MinItems: i64(1), MaxItems: i64(10), UniqueItems: true, MultipleOf: f64(2)},
+			expected: []SchemaChange{
+				{Path: "name", Type: ChangeNarrowing, Description: "minItems of field 'name' set to 1"},
+				{Path: "name", Type: ChangeNarrowing, Description: "multipleOf of field 'name' set to '2'"},
+				{Path: "name", Type: ChangeNarrowing, Description: "uniqueItems of field 'name' set"},
+			},
+		},
+		{
+			name:      "Nullable removed",
+			installed: apiextensionsv1.JSONSchemaProps{Type: "string", Nullable: true},
+			desired:   apiextensionsv1.JSONSchemaProps{Type: "string"},
+			expected: []SchemaChange{
+				{Path: "name", Type: ChangeNarrowing, Description: "field 'name' is not nullable anymore"},
+			},
+		},
+		{
+			name:      "Validation rule added",
+			installed: apiextensionsv1.JSONSchemaProps{Type: "string"},
+			desired: apiextensionsv1.JSONSchemaProps{Type: "string", XValidations: apiextensionsv1.ValidationRules{
+				{Rule: "self.size() > 1"},
+			}},
+			expected: []SchemaChange{
+				{Path: "name", Type: ChangeNarrowing, Description: "validation rule 'self.size() > 1' added to field 'name'"},
+			},
+		},
+		{
+			name: "Validation rule message changed",
+			installed: apiextensionsv1.JSONSchemaProps{Type: "string", XValidations: apiextensionsv1.ValidationRules{
+				{Rule: "self.size() > 1", Message: "too short"},
+			}},
+			desired: apiextensionsv1.JSONSchemaProps{Type: "string", XValidations: apiextensionsv1.ValidationRules{
+				{Rule: "self.size() > 1", Message: "name too short"},
+			}},
+			expected: []SchemaChange{
+				{Path: "name", Type: ChangeAdditive, Description: "message of validation rule 'self.size() > 1' of field 'name' changed"},
+			},
+		},
+		{
+			name:      "Unknown fields not preserved anymore",
+			installed: apiextensionsv1.JSONSchemaProps{Type: "object", XPreserveUnknownFields: &yes},
+			desired:   apiextensionsv1.JSONSchemaProps{Type: "object"},
+			expected: []SchemaChange{
+				{Path: "name", Type: ChangeDestructive, Description: "unknown fields of field 'name' are not preserved anymore"},
+			},
+		},
+		{
+			name:      "Unclassified change",
+			installed: apiextensionsv1.JSONSchemaProps{Type: "string"},
+			desired:   apiextensionsv1.JSONSchemaProps{Type: "string", XListType: func() *string { s := "set"; return &s }()},
+			expected: []SchemaChange{
+				{Path: "name", Type: ChangeNarrowing, Description: "schema of field 'name' changed in a way that cannot be classified"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes := DiffSchemas(spec(tt.installed), spec(tt.desired))
+			assert.Equal(t, tt.expected, changes)
+		})
+	}
+}
+
+func TestBreakingChanges(t *testing.T) {
+	changes := []SchemaChange{
+		{Path: "spec.a", Type: ChangeAdditive},
+		{Path: "spec.b", Type: ChangeNarrowing},
+		{Path: "spec.c", Type: ChangeDestructive},
+	}
+
+	assert.Equal(t, changes[1:], BreakingChanges(changes))
+	assert.Empty(t, BreakingChanges(changes[:1]))
+}
@@ -4,6 +4,9 @@ import (
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 )
 
+// VacuumVersion is the name of the version used to store the objects of a CRD serving multiple versions (see AppendVersion).
+const VacuumVersion = "vacuum"
+
 func ConversionConf(crd apiextensionsv1.CustomResourceDefinition, conf *apiextensionsv1.CustomResourceConversion) *apiextensionsv1.CustomResourceDefinition {
 	crd.Spec.Conversion = conf
 	return &crd
@@ -30,7 +33,7 @@ func AppendVersion(crd apiextensionsv1.CustomResourceDefinition, toadd apiextens
 			}
 		}
 		for _, el1 := range crd.Spec.Versions {
-			if el1.Name == "vacuum" {
+			if el1.Name == VacuumVersion {
 				vacuum = true
 				break
 			}
@@ -40,7 +43,7 @@ func AppendVersion(crd apiextensionsv1.CustomResourceDefinition, toadd apiextens
 			crd.Spec.Versions = append(crd.Spec.Versions, el2)
 			if !vacuum {
 				crd.Spec.Versions = append(crd.Spec.Versions, apiextensionsv1.CustomResourceDefinitionVersion{
-					Name:    "vacuum",
+					Name:    VacuumVersion,
 					Served:  false,
 					Storage: true,
 					Schema: &apiextensionsv1.CustomResourceValidation{
@@ -73,7 +76,7 @@ func AppendVersion(crd apiextensionsv1.CustomResourceDefinition, toadd apiextens
 			}
 			for i := range crd.Spec.Versions {
 				// if different from vacuum served: false and storage: true
-				if crd.Spec.Versions[i].Name != "vacuum" {
+				if crd.Spec.Versions[i].Name != VacuumVersion {
 					crd.Spec.Versions[i].Served = true
 					crd.Spec.Versions[i].Storage = false
 				}
@@ -35,6 +35,8 @@ type UndeployOptions struct {
 	SkipDeploy             bool
 	DeploymentTemplatePath string
 	ConfigmapTemplatePath  string
+	// Scope is the scope of the resources of the CRD ('Cluster' or 'Namespaced', the default), available to the RBAC templates as '.scope'
+	Scope string
 }
 
 type DeployOptions struct {
@@ -48,6 +50,19 @@ type DeployOptions struct {
 	Log                    func(msg string, keysAndValues ...any)
 	// DryRunServer is used to determine if the deployment should be applied in dry-run mode. This is ignored in lookup mode
 	DryRunServer bool
+	// Scope is the scope of the resources of the CRD ('Cluster' or 'Namespaced', the default), available to the RBAC templates as '.scope'
+	Scope string
+}
+
+// Reference returns the reference to the object, identifying it by kind, namespace and name.
+func Reference(obj client.Object) corev1.ObjectReference {
+	gvk := obj.GetObjectKind().GroupVersionKind()
+	return corev1.ObjectReference{
+		APIVersion: gvk.GroupVersion().String(),
+		Kind:       gvk.Kind,
+		Namespace:  obj.GetNamespace(),
+		Name:       obj.GetName(),
+	}
 }
 
 func logError(log func(msg string, keysAndValues ...any), msg string, err error) {
@@ -56,14 +71,17 @@ func logError(log func(msg string, keysAndValues ...any), msg string, err error)
 	}
 }
 
-func createRBACResources(gvr schema.GroupVersionResource, rbacNSName types.NamespacedName, ConfigurationGVR schema.GroupVersionResource, rbacFolderPath string) (corev1.ServiceAccount, rbacv1.ClusterRole, rbacv1.ClusterRoleBinding, rbacv1.Role, rbacv1.RoleBinding, error) {
+func createRBACResources(gvr schema.GroupVersionResource, rbacNSName types.NamespacedName, ConfigurationGVR schema.GroupVersionResource, rbacFolderPath string, scope string) (corev1.ServiceAccount, rbacv1.ClusterRole, rbacv1.ClusterRoleBinding, rbacv1.Role, rbacv1.RoleBinding, error) {
 	rbacNSName = types.NamespacedName{
 		Namespace: rbacNSName.Namespace,
 		Name:      rbacNSName.Name + ControllerResourceSuffix,
 	}
+	if scope == "" {
+		scope = "Namespaced"
+	}
 
 	sa := corev1.ServiceAccount{}
-	err := templates.CreateK8sObject(&sa, gvr, rbacNSName, path.Join(rbacFolderPath, "serviceaccount.yaml"))
+	err := templates.CreateK8sObject(&sa, gvr, rbacNSName, path.Join(rbacFolderPath, "serviceaccount.yaml"), "scope", scope)
 	if err != nil {
 		return corev1.ServiceAccount{}, rbacv1.ClusterRole{}, rbacv1.ClusterRoleBinding{}, rbacv1.Role{}, rbacv1.RoleBinding{}, err
 	}
@@ -74,28 +92,28 @@ func createRBACResources(gvr schema.GroupVersionResource, rbacNSName types.Names
 		//fmt.Printf("Configuration GVR found: %s\n", configuration)
 	}
 	clusterrole := rbacv1.ClusterRole{}
-	err = templates.CreateK8sObject(&clusterrole, gvr, rbacNSName, path.Join(rbacFolderPath, "clusterrole.yaml"), "configuration", configuration)
+	err = templates.CreateK8sObject(&clusterrole, gvr, rbacNSName, path.Join(rbacFolderPath, "clusterrole.yaml"), "configuration", configuration, "scope", scope)
 	if err != nil {
 		return corev1.ServiceAccount{}, rbacv1.ClusterRole{}, rbacv1.ClusterRoleBinding{}, rbacv1.Role{}, rbacv1.RoleBinding{}, err
 	}
 	//fmt.Printf("ClusterRole created with name: %s, namespace: %s, rules: %+v\n", clusterrole.Name, clusterrole.Namespace, clusterrole.Rules)
 
 	clusterrolebinding := rbacv1.ClusterRoleBinding{}
-	err = templates.CreateK8sObject(&clusterrolebinding, gvr, rbacNSName, path.Join(rbacFolderPath, "clusterrolebinding.yaml"), "serviceAccount", sa.Name, "saNamespace", sa.Namespace)
+	err = templates.CreateK8sObject(&clusterrolebinding, gvr, rbacNSName, path.Join(rbacFolderPath, "clusterrolebinding.yaml"), "serviceAccount", sa.Name, "saNamespace", sa.Namespace, "scope", scope)
 	if err != nil {
 		return corev1.ServiceAccount{}, rbacv1.ClusterRole{}, rbacv1.ClusterRoleBinding{}, rbacv1.Role{}, rbacv1.RoleBinding{}, err
 	}
 	//fmt.Printf("ClusterRoleBinding created with name: %s, namespace: %s, subjects: %+v, roleRef: %+v\n", clusterrolebinding.Name, clusterrolebinding.Namespace, clusterrolebinding.Subjects, clusterrolebinding.RoleRef)
 
 	role := rbacv1.Role{}
-	err = templates.CreateK8sObject(&role, gvr, rbacNSName, path.Join(rbacFolderPath, "role.yaml"))
+	err = templates.CreateK8sObject(&role, gvr, rbacNSName, path.Join(rbacFolderPath, "role.yaml"), "scope", scope)
 	if err != nil {
 		return corev1.ServiceAccount{}, rbacv1.ClusterRole{}, rbacv1.ClusterRoleBinding{}, rbacv1.Role{}, rbacv1.RoleBinding{}, err
 	}
 	//fmt.Printf("Role created with name: %s, namespace: %s, rules: %+v\n", role.Name, role.Namespace, role.Rules)
 
 	rolebinding := rbacv1.RoleBinding{}
-	err = templates.CreateK8sObject(&rolebinding, gvr, rbacNSName, path.Join(rbacFolderPath, "rolebinding.yaml"), "serviceAccount", sa.Name, "saNamespace", sa.Namespace)
+	err = templates.CreateK8sObject(&rolebinding, gvr, rbacNSName, path.Join(rbacFolderPath, "rolebinding.yaml"), "serviceAccount", sa.Name, "saNamespace", sa.Namespace, "scope", scope)
 	if err != nil {
 		return corev1.ServiceAccount{}, rbacv1.ClusterRole{}, rbacv1.ClusterRoleBinding{}, rbacv1.Role{}, rbacv1.RoleBinding{}, err
 	}
@@ -267,18 +285,21 @@ func lookupRBACResources(ctx context.Context, kubeClient client.Client, clusterr
 	return nil
 }
 
-func Deploy(ctx context.Context, kube client.Client, opts DeployOptions) (digest string, err error) {
+// Deploy applies the RBAC resources, ConfigMap and Deployment of the controller of the GVR, returning the digest
+// of the applied objects and the references to them (in the order they are applied).
+func Deploy(ctx context.Context, kube client.Client, opts DeployOptions) (digest string, applied []corev1.ObjectReference, err error) {
 	if opts.Log == nil {
-		return "", fmt.Errorf("log function is required")
+		return "", nil, fmt.Errorf("log function is required")
 	}
 
 	hsh := hasher.NewFNVObjectHash()
 
-	sa, clusterrole, clusterrolebinding, role, rolebinding, err := createRBACResources(opts.GVR, opts.NamespacedName, opts.ConfigurationGVR, opts.RBACFolderPath)
+	sa, clusterrole, clusterrolebinding, role, rolebinding, err := createRBACResources(opts.GVR, opts.NamespacedName, opts.ConfigurationGVR, opts.RBACFolderPath, opts.Scope)
 	if err != nil {
 		opts.Log("Error creating RBAC resources", "error", err)
-		return "", err
+		return "", nil, err
 	}
+	applied = append(applied, Reference(&clusterrole), Reference(&clusterrolebinding), Reference(&role), Reference(&rolebinding), Reference(&sa))
 	applyOpts := kubecli.ApplyOptions{}
 
 	if opts.DryRunServer {
@@ -287,7 +308,7 @@ func Deploy(ctx context.Context, kube client.Client, opts DeployOptions) (digest
 	err = installRBACResources(ctx, opts.KubeClient, clusterrole, clusterrolebinding, role, rolebinding, sa, opts.Log, &hsh, applyOpts)
 	if err != nil {
 		opts.Log("Error installing RBAC resources", "error", err)
-		return "", err
+		return "", nil, err
 	}
 
 	cmNSName := types.NamespacedName{
@@ -300,17 +321,18 @@ func Deploy(ctx context.Context, kube client.Client, opts DeployOptions) (digest
 		"composition_controller_sa_namespace", sa.Namespace)
 	if err != nil {
 		opts.Log("Error creating configmap object", "error", err)
-		return "", err
+		return "", nil, err
 	}
 
+	applied = append(applied, Reference(&cm))
 	err = kubecli.Apply(ctx, opts.KubeClient, &cm, applyOpts)
 	if err != nil {
 		opts.Log("Error installing configmap", "name", cm.Name, "namespace", cm.Namespace, "error", err)
-		return "", fmt.Errorf("error installing configmap: %v", err)
+		return "", nil, fmt.Errorf("error installing configmap: %v", err)
 	}
 	err = hsh.SumHash(cm.ObjectMeta.Name, cm.ObjectMeta.Namespace, cm.Data)
 	if err != nil {
-		return "", fmt.Errorf("error hashing configmap: %v", err)
+		return "", nil, fmt.Errorf("error hashing configmap: %v", err)
 	}
 	opts.Log("Configmap successfully installed", "gvr", opts.GVR.String(), "name", cm.Name, "namespace", cm.Namespace, "digest", hsh.GetHash())
 
@@ -327,13 +349,14 @@ func Deploy(ctx context.Context, kube client.Client, opts DeployOptions) (digest
 		"serviceAccountName", sa.Name)
 	if err != nil {
 		opts.Log("Error creating deployment object", "error", err)
-		return "", err
+		return "", nil, err
 	}
 
+	applied = append(applied, Reference(&dep))
 	err = kubecli.Apply(ctx, opts.KubeClient, &dep, applyOpts)
 	if err != nil {
 		opts.Log("Error installing deployment", "name", dep.Name, "namespace", dep.Namespace, "error", err)
-		return "", fmt.Errorf("error installing deployment: %v", err)
+		return "", nil, fmt.Errorf("error installing deployment: %v", err)
 	}
 
 	if !opts.DryRunServer {
@@ -346,20 +369,20 @@ func Deploy(ctx context.Context, kube client.Client, opts DeployOptions) (digest
 			"serviceAccountName", sa.Name)
 		if err != nil {
 			opts.Log("Error creating deployment object", "error", err)
-			return "", err
+			return "", nil, err
 		}
 		// Deployment needs to be restarted if the hash changes to get the new configmap
 		err = kubecli.Get(ctx, opts.KubeClient, &dep)
 		if err != nil {
 			logError(opts.Log, "Error getting deployment", err)
-			return "", err
+			return "", nil, err
 		}
 		// restart only if deployment is presently running
 		if dep.Status.ReadyReplicas == dep.Status.Replicas {
 			err = deployment.RestartDeployment(ctx, opts.KubeClient, &dep)
 			if err != nil {
 				logError(opts.Log, "Error restarting deployment", err)
-				return "", err
+				return "", nil, err
 			}
 		}
 	}
@@ -368,11 +391,11 @@ func Deploy(ctx context.Context, kube client.Client, opts DeployOptions) (digest
 
 	err = hsh.SumHash(dep.ObjectMeta.Name, dep.ObjectMeta.Namespace, dep.Spec)
 	if err != nil {
-		return "", fmt.Errorf("error hashing deployment spec: %v", err)
+		return "", nil, fmt.Errorf("error hashing deployment spec: %v", err)
 	}
 	opts.Log("Deployment successfully installed", "gvr", opts.GVR.String(), "name", dep.Name, "namespace", dep.Namespace, "digest", hsh.GetHash())
 
-	return hsh.GetHash(), nil
+	return hsh.GetHash(), applied, nil
 }
 
 func Undeploy(ctx context.Context, kube client.Client, opts UndeployOptions) error {
@@ -396,7 +419,7 @@ func Undeploy(ctx context.Context, kube client.Client, opts UndeployOptions) err
 		return nil
 	}
 
-	sa, clusterrole, clusterrolebinding, role, rolebinding, err := createRBACResources(opts.GVR, opts.NamespacedName, opts.ConfigurationGVR, opts.RBACFolderPath)
+	sa, clusterrole, clusterrolebinding, role, rolebinding, err := createRBACResources(opts.GVR, opts.NamespacedName, opts.ConfigurationGVR, opts.RBACFolderPath, opts.Scope)
 	if err != nil {
 		opts.Log("Error creating RBAC resources", "error", err)
 		return err
@@ -451,6 +474,21 @@ func Undeploy(ctx context.Context, kube client.Client, opts UndeployOptions) err
 	return err
 }
 
+// UndeployReferences removes the referenced objects in the reverse order they were applied (see Deploy).
+// Objects already removed are skipped.
+func UndeployReferences(ctx context.Context, kube client.Client, refs []corev1.ObjectReference, log func(msg string, keysAndValues ...any)) error {
+	for i := len(refs) - 1; i >= 0; i-- {
+		ref := refs[i]
+		err := kubecli.UninstallFromReference(ctx, kube, ref, kubecli.UninstallOptions{})
+		if err != nil {
+			logError(log, fmt.Sprintf("Error uninstalling %s", ref.Kind), err)
+			return fmt.Errorf("error uninstalling %s %s: %w", ref.Kind, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}.String(), err)
+		}
+		log(fmt.Sprintf("%s successfully uninstalled", ref.Kind), "name", ref.Name, "namespace", ref.Namespace)
+	}
+	return nil
+}
+
 // This function is used to lookup the current state of the deployment and return the hash of the current state
 // This is used to determine if the deployment needs to be updated or not
 func Lookup(ctx context.Context, kube client.Client, opts DeployOptions) (digest string, err error) {
@@ -458,7 +496,7 @@ func Lookup(ctx context.Context, kube client.Client, opts DeployOptions) (digest
 		return "", fmt.Errorf("log function is required")
 	}
 
-	sa, clusterrole, clusterrolebinding, role, rolebinding, err := createRBACResources(opts.GVR, opts.NamespacedName, opts.ConfigurationGVR, opts.RBACFolderPath)
+	sa, clusterrole, clusterrolebinding, role, rolebinding, err := createRBACResources(opts.GVR, opts.NamespacedName, opts.ConfigurationGVR, opts.RBACFolderPath, opts.Scope)
 	if err != nil {
 		return "", err
 	}
@@ -96,8 +96,10 @@ func TestDeploy(t *testing.T) {
 			Log: func(msg string, keysAndValues ...any) {},
 		}
 
-		_, err = Deploy(context.Background(), cli, opts)
+		_, applied, err := Deploy(context.Background(), cli, opts)
 		assert.NoError(t, err)
+		// ClusterRole, ClusterRoleBinding, Role, RoleBinding, ServiceAccount, ConfigMap and Deployment
+		assert.Len(t, applied, 7)
 
 		return ctx
 	}).Assess("Undeploy", func(ctx context.Context, t *testing.T, cfg *envconf.Config) context.Context {
@@ -166,7 +168,7 @@ func TestLookup(t *testing.T) {
 		}
 
 		// Deploy first to ensure resources exist for lookup
-		ddig, err := Deploy(context.Background(), cli, opts)
+		ddig, _, err := Deploy(context.Background(), cli, opts)
 		assert.NoError(t, err)
 		assert.NotEmpty(t, ddig)
 
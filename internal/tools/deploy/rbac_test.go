@@ -0,0 +1,34 @@
+package deploy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestCreateRBACResourcesScope(t *testing.T) {
+	gvr := schema.GroupVersionResource{Group: "github.ogen.krateo.io", Version: "v1alpha1", Resource: "orgs"}
+	nn := types.NamespacedName{Namespace: "demo", Name: "orgs-v1alpha1"}
+
+	tests := []struct {
+		name     string
+		scope    string
+		expected string
+	}{
+		{name: "Default scope", expected: "Namespaced"},
+		{name: "Namespaced", scope: "Namespaced", expected: "Namespaced"},
+		{name: "Cluster", scope: "Cluster", expected: "Cluster"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, clusterrole, clusterrolebinding, _, _, err := createRBACResources(gvr, nn, schema.GroupVersionResource{}, "testdata", tt.scope)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, clusterrole.Labels["ogen.krateo.io/resource-scope"])
+			assert.Equal(t, tt.expected, clusterrolebinding.Labels["ogen.krateo.io/resource-scope"])
+		})
+	}
+}
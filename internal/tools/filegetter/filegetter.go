@@ -1,11 +1,16 @@
 package filegetter
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
+	"path/filepath"
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
@@ -19,6 +24,7 @@ const (
 	NoAuth AuthType = iota
 	BasicAuth
 	BearerToken
+	HeaderAuth
 )
 
 // AuthConfig holds authentication information
@@ -27,28 +33,90 @@ type AuthConfig struct {
 	Username string
 	Password string
 	Token    string
+	// HeaderName and HeaderValue are the custom header sent with HeaderAuth
+	HeaderName  string
+	HeaderValue string
 }
 
 type Filegetter struct {
 	Client     *http.Client
 	KubeClient client.Client
+	// Cache keeps the files downloaded over HTTP, to download them again only if changed (optional).
+	Cache *HTTPCache
 }
 
 // GetFile gets a file from a source and writes it to a destination.
 func (cli *Filegetter) GetFile(ctx context.Context, dst string, src string, auth *AuthConfig) error {
-	var reader io.Reader
-	var err error
+	reader, err := cli.open(ctx, src, auth)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	// Create the destination file
+	dstFile, err := os.Create(dst)
+	if err != nil {
+		return fmt.Errorf("error creating destination file: %v", err)
+	}
+	defer dstFile.Close()
+
+	// Copy the contents
+	_, err = io.Copy(dstFile, reader)
+	if err != nil {
+		return fmt.Errorf("error writing to destination file: %v", err)
+	}
 
+	return nil
+}
+
+// Get gets a file from a source and returns its contents, without writing anything to disk.
+func (cli *Filegetter) Get(ctx context.Context, src string, auth *AuthConfig) ([]byte, error) {
+	reader, err := cli.open(ctx, src, auth)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	contents, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("error reading file: %v", err)
+	}
+	return contents, nil
+}
+
+// withoutHeaderOnHostChange returns a copy of the client removing the given header from the requests redirected
+// to another host. Unlike Authorization, custom headers are sent by net/http to any redirect target,
+// which would leak the credentials they hold.
+func withoutHeaderOnHostChange(client *http.Client, header string) *http.Client {
+	res := *client
+	checkRedirect := client.CheckRedirect
+	res.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if req.URL.Host != via[0].URL.Host {
+			req.Header.Del(header)
+		}
+		if checkRedirect != nil {
+			return checkRedirect(req, via)
+		}
+		if len(via) >= 10 {
+			return errors.New("stopped after 10 redirects")
+		}
+		return nil
+	}
+	return &res
+}
+
+// open returns a reader of the file at the source: a URL, a ConfigMap or Secret key, or a local file.
+func (cli *Filegetter) open(ctx context.Context, src string, auth *AuthConfig) (io.ReadCloser, error) {
 	if cli.Client == nil || cli.KubeClient == nil {
-		return fmt.Errorf("http client or kube client not set")
+		return nil, fmt.Errorf("http client or kube client not set")
 	}
 
 	// Check if the source is a URL or a local file
 	if strings.HasPrefix(src, "http://") || strings.HasPrefix(src, "https://") {
 		// Create a new request
-		req, err := http.NewRequest("GET", src, nil)
+		req, err := http.NewRequestWithContext(ctx, "GET", src, nil)
 		if err != nil {
-			return fmt.Errorf("error creating request: %v", err)
+			return nil, fmt.Errorf("error creating request: %v", err)
 		}
 
 		// Add authentication if provided
@@ -58,30 +126,51 @@ func (cli *Filegetter) GetFile(ctx context.Context, dst string, src string, auth
 				req.SetBasicAuth(auth.Username, auth.Password)
 			case BearerToken:
 				req.Header.Add("Authorization", "Bearer "+auth.Token)
+			case HeaderAuth:
+				req.Header.Add(auth.HeaderName, auth.HeaderValue)
 			}
 		}
 
+		httpClient := cli.Client
+		if auth != nil && auth.Type == HeaderAuth {
+			httpClient = withoutHeaderOnHostChange(httpClient, auth.HeaderName)
+		}
+
+		// Revalidate the cached file, if any
+		cached, revalidating := cli.Cache.prepare(req)
+
 		// Send the request
-		resp, err := cli.Client.Do(req)
+		resp, err := httpClient.Do(req)
 		if err != nil {
-			return fmt.Errorf("error downloading file: %v", err)
+			return nil, fmt.Errorf("error downloading file: %v", err)
+		}
+
+		if resp.StatusCode == http.StatusNotModified && revalidating {
+			resp.Body.Close()
+			return io.NopCloser(bytes.NewReader(cli.Cache.notModified(cached))), nil
 		}
-		defer resp.Body.Close()
 
 		if resp.StatusCode != http.StatusOK {
-			return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+			resp.Body.Close()
+			return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		}
+
+		if cli.Cache == nil {
+			return resp.Body, nil
 		}
 
-		reader = resp.Body
+		defer resp.Body.Close()
+		contents, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("error downloading file: %v", err)
+		}
+		cli.Cache.store(req, resp, contents)
+		return io.NopCloser(bytes.NewReader(contents)), nil
 	} else if strings.HasPrefix(src, "configmap://") {
-		configmapString := strings.TrimPrefix(src, "configmap://")
-		configmapParts := strings.Split(configmapString, "/")
-		if len(configmapParts) != 3 {
-			return fmt.Errorf("invalid configmap source: %s - must be formatted as configmap://<namespace>/<name>/<key>", src)
+		namespace, name, key, err := ParseConfigMapSource(src)
+		if err != nil {
+			return nil, err
 		}
-		namespace := configmapParts[0]
-		name := configmapParts[1]
-		key := configmapParts[2]
 
 		// Get the configmap name and key
 		cm := corev1.ConfigMap{}
@@ -90,37 +179,122 @@ func (cli *Filegetter) GetFile(ctx context.Context, dst string, src string, auth
 			Name:      name,
 		}, &cm)
 		if err != nil {
-			return fmt.Errorf("error getting configmap: %v", err)
+			return nil, fmt.Errorf("error getting configmap: %v", err)
 		}
 
-		data, ok := cm.Data[key]
+		// Text keys are in data, while binary keys (e.g., gzip compressed) are in binaryData
+		if data, ok := cm.Data[key]; ok {
+			return decompress([]byte(data))
+		}
+		data, ok := cm.BinaryData[key]
 		if !ok {
-			return fmt.Errorf("key not found in configmap: %s", key)
+			return nil, fmt.Errorf("key not found in configmap: %s", key)
+		}
+		return decompress(data)
+	} else if strings.HasPrefix(src, "secret://") {
+		namespace, name, key, err := ParseSecretSource(src)
+		if err != nil {
+			return nil, err
 		}
 
-		reader = strings.NewReader(data)
-	} else {
-		// Open local file
-		file, err := os.Open(src)
+		secret := corev1.Secret{}
+		err = cli.KubeClient.Get(ctx, client.ObjectKey{
+			Namespace: namespace,
+			Name:      name,
+		}, &secret)
 		if err != nil {
-			return fmt.Errorf("error opening local file: %v - %s", err, src)
+			return nil, fmt.Errorf("error getting secret: %v", err)
 		}
-		defer file.Close()
-		reader = file
+
+		data, ok := secret.Data[key]
+		if !ok {
+			return nil, fmt.Errorf("key not found in secret: %s", key)
+		}
+		return decompress(data)
 	}
 
-	// Create the destination file
-	dstFile, err := os.Create(dst)
+	// Open local file
+	file, err := os.Open(src)
 	if err != nil {
-		return fmt.Errorf("error creating destination file: %v", err)
+		return nil, fmt.Errorf("error opening local file: %v - %s", err, src)
 	}
-	defer dstFile.Close()
+	return file, nil
+}
 
-	// Copy the contents
-	_, err = io.Copy(dstFile, reader)
+// ParseConfigMapSource returns the namespace, name and key of a ConfigMap source, formatted as
+// configmap://<namespace>/<name>/<key>.
+func ParseConfigMapSource(src string) (namespace, name, key string, err error) {
+	return parseObjectSource(src, "configmap")
+}
+
+// ParseSecretSource returns the namespace, name and key of a Secret source, formatted as
+// secret://<namespace>/<name>/<key>.
+func ParseSecretSource(src string) (namespace, name, key string, err error) {
+	return parseObjectSource(src, "secret")
+}
+
+func parseObjectSource(src string, scheme string) (namespace, name, key string, err error) {
+	prefix := scheme + "://"
+	parts := strings.Split(strings.TrimPrefix(src, prefix), "/")
+	if !strings.HasPrefix(src, prefix) || len(parts) != 3 {
+		return "", "", "", fmt.Errorf("invalid %s source: %s - must be formatted as %s<namespace>/<name>/<key>", scheme, src, prefix)
+	}
+	return parts[0], parts[1], parts[2], nil
+}
+
+// gzipMagic are the first bytes of gzip compressed data.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// maxDecompressedSize bounds the size of decompressed files, so that a small compressed key cannot exhaust the memory.
+const maxDecompressedSize = 256 << 20
+
+// decompress returns a reader of the given ConfigMap or Secret key, decompressing it if gzip compressed.
+// Keys are limited to 1 MiB, so larger files (e.g., large OAS documents) can be stored compressed.
+func decompress(data []byte) (io.ReadCloser, error) {
+	if !bytes.HasPrefix(data, gzipMagic) {
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(data))
 	if err != nil {
-		return fmt.Errorf("error writing to destination file: %v", err)
+		return nil, fmt.Errorf("error decompressing file: %v", err)
 	}
+	defer zr.Close()
 
-	return nil
+	contents, err := io.ReadAll(io.LimitReader(zr, maxDecompressedSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing file: %v", err)
+	}
+	if len(contents) > maxDecompressedSize {
+		return nil, fmt.Errorf("error decompressing file: larger than %d bytes", maxDecompressedSize)
+	}
+	return io.NopCloser(bytes.NewReader(contents)), nil
+}
+
+// ResolveSibling returns the source of a file of the same bundle as src (e.g., a file referenced with
+// a relative $ref by an OAS document), given its path relative to the directory of src.
+// For URLs, the query string of src (e.g., a branch or an access token) is kept, unless rel has its own.
+// For ConfigMaps and Secrets, the files of a bundle are other keys of the same object, so subdirectories are not allowed.
+func ResolveSibling(src string, rel string) (string, error) {
+	if strings.HasPrefix(src, "http://") || strings.HasPrefix(src, "https://") {
+		base, err := url.Parse(src)
+		if err != nil {
+			return "", fmt.Errorf("error parsing url: %v", err)
+		}
+		ref, err := url.Parse(rel)
+		if err != nil {
+			return "", fmt.Errorf("error parsing relative path: %v", err)
+		}
+		sibling := base.ResolveReference(ref)
+		if sibling.RawQuery == "" && !ref.IsAbs() && ref.Host == "" {
+			sibling.RawQuery = base.RawQuery
+		}
+		return sibling.String(), nil
+	} else if strings.HasPrefix(src, "configmap://") || strings.HasPrefix(src, "secret://") {
+		if strings.Contains(rel, "/") {
+			return "", fmt.Errorf("invalid key: %s - files in a configmap or secret cannot be in subdirectories", rel)
+		}
+		return src[:strings.LastIndex(src, "/")+1] + rel, nil
+	}
+	return filepath.Join(filepath.Dir(src), filepath.FromSlash(rel)), nil
 }
@@ -1,6 +1,8 @@
 package filegetter
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
 	"net/http"
 	"net/http/httptest"
@@ -120,6 +122,30 @@ func TestGetFile(t *testing.T) {
 				return err == nil && string(content) == "token authenticated content"
 			},
 		},
+		{
+			name: "Download with custom header",
+			auth: &AuthConfig{
+				Type:        HeaderAuth,
+				HeaderName:  "PRIVATE-TOKEN",
+				HeaderValue: "secret-token",
+			},
+			expectError: false,
+			setup: func() string {
+				content := "header authenticated content"
+				server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if r.Header.Get("PRIVATE-TOKEN") != "secret-token" {
+						w.WriteHeader(http.StatusUnauthorized)
+						return
+					}
+					w.Write([]byte(content))
+				}))
+				return server.URL
+			},
+			validate: func(dst string) bool {
+				content, err := os.ReadFile(dst)
+				return err == nil && string(content) == "header authenticated content"
+			},
+		},
 		{
 			name:        "Non-existent local file",
 			src:         filepath.Join(tempDir, "non_existent.txt"),
@@ -190,3 +216,276 @@ func TestGetFile(t *testing.T) {
 		})
 	}
 }
+
+func gzipped(t *testing.T, contents string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write([]byte(contents)); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestGet(t *testing.T) {
+	scheme := runtime.NewScheme()
+	corev1.AddToScheme(scheme)
+	kubeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-configmap",
+			Namespace: "default",
+		},
+		Data: map[string]string{
+			"test-key": "configmap content",
+		},
+		BinaryData: map[string][]byte{
+			"binary-key":  []byte("binary content"),
+			"gzipped-key": gzipped(t, "gzipped content"),
+		},
+	}, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-secret",
+			Namespace: "default",
+		},
+		Data: map[string][]byte{
+			"test-key":    []byte("secret content"),
+			"gzipped-key": gzipped(t, "gzipped secret content"),
+			"corrupt-key": append([]byte{0x1f, 0x8b}, "not gzip"...),
+		},
+	}).Build()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/openapi.yaml" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte("downloaded content"))
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		name        string
+		src         string
+		expected    string
+		expectError bool
+	}{
+		{
+			name:     "URL",
+			src:      server.URL + "/openapi.yaml",
+			expected: "downloaded content",
+		},
+		{
+			name:        "URL not found",
+			src:         server.URL + "/missing.yaml",
+			expectError: true,
+		},
+		{
+			name:     "ConfigMap key",
+			src:      "configmap://default/test-configmap/test-key",
+			expected: "configmap content",
+		},
+		{
+			name:        "ConfigMap key not found",
+			src:         "configmap://default/test-configmap/missing-key",
+			expectError: true,
+		},
+		{
+			name:     "ConfigMap binary key",
+			src:      "configmap://default/test-configmap/binary-key",
+			expected: "binary content",
+		},
+		{
+			name:     "ConfigMap gzip compressed key",
+			src:      "configmap://default/test-configmap/gzipped-key",
+			expected: "gzipped content",
+		},
+		{
+			name:     "Secret key",
+			src:      "secret://default/test-secret/test-key",
+			expected: "secret content",
+		},
+		{
+			name:     "Secret gzip compressed key",
+			src:      "secret://default/test-secret/gzipped-key",
+			expected: "gzipped secret content",
+		},
+		{
+			name:        "Secret corrupt gzip compressed key",
+			src:         "secret://default/test-secret/corrupt-key",
+			expectError: true,
+		},
+		{
+			name:        "Secret key not found",
+			src:         "secret://default/test-secret/missing-key",
+			expectError: true,
+		},
+		{
+			name:        "Secret not found",
+			src:         "secret://default/missing-secret/test-key",
+			expectError: true,
+		},
+		{
+			name:        "Invalid Secret source",
+			src:         "secret://default/test-secret",
+			expectError: true,
+		},
+	}
+
+	filegetter := &Filegetter{
+		Client:     &http.Client{},
+		KubeClient: kubeClient,
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := filegetter.Get(context.Background(), tc.src, nil)
+			if tc.expectError {
+				if err == nil {
+					t.Errorf("Expected error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if string(got) != tc.expected {
+				t.Errorf("Expected %s, got %s", tc.expected, string(got))
+			}
+		})
+	}
+}
+
+func TestResolveSibling(t *testing.T) {
+	testCases := []struct {
+		name        string
+		src         string
+		rel         string
+		expected    string
+		expectError bool
+	}{
+		{
+			name:     "URL",
+			src:      "https://example.com/specs/openapi.yaml",
+			rel:      "schemas/repo.yaml",
+			expected: "https://example.com/specs/schemas/repo.yaml",
+		},
+		{
+			name:     "URL with query",
+			src:      "https://example.com/specs/openapi.yaml?ref=main",
+			rel:      "repo.yaml",
+			expected: "https://example.com/specs/repo.yaml?ref=main",
+		},
+		{
+			name:     "URL with query and relative path with its own query",
+			src:      "https://example.com/specs/openapi.yaml?ref=main",
+			rel:      "schemas/repo.yaml?ref=v1",
+			expected: "https://example.com/specs/schemas/repo.yaml?ref=v1",
+		},
+		{
+			name:     "ConfigMap key",
+			src:      "configmap://default/specs/openapi.yaml",
+			rel:      "repo.yaml",
+			expected: "configmap://default/specs/repo.yaml",
+		},
+		{
+			name:        "ConfigMap key in a subdirectory",
+			src:         "configmap://default/specs/openapi.yaml",
+			rel:         "schemas/repo.yaml",
+			expectError: true,
+		},
+		{
+			name:     "Secret key",
+			src:      "secret://default/specs/openapi.yaml",
+			rel:      "repo.yaml",
+			expected: "secret://default/specs/repo.yaml",
+		},
+		{
+			name:        "Secret key in a subdirectory",
+			src:         "secret://default/specs/openapi.yaml",
+			rel:         "schemas/repo.yaml",
+			expectError: true,
+		},
+		{
+			name:     "Local file",
+			src:      filepath.Join("specs", "openapi.yaml"),
+			rel:      "schemas/repo.yaml",
+			expected: filepath.Join("specs", "schemas", "repo.yaml"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ResolveSibling(tc.src, tc.rel)
+			if tc.expectError {
+				if err == nil {
+					t.Errorf("Expected error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got != tc.expected {
+				t.Errorf("Expected %s, got %s", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestGetWithHeaderAuthRedirect(t *testing.T) {
+	var received []string
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.Header.Get("X-Api-Key"))
+		w.Write([]byte("redirected content"))
+	}))
+	defer target.Close()
+
+	origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/same-host":
+			http.Redirect(w, r, "/openapi.yaml", http.StatusFound)
+		case "/other-host":
+			http.Redirect(w, r, target.URL+"/openapi.yaml", http.StatusFound)
+		default:
+			received = append(received, r.Header.Get("X-Api-Key"))
+			w.Write([]byte("redirected content"))
+		}
+	}))
+	defer origin.Close()
+
+	scheme := runtime.NewScheme()
+	corev1.AddToScheme(scheme)
+	filegetter := &Filegetter{
+		Client:     &http.Client{},
+		KubeClient: fake.NewClientBuilder().WithScheme(scheme).Build(),
+	}
+	auth := &AuthConfig{Type: HeaderAuth, HeaderName: "X-Api-Key", HeaderValue: "secret-key"}
+
+	testCases := []struct {
+		name     string
+		path     string
+		expected string
+	}{
+		{name: "Redirect to the same host keeps the header", path: "/same-host", expected: "secret-key"},
+		{name: "Redirect to another host drops the header", path: "/other-host", expected: ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			received = nil
+			got, err := filegetter.Get(context.Background(), origin.URL+tc.path, auth)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if string(got) != "redirected content" {
+				t.Errorf("Expected redirected content, got %s", string(got))
+			}
+			if len(received) != 1 || received[0] != tc.expected {
+				t.Errorf("Expected the redirect target to receive header %q, got %q", tc.expected, received)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package filegetter
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/cache"
+)
+
+// cachedFile is a file downloaded over HTTP, with the validators returned by the server.
+type cachedFile struct {
+	etag         string
+	lastModified string
+	contents     []byte
+}
+
+// HTTPCache keeps the files downloaded over HTTP along with their validators (ETag and Last-Modified headers),
+// so they are downloaded again only when the server reports they changed. The cache is bounded by the total size
+// of the cached files, in bytes. Files with no validators are never cached.
+type HTTPCache struct {
+	files  *cache.LRU[string, cachedFile]
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+// NewHTTPCache returns a cache holding downloaded files up to maxSize bytes.
+func NewHTTPCache(maxSize int64) *HTTPCache {
+	return &HTTPCache{files: cache.NewLRU[string, cachedFile](maxSize)}
+}
+
+// prepare adds to the request the validators of the cached file, if any, and returns the file.
+// The file is kept by the caller, since it may be evicted from the cache before the server reports it unchanged.
+func (c *HTTPCache) prepare(req *http.Request) (cachedFile, bool) {
+	if c == nil {
+		return cachedFile{}, false
+	}
+	file, ok := c.files.Get(req.URL.String())
+	if !ok {
+		return cachedFile{}, false
+	}
+	if file.etag != "" {
+		req.Header.Set("If-None-Match", file.etag)
+	}
+	if file.lastModified != "" {
+		req.Header.Set("If-Modified-Since", file.lastModified)
+	}
+	return file, true
+}
+
+// notModified returns the contents of the file returned by prepare, that the server reported unchanged.
+func (c *HTTPCache) notModified(file cachedFile) []byte {
+	c.hits.Add(1)
+	return file.contents
+}
+
+// store caches the file downloaded with the response, if the server returned any validators.
+func (c *HTTPCache) store(req *http.Request, resp *http.Response, contents []byte) {
+	if c == nil {
+		return
+	}
+	c.misses.Add(1)
+
+	file := cachedFile{
+		etag:         resp.Header.Get("ETag"),
+		lastModified: resp.Header.Get("Last-Modified"),
+		contents:     contents,
+	}
+	if file.etag == "" && file.lastModified == "" {
+		return
+	}
+	c.files.Add(req.URL.String(), file, int64(len(contents)))
+}
+
+// Stats returns the usage of the cache: the files found unchanged (hits), the files downloaded (misses),
+// and the number and total size in bytes of the cached files.
+func (c *HTTPCache) Stats() cache.Stats {
+	if c == nil {
+		return cache.Stats{}
+	}
+	return cache.Stats{
+		Hits:    c.hits.Load(),
+		Misses:  c.misses.Load(),
+		Entries: c.files.Len(),
+		Size:    c.files.Size(),
+	}
+}
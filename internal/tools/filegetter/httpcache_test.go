@@ -0,0 +1,91 @@
+package filegetter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestGetWithHTTPCache(t *testing.T) {
+	content := "openapi: 3.0.0"
+	downloads := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		downloads++
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(content))
+	}))
+	defer server.Close()
+
+	scheme := runtime.NewScheme()
+	corev1.AddToScheme(scheme)
+	filegetter := &Filegetter{
+		Client:     &http.Client{},
+		KubeClient: fake.NewClientBuilder().WithScheme(scheme).Build(),
+		Cache:      NewHTTPCache(1024),
+	}
+
+	for i := 0; i < 3; i++ {
+		got, err := filegetter.Get(context.Background(), server.URL+"/openapi.yaml", nil)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if string(got) != content {
+			t.Errorf("Expected %s, got %s", content, string(got))
+		}
+	}
+
+	if downloads != 1 {
+		t.Errorf("Expected 1 download, got %d", downloads)
+	}
+	stats := filegetter.Cache.Stats()
+	if stats.Hits != 2 || stats.Misses != 1 || stats.Entries != 1 {
+		t.Errorf("Unexpected cache stats: %+v", stats)
+	}
+}
+
+func TestGetWithHTTPCache_EvictedBeforeNotModified(t *testing.T) {
+	content := "openapi: 3.0.0"
+	var filegetter *Filegetter
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			// Another download evicts the file while the server is revalidating it
+			filegetter.Cache.files.Add("other", cachedFile{etag: `"other"`}, 1024)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(content))
+	}))
+	defer server.Close()
+
+	scheme := runtime.NewScheme()
+	corev1.AddToScheme(scheme)
+	filegetter = &Filegetter{
+		Client:     &http.Client{},
+		KubeClient: fake.NewClientBuilder().WithScheme(scheme).Build(),
+		Cache:      NewHTTPCache(1024),
+	}
+
+	for i := 0; i < 2; i++ {
+		got, err := filegetter.Get(context.Background(), server.URL+"/openapi.yaml", nil)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if string(got) != content {
+			t.Errorf("Expected %s, got %s", content, string(got))
+		}
+	}
+
+	if _, ok := filegetter.Cache.files.Get(server.URL + "/openapi.yaml"); ok {
+		t.Errorf("Expected the file to be evicted")
+	}
+}
@@ -0,0 +1,159 @@
+package oas2jsonschema
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"testing/fstest"
+
+	"github.com/pb33f/libopenapi/index"
+	"gopkg.in/yaml.v3"
+)
+
+// bundleRoot is the virtual directory where the files of a bundle are served to libopenapi.
+const bundleRoot = "/oas-bundle"
+
+// Bundle is an OAS document split across multiple files, referenced with relative $refs
+// (e.g., `$ref: ./schemas/repo.yaml#/Repo`).
+type Bundle struct {
+	// Entry is the path of the root document, relative to the root of the bundle.
+	Entry string
+	// Files holds the contents of the files of the bundle (including the root document),
+	// by path relative to the root of the bundle.
+	Files map[string][]byte
+}
+
+// Digest returns the SHA-256 digest of the contents of the bundle, hex encoded.
+// For a single-file bundle, it is the SHA-256 digest of the root document.
+func (b *Bundle) Digest() string {
+	h := sha256.New()
+	h.Write(b.Files[b.Entry])
+
+	names := make([]string, 0, len(b.Files))
+	for name := range b.Files {
+		if name != b.Entry {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		h.Write([]byte("\x00" + name + "\x00"))
+		h.Write(b.Files[name])
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// FileLoader loads a file of a bundle given its path relative to the root of the bundle.
+type FileLoader func(path string) ([]byte, error)
+
+// LoadBundle builds a bundle starting from the entry document, loading with the given loader
+// all the files referenced (directly or indirectly) by relative $refs.
+// References resolving outside the root of the bundle (absolute URLs, absolute paths and relative paths
+// escaping the root) are rejected.
+func LoadBundle(entry string, load FileLoader) (*Bundle, error) {
+	bundle := &Bundle{Entry: path.Clean(entry), Files: make(map[string][]byte)}
+
+	queue := []string{bundle.Entry}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if _, ok := bundle.Files[current]; ok {
+			continue
+		}
+
+		content, err := load(current)
+		if err != nil {
+			return nil, ParserError{
+				Code:    CodeExternalReferenceError,
+				Message: fmt.Sprintf("failed to load '%s'", current),
+				Err:     err,
+			}
+		}
+		bundle.Files[current] = content
+
+		refs, err := externalRefs(content)
+		if err != nil {
+			return nil, ParserError{
+				Code:    CodeDocumentCreationError,
+				Message: fmt.Sprintf("failed to parse '%s'", current),
+				Err:     err,
+			}
+		}
+		for _, ref := range refs {
+			resolved, err := resolveBundlePath(path.Dir(current), ref)
+			if err != nil {
+				return nil, ParserError{
+					Code:    CodeExternalReferenceError,
+					Message: fmt.Sprintf("invalid reference '%s' in '%s'", ref, current),
+					Err:     err,
+				}
+			}
+			queue = append(queue, resolved)
+		}
+	}
+
+	return bundle, nil
+}
+
+// externalRefs returns the file part of the $refs of a document pointing to other files
+// (local references, starting with "#", are skipped).
+func externalRefs(content []byte) ([]string, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(content, &root); err != nil {
+		return nil, err
+	}
+
+	var refs []string
+	var walk func(n *yaml.Node)
+	walk = func(n *yaml.Node) {
+		if n.Kind == yaml.MappingNode {
+			for i := 0; i+1 < len(n.Content); i += 2 {
+				if n.Content[i].Value == "$ref" && n.Content[i+1].Kind == yaml.ScalarNode {
+					if file, _, _ := strings.Cut(n.Content[i+1].Value, "#"); file != "" {
+						refs = append(refs, file)
+					}
+				}
+			}
+		}
+		for _, child := range n.Content {
+			walk(child)
+		}
+	}
+	walk(&root)
+
+	return refs, nil
+}
+
+// resolveBundlePath resolves a reference to a file relative to the given directory of the bundle,
+// returning an error if the file is outside the bundle.
+func resolveBundlePath(dir, ref string) (string, error) {
+	if u, err := url.Parse(ref); err != nil || u.Scheme != "" || u.Host != "" {
+		return "", fmt.Errorf("only relative references are allowed")
+	}
+	if path.IsAbs(ref) {
+		return "", fmt.Errorf("absolute paths are not allowed")
+	}
+	resolved := path.Join(dir, ref)
+	if resolved == ".." || strings.HasPrefix(resolved, "../") {
+		return "", fmt.Errorf("the referenced file is outside the bundle")
+	}
+	return resolved, nil
+}
+
+// newBundleFS returns a libopenapi file system serving the files of a bundle from memory,
+// rooted at bundleRoot. Nothing is read from the local disk.
+func newBundleFS(bundle *Bundle) (*index.LocalFS, error) {
+	files := make(fstest.MapFS, len(bundle.Files))
+	for name, content := range bundle.Files {
+		files[name] = &fstest.MapFile{Data: content}
+	}
+	return index.NewLocalFSWithConfig(&index.LocalFSConfig{
+		BaseDirectory: bundleRoot,
+		DirFS:         files,
+	})
+}
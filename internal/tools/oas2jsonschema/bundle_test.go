@@ -0,0 +1,220 @@
+package oas2jsonschema
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var repoBundleFiles = map[string]string{
+	"openapi.yaml": `
+openapi: 3.0.3
+info:
+  title: Repos
+  version: 1.0.0
+paths:
+  /repos/{owner}/{repo}:
+    parameters:
+      - $ref: './parameters.yaml#/owner'
+      - name: repo
+        in: path
+        required: true
+        schema:
+          type: string
+    get:
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: './schemas/repo.yaml#/Repo'
+`,
+	"parameters.yaml": `
+owner:
+  name: owner
+  in: path
+  required: true
+  schema:
+    type: string
+`,
+	"schemas/repo.yaml": `
+Repo:
+  type: object
+  properties:
+    id:
+      type: integer
+    name:
+      type: string
+    owner:
+      $ref: './user.yaml#/User'
+    parent:
+      $ref: '#/Repo'
+`,
+	"schemas/user.yaml": `
+User:
+  type: object
+  properties:
+    login:
+      type: string
+`,
+}
+
+func mapLoader(files map[string]string) FileLoader {
+	return func(path string) ([]byte, error) {
+		content, ok := files[path]
+		if !ok {
+			return nil, fmt.Errorf("file '%s' not found", path)
+		}
+		return []byte(content), nil
+	}
+}
+
+func TestLoadBundle(t *testing.T) {
+	bundle, err := LoadBundle("openapi.yaml", mapLoader(repoBundleFiles))
+	require.NoError(t, err)
+
+	assert.Equal(t, "openapi.yaml", bundle.Entry)
+	assert.Len(t, bundle.Files, 4)
+	for name := range repoBundleFiles {
+		assert.Contains(t, bundle.Files, name)
+	}
+}
+
+func TestBundleDigest(t *testing.T) {
+	single := &Bundle{Entry: "openapi.yaml", Files: map[string][]byte{"openapi.yaml": []byte("openapi: 3.0.3")}}
+	sum := sha256.Sum256([]byte("openapi: 3.0.3"))
+	assert.Equal(t, hex.EncodeToString(sum[:]), single.Digest())
+
+	bundle, err := LoadBundle("openapi.yaml", mapLoader(repoBundleFiles))
+	require.NoError(t, err)
+	same, err := LoadBundle("openapi.yaml", mapLoader(repoBundleFiles))
+	require.NoError(t, err)
+	assert.Equal(t, bundle.Digest(), same.Digest())
+
+	// Editing any file of the bundle changes the digest
+	for name := range bundle.Files {
+		changed := &Bundle{Entry: bundle.Entry, Files: map[string][]byte{}}
+		for n, content := range bundle.Files {
+			changed.Files[n] = content
+		}
+		changed.Files[name] = append([]byte("# edited\n"), changed.Files[name]...)
+		assert.NotEqual(t, bundle.Digest(), changed.Digest(), name)
+	}
+}
+
+func TestLoadBundle_Errors(t *testing.T) {
+	testCases := []struct {
+		name string
+		ref  string
+	}{
+		{name: "remote reference", ref: "https://example.com/schemas.yaml#/Repo"},
+		{name: "absolute path", ref: "/etc/schemas.yaml#/Repo"},
+		{name: "parent directory", ref: "../schemas.yaml#/Repo"},
+		{name: "parent directory after cleaning", ref: "./schemas/../../schemas.yaml#/Repo"},
+		{name: "missing file", ref: "./missing.yaml#/Repo"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			files := map[string]string{
+				"openapi.yaml": fmt.Sprintf(`
+openapi: 3.0.3
+info: {title: t, version: "1"}
+paths:
+  /repos:
+    get:
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: '%s'
+`, tc.ref),
+				"../schemas.yaml": "Repo: {type: object}",
+			}
+
+			_, err := LoadBundle("openapi.yaml", mapLoader(files))
+			var parserErr ParserError
+			require.ErrorAs(t, err, &parserErr)
+			assert.Equal(t, CodeExternalReferenceError, parserErr.Code)
+		})
+	}
+}
+
+func TestParseBundle(t *testing.T) {
+	bundle, err := LoadBundle("openapi.yaml", mapLoader(repoBundleFiles))
+	require.NoError(t, err)
+
+	doc, err := NewLibOASParser().ParseBundle(bundle)
+	require.NoError(t, err)
+
+	path, ok := doc.FindPath("/repos/{owner}/{repo}")
+	require.True(t, ok)
+	get := path.GetOperations()["get"]
+
+	params := get.GetParameters()
+	require.Len(t, params, 2)
+	assert.Equal(t, "owner", params[0].Name)
+	assert.Equal(t, []string{"string"}, params[0].Schema.Type)
+
+	schema := get.GetResponses()[200].Content["application/json"]
+	require.NotNil(t, schema)
+	assert.Equal(t, []string{"id", "name", "owner", "parent"}, propertyNames(schema))
+	assert.Equal(t, []string{"login"}, propertyNames(schema.Properties[2].Schema))
+	assert.Equal(t, []string{"id", "name", "owner", "parent"}, propertyNames(schema.Properties[3].Schema))
+}
+
+func TestParseBundle_MissingEntry(t *testing.T) {
+	_, err := NewLibOASParser().ParseBundle(&Bundle{Entry: "openapi.yaml", Files: map[string][]byte{}})
+	var parserErr ParserError
+	require.ErrorAs(t, err, &parserErr)
+	assert.Equal(t, CodeExternalReferenceError, parserErr.Code)
+}
+
+func TestParseBundle_SameReferenceInDifferentFiles(t *testing.T) {
+	files := map[string]string{
+		"openapi.yaml": `
+openapi: 3.0.3
+info: {title: t, version: "1"}
+paths:
+  /things:
+    get:
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  a: {$ref: './a.yaml#/Thing'}
+                  b: {$ref: './b.yaml#/Thing'}
+`,
+		"a.yaml": `
+Thing: {type: object, properties: {inner: {$ref: '#/Inner'}}}
+Inner: {type: object, properties: {fromA: {type: string}}}
+`,
+		"b.yaml": `
+Thing: {type: object, properties: {inner: {$ref: '#/Inner'}}}
+Inner: {type: object, properties: {fromB: {type: string}}}
+`,
+	}
+
+	bundle, err := LoadBundle("openapi.yaml", mapLoader(files))
+	require.NoError(t, err)
+	doc, err := NewLibOASParser().ParseBundle(bundle)
+	require.NoError(t, err)
+
+	path, ok := doc.FindPath("/things")
+	require.True(t, ok)
+	schema := path.GetOperations()["get"].GetResponses()[200].Content["application/json"]
+	require.NotNil(t, schema)
+	assert.Equal(t, []string{"fromA"}, propertyNames(schema.Properties[0].Schema.Properties[0].Schema))
+	assert.Equal(t, []string{"fromB"}, propertyNames(schema.Properties[1].Schema.Properties[0].Schema))
+}
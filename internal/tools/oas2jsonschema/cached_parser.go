@@ -0,0 +1,70 @@
+package oas2jsonschema
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sync/atomic"
+
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/cache"
+)
+
+// CachedParser is a Parser caching the parsed documents by the SHA-256 digest of their contents (see Bundle.Digest),
+// so that documents shared by many RestDefinitions are parsed once. The cache is bounded by the total size of the
+// contents of the cached documents. Parsing errors are not cached.
+// The cached documents are shared, so they must not be modified.
+type CachedParser struct {
+	parser Parser
+	docs   *cache.LRU[string, OASDocument]
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+// NewCachedParser returns a Parser caching the documents parsed by the given parser, up to maxSize bytes of contents.
+func NewCachedParser(parser Parser, maxSize int64) *CachedParser {
+	return &CachedParser{
+		parser: parser,
+		docs:   cache.NewLRU[string, OASDocument](maxSize),
+	}
+}
+
+func (p *CachedParser) Parse(content []byte) (OASDocument, error) {
+	sum := sha256.Sum256(content)
+	return p.parse(hex.EncodeToString(sum[:]), int64(len(content)), func() (OASDocument, error) {
+		return p.parser.Parse(content)
+	})
+}
+
+func (p *CachedParser) ParseBundle(bundle *Bundle) (OASDocument, error) {
+	var size int64
+	for _, content := range bundle.Files {
+		size += int64(len(content))
+	}
+	return p.parse(bundle.Digest(), size, func() (OASDocument, error) {
+		return p.parser.ParseBundle(bundle)
+	})
+}
+
+func (p *CachedParser) parse(digest string, size int64, parse func() (OASDocument, error)) (OASDocument, error) {
+	if doc, ok := p.docs.Get(digest); ok {
+		p.hits.Add(1)
+		return doc, nil
+	}
+	p.misses.Add(1)
+
+	doc, err := parse()
+	if err != nil {
+		return nil, err
+	}
+	p.docs.Add(digest, doc, size)
+	return doc, nil
+}
+
+// Stats returns the usage of the cache, the size being the total size of the contents of the cached documents.
+func (p *CachedParser) Stats() cache.Stats {
+	return cache.Stats{
+		Hits:    p.hits.Load(),
+		Misses:  p.misses.Load(),
+		Entries: p.docs.Len(),
+		Size:    p.docs.Size(),
+	}
+}
@@ -0,0 +1,63 @@
+package oas2jsonschema
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingParser counts the documents it parses.
+type countingParser struct {
+	parsed int
+	err    error
+}
+
+func (p *countingParser) Parse(content []byte) (OASDocument, error) {
+	p.parsed++
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &mockOASDocument{}, nil
+}
+
+func (p *countingParser) ParseBundle(bundle *Bundle) (OASDocument, error) {
+	return p.Parse(bundle.Files[bundle.Entry])
+}
+
+func TestCachedParser(t *testing.T) {
+	inner := &countingParser{}
+	parser := NewCachedParser(inner, 1024)
+
+	first, err := parser.Parse([]byte("openapi: 3.0.0"))
+	require.NoError(t, err)
+	second, err := parser.Parse([]byte("openapi: 3.0.0"))
+	require.NoError(t, err)
+	assert.Same(t, first, second)
+
+	// A single-file bundle has the same digest as its contents
+	bundled, err := parser.ParseBundle(&Bundle{Entry: "openapi.yaml", Files: map[string][]byte{"openapi.yaml": []byte("openapi: 3.0.0")}})
+	require.NoError(t, err)
+	assert.Same(t, first, bundled)
+
+	_, err = parser.Parse([]byte("openapi: 3.1.0"))
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, inner.parsed)
+	assert.Equal(t, cache.Stats{Hits: 2, Misses: 2, Entries: 2, Size: 28}, parser.Stats())
+}
+
+func TestCachedParser_Errors(t *testing.T) {
+	inner := &countingParser{err: errors.New("invalid document")}
+	parser := NewCachedParser(inner, 1024)
+
+	_, err := parser.Parse([]byte("invalid"))
+	assert.Error(t, err)
+	_, err = parser.Parse([]byte("invalid"))
+	assert.Error(t, err)
+
+	assert.Equal(t, 2, inner.parsed)
+	assert.Equal(t, 0, parser.Stats().Entries)
+}
@@ -0,0 +1,446 @@
+package oas2jsonschema
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/safety"
+)
+
+// Kubernetes structural schemas do not allow "oneOf" and "anyOf" to define types or properties.
+// Therefore, before generating the CRD, every oneOf/anyOf is resolved into a single structural schema
+// using one of the following strategies:
+//   - object variants are merged into a property union, and the choice between the variants is enforced
+//     with a CEL rule (x-kubernetes-validations) based on the required fields of each variant;
+//   - array variants are resolved by applying the same strategies to their items;
+//   - scalar variants of the same type are collapsed into a single schema of that type
+//     (integer and number variants are collapsed into a number);
+//   - integer and string variants are mapped to `x-kubernetes-int-or-string`;
+//   - anything else (mixed types, conflicting properties) becomes a subtree that preserves unknown fields.
+//
+// Every choice is reported as a SchemaGenerationError warning.
+
+const (
+	extensionValidations           = "x-kubernetes-validations"
+	extensionPreserveUnknownFields = "x-kubernetes-preserve-unknown-fields"
+	extensionIntOrString           = "x-kubernetes-int-or-string"
+)
+
+// celIdentifier matches property names that can be used as-is in a CEL expression.
+var celIdentifier = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// celEscapable matches property names that can be escaped following the Kubernetes rules.
+// Source: https://kubernetes.io/docs/tasks/extend-kubernetes/custom-resources/custom-resource-definitions/#escaping
+var celEscapable = regexp.MustCompile(`^[a-zA-Z_.\-/][a-zA-Z0-9_.\-/]*$`)
+
+// celReservedKeywords are the CEL reserved words that must be escaped when used as property names.
+var celReservedKeywords = []string{
+	"true", "false", "null", "in", "as", "break", "const", "continue", "else",
+	"for", "function", "if", "import", "let", "loop", "package", "namespace",
+	"return", "var", "void", "while",
+}
+
+// resolveComposition resolves the variants of a "oneOf" or "anyOf" (keyword) into the given schema.
+// The variants are expected to be already prepared for CRD generation.
+// The caller is responsible for clearing the composition field of the schema.
+func resolveComposition(schema *Schema, keyword string, variants []*Schema, path string) []error {
+	// Variants that only allow null do not contribute to the structure of the schema,
+	// they only make it nullable.
+	var nonNullVariants []*Schema
+	for _, variant := range variants {
+		if variant == nil {
+			continue
+		}
+		if isNullOnlySchema(variant) {
+			schema.Nullable = true
+			continue
+		}
+		nonNullVariants = append(nonNullVariants, variant)
+	}
+	if len(nonNullVariants) == 0 {
+		return nil
+	}
+
+	variantTypes := make([]string, 0, len(nonNullVariants))
+	for _, variant := range nonNullVariants {
+		if t := compositionType(variant); !slices.Contains(variantTypes, t) {
+			variantTypes = append(variantTypes, t)
+		}
+	}
+	parentType := compositionType(schema)
+
+	if len(variantTypes) == 1 && variantTypes[0] != "" && (parentType == "" || parentType == variantTypes[0]) {
+		switch variantTypes[0] {
+		case "object":
+			warnings, err := mergeObjectVariants(schema, keyword, nonNullVariants, path)
+			if err == nil {
+				return warnings
+			}
+			return preserveComposition(schema, keyword, path, err.Error())
+
+		case "array":
+			return mergeArrayVariants(schema, keyword, nonNullVariants, path)
+
+		default:
+			return mergeScalarVariants(schema, keyword, variantTypes[0], nonNullVariants, path)
+		}
+	}
+
+	if (parentType == "" || parentType == "number") && len(variantTypes) == 2 && slices.Contains(variantTypes, "integer") && slices.Contains(variantTypes, "number") {
+		// Every integer is a valid number.
+		return mergeScalarVariants(schema, keyword, "number", nonNullVariants, path)
+	}
+
+	if parentType == "" && len(variantTypes) == 2 && slices.Contains(variantTypes, "integer") && slices.Contains(variantTypes, "string") {
+		schema.Type = nil
+		setExtension(schema, extensionIntOrString, true)
+		return []error{SchemaGenerationError{
+			Path:    path,
+			Code:    CodeCompositionMerged,
+			Message: fmt.Sprintf("%s with integer and string variants mapped to '%s'", keyword, extensionIntOrString),
+		}}
+	}
+
+	return preserveComposition(schema, keyword, path, fmt.Sprintf("variants have incompatible types %v", variantTypes))
+}
+
+// mergeObjectVariants merges the properties of all object variants into the schema (property union).
+// Properties required by every variant become required, while the choice between the variants
+// is enforced by a CEL rule based on the remaining required fields of each variant.
+// It returns an error, without modifying the schema, if the same property has incompatible types in different variants.
+func mergeObjectVariants(schema *Schema, keyword string, variants []*Schema, path string) ([]error, error) {
+	propTypes := make(map[string][]string)
+	for _, p := range schema.Properties {
+		propTypes[p.Name] = schemaTypes(p.Schema)
+	}
+	for _, variant := range variants {
+		for _, p := range variant.Properties {
+			existing, ok := propTypes[p.Name]
+			if ok && !areTypesCompatible(existing, schemaTypes(p.Schema)) {
+				return nil, fmt.Errorf("property '%s' has incompatible types %v and %v", p.Name, existing, schemaTypes(p.Schema))
+			}
+			if !ok {
+				propTypes[p.Name] = schemaTypes(p.Schema)
+			}
+		}
+	}
+
+	if len(schema.Type) == 0 {
+		schema.Type = []string{"object"}
+	}
+
+	for _, variant := range variants {
+		for _, p := range variant.Properties {
+			if !slices.ContainsFunc(schema.Properties, func(existing Property) bool { return existing.Name == p.Name }) {
+				schema.Properties = append(schema.Properties, p)
+			}
+		}
+	}
+
+	// Fields required by every variant are required in the merged schema as well.
+	var commonRequired []string
+	for _, req := range variants[0].Required {
+		requiredByAll := true
+		for _, variant := range variants[1:] {
+			if !slices.Contains(variant.Required, req) {
+				requiredByAll = false
+				break
+			}
+		}
+		if requiredByAll && !slices.Contains(commonRequired, req) {
+			commonRequired = append(commonRequired, req)
+		}
+	}
+	for _, req := range commonRequired {
+		if !slices.Contains(schema.Required, req) {
+			schema.Required = append(schema.Required, req)
+		}
+	}
+
+	warnings := []error{SchemaGenerationError{
+		Path:    path,
+		Code:    CodeCompositionMerged,
+		Message: fmt.Sprintf("%s with %d object variants merged into a property union", keyword, len(variants)),
+	}}
+
+	rule, message, err := buildVariantsRule(keyword, variants, commonRequired)
+	if err != nil {
+		warnings = append(warnings, SchemaGenerationError{
+			Path:    path,
+			Code:    CodeCompositionRuleSkipped,
+			Message: fmt.Sprintf("no validation rule generated for %s: %v", keyword, err),
+		})
+		return warnings, nil
+	}
+	if rule != "" {
+		addValidationRule(schema, rule, message)
+	}
+
+	return warnings, nil
+}
+
+// buildVariantsRule builds a CEL rule that checks which variants are set, identifying each variant
+// by its required fields (excluding the ones required by all variants).
+// For "oneOf" exactly one variant must be set, for "anyOf" at least one.
+// An empty rule (and no error) is returned when the rule would always be satisfied.
+func buildVariantsRule(keyword string, variants []*Schema, commonRequired []string) (string, string, error) {
+	conditions := make([]string, 0, len(variants))
+	descriptions := make([]string, 0, len(variants))
+	for i, variant := range variants {
+		var checks []string
+		var fields []string
+		for _, req := range variant.Required {
+			if slices.Contains(commonRequired, req) {
+				continue
+			}
+			field, ok := escapeCELFieldName(req)
+			if !ok {
+				return "", "", fmt.Errorf("required field '%s' of variant %d cannot be used in a CEL expression", req, i)
+			}
+			checks = append(checks, fmt.Sprintf("has(self.%s)", field))
+			fields = append(fields, req)
+		}
+
+		if len(checks) == 0 {
+			if keyword == "anyOf" {
+				// This variant is always satisfied, therefore also the anyOf is.
+				return "", "", nil
+			}
+			return "", "", fmt.Errorf("variant %d has no distinguishing required fields", i)
+		}
+
+		conditions = append(conditions, strings.Join(checks, " && "))
+		descriptions = append(descriptions, fmt.Sprintf("[%s]", strings.Join(fields, ", ")))
+	}
+
+	if keyword == "oneOf" {
+		return fmt.Sprintf("[%s].exists_one(v, v)", strings.Join(conditions, ", ")),
+			fmt.Sprintf("exactly one of the following sets of fields must be specified: %s", strings.Join(descriptions, ", ")),
+			nil
+	}
+	return fmt.Sprintf("[%s].exists(v, v)", strings.Join(conditions, ", ")),
+		fmt.Sprintf("at least one of the following sets of fields must be specified: %s", strings.Join(descriptions, ", ")),
+		nil
+}
+
+// mergeArrayVariants resolves array variants by resolving the composition of their items.
+func mergeArrayVariants(schema *Schema, keyword string, variants []*Schema, path string) []error {
+	schema.Type = []string{"array"}
+
+	var itemVariants []*Schema
+	for _, variant := range variants {
+		if variant.Items != nil {
+			itemVariants = append(itemVariants, variant.Items)
+		}
+	}
+	if schema.Items != nil {
+		// Items declared on the parent schema apply to all the variants.
+		itemVariants = append([]*Schema{schema.Items}, itemVariants...)
+	}
+	if len(itemVariants) == 0 {
+		return nil
+	}
+
+	items := &Schema{}
+	schema.Items = items
+	return resolveComposition(items, keyword, itemVariants, path)
+}
+
+// mergeScalarVariants collapses scalar variants of the same type into a single schema.
+// Enum values are merged only if every variant defines them, otherwise any value of the type is allowed.
+func mergeScalarVariants(schema *Schema, keyword string, scalarType string, variants []*Schema, path string) []error {
+	schema.Type = []string{scalarType}
+
+	allEnums := true
+	var mergedEnum []interface{}
+	for _, variant := range variants {
+		if len(variant.Enum) == 0 {
+			allEnums = false
+			break
+		}
+		for _, enumVal := range variant.Enum {
+			if !slices.Contains(mergedEnum, enumVal) {
+				mergedEnum = append(mergedEnum, enumVal)
+			}
+		}
+	}
+	if allEnums && len(schema.Enum) == 0 {
+		schema.Enum = mergedEnum
+	}
+
+	return []error{SchemaGenerationError{
+		Path:    path,
+		Code:    CodeCompositionMerged,
+		Message: fmt.Sprintf("%s with %d variants of type '%s' collapsed into a single schema", keyword, len(variants), scalarType),
+	}}
+}
+
+// preserveComposition replaces the composition with a subtree that preserves unknown fields.
+// Object schemas keep their own properties, any other schema becomes untyped.
+func preserveComposition(schema *Schema, keyword string, path string, reason string) []error {
+	if compositionType(schema) != "object" {
+		schema.Type = nil
+		schema.Properties = nil
+		schema.Items = nil
+		schema.Required = nil
+		schema.Enum = nil
+	}
+	setExtension(schema, extensionPreserveUnknownFields, true)
+
+	return []error{SchemaGenerationError{
+		Path:    path,
+		Code:    CodeCompositionPreserved,
+		Message: fmt.Sprintf("%s replaced by a schema preserving unknown fields: %s", keyword, reason),
+	}}
+}
+
+// compositionType returns the primary type of a schema, inferring it from the structure when the type is not set.
+func compositionType(schema *Schema) string {
+	if t := getPrimaryType(schema.Type); t != "" {
+		return t
+	}
+	if len(schema.Properties) > 0 {
+		return "object"
+	}
+	if schema.Items != nil {
+		return "array"
+	}
+	return ""
+}
+
+// isNullOnlySchema checks if the schema only allows the null value (e.g., `type: "null"` in OAS 3.1).
+func isNullOnlySchema(schema *Schema) bool {
+	return isNullOnlyType(schema.Type)
+}
+
+// schemaTypes returns the types of a schema, handling nil schemas.
+func schemaTypes(schema *Schema) []string {
+	if schema == nil {
+		return nil
+	}
+	return schema.Type
+}
+
+// setExtension sets an extension on the schema, initializing the extensions map if needed.
+func setExtension(schema *Schema, key string, value interface{}) {
+	if schema.Extensions == nil {
+		schema.Extensions = make(map[string]interface{})
+	}
+	schema.Extensions[key] = value
+}
+
+// addValidationRule appends a CEL rule to the `x-kubernetes-validations` extension of the schema.
+func addValidationRule(schema *Schema, rule, message string) {
+	var rules []interface{}
+	if existing, ok := schema.Extensions[extensionValidations].([]interface{}); ok {
+		rules = existing
+	}
+	rules = append(rules, map[string]interface{}{
+		"rule":    rule,
+		"message": message,
+	})
+	setExtension(schema, extensionValidations, rules)
+}
+
+// celSelfField matches the fields of self referenced by a CEL rule (e.g., 'name' in 'has(self.name)').
+var celSelfField = regexp.MustCompile(`\bself\.([a-zA-Z_][a-zA-Z0-9_]*)`)
+
+// dropStaleValidationRules removes, at any depth, the CEL rules referencing fields not defined by their schema anymore
+// (e.g., a field required by a oneOf variant, removed afterwards as a configuration or excluded field),
+// since the API server rejects CRDs with such rules.
+// It returns a warning for each removed rule.
+func dropStaleValidationRules(schema *Schema, config *GeneratorConfig, path string) []error {
+	if schema == nil {
+		return nil
+	}
+
+	guard := safety.NewRecursionGuard(config.MaxRecursionDepth, config.MaxRecursionNodes, config.RecursionTimeout)
+	ctx, cancel := guard.WithContext()
+	defer cancel()
+
+	return dropStaleValidationRulesRec(ctx, schema, guard, make(map[*Schema]struct{}), 0, path)
+}
+
+// dropStaleValidationRulesRec is the recursive implementation of dropStaleValidationRules.
+// It tracks visited schemas to handle circular references.
+func dropStaleValidationRulesRec(ctx context.Context, schema *Schema, guard *safety.RecursionGuard, visited map[*Schema]struct{}, depth int, path string) []error {
+	if schema == nil || guard.Check(ctx, depth) != nil {
+		return nil
+	}
+	if _, ok := visited[schema]; ok {
+		return nil
+	}
+	visited[schema] = struct{}{}
+
+	var warnings []error
+
+	if rules, ok := schema.Extensions[extensionValidations].([]interface{}); ok {
+		fields := make(map[string]struct{}, len(schema.Properties))
+		for _, prop := range schema.Properties {
+			if field, ok := escapeCELFieldName(prop.Name); ok {
+				fields[field] = struct{}{}
+			}
+		}
+
+		kept := make([]interface{}, 0, len(rules))
+		for _, r := range rules {
+			rule, _ := r.(map[string]interface{})["rule"].(string)
+			if missing := missingCELFields(rule, fields); len(missing) > 0 {
+				warnings = append(warnings, SchemaGenerationError{
+					Path:    path,
+					Code:    CodeCompositionRuleSkipped,
+					Message: fmt.Sprintf("validation rule '%s' removed: fields %v not in the schema anymore", rule, missing),
+				})
+				continue
+			}
+			kept = append(kept, r)
+		}
+		if len(kept) > 0 {
+			schema.Extensions[extensionValidations] = kept
+		} else {
+			delete(schema.Extensions, extensionValidations)
+		}
+	}
+
+	for _, prop := range schema.Properties {
+		warnings = append(warnings, dropStaleValidationRulesRec(ctx, prop.Schema, guard, visited, depth+1, buildPath(path, prop.Name))...)
+	}
+	warnings = append(warnings, dropStaleValidationRulesRec(ctx, schema.Items, guard, visited, depth+1, path)...)
+	warnings = append(warnings, dropStaleValidationRulesRec(ctx, schema.AdditionalPropertiesSchema, guard, visited, depth+1, path)...)
+
+	return warnings
+}
+
+// missingCELFields returns the fields of self referenced by the CEL rule that are not in the given (escaped) fields.
+func missingCELFields(rule string, fields map[string]struct{}) []string {
+	var missing []string
+	for _, match := range celSelfField.FindAllStringSubmatch(rule, -1) {
+		if _, ok := fields[match[1]]; !ok && !slices.Contains(missing, match[1]) {
+			missing = append(missing, match[1])
+		}
+	}
+	return missing
+}
+
+// escapeCELFieldName escapes a property name to be used in a CEL expression, following the Kubernetes rules.
+// It returns false if the property name cannot be escaped.
+func escapeCELFieldName(name string) (string, bool) {
+	if slices.Contains(celReservedKeywords, name) {
+		return fmt.Sprintf("__%s__", name), true
+	}
+	if celIdentifier.MatchString(name) && !strings.Contains(name, "__") {
+		return name, true
+	}
+	if !celEscapable.MatchString(name) {
+		return "", false
+	}
+
+	escaped := strings.ReplaceAll(name, "__", "__underscores__")
+	escaped = strings.ReplaceAll(escaped, ".", "__dot__")
+	escaped = strings.ReplaceAll(escaped, "-", "__dash__")
+	escaped = strings.ReplaceAll(escaped, "/", "__slash__")
+	return escaped, true
+}
@@ -0,0 +1,280 @@
+package oas2jsonschema
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// generationCodes extracts the codes of the SchemaGenerationError warnings.
+func generationCodes(warnings []error) []GenerationCode {
+	var codes []GenerationCode
+	for _, w := range warnings {
+		var genErr SchemaGenerationError
+		if errors.As(w, &genErr) {
+			codes = append(codes, genErr.Code)
+		}
+	}
+	return codes
+}
+
+func propertyNames(schema *Schema) []string {
+	var names []string
+	for _, p := range schema.Properties {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+func TestPrepareSchemaForCRD_OneOfAnyOf(t *testing.T) {
+	testCases := []struct {
+		name          string
+		schema        *Schema
+		expectedCodes []GenerationCode
+		assertSchema  func(t *testing.T, schema *Schema)
+	}{
+		{
+			name: "oneOf object variants are merged into a property union with a CEL rule",
+			schema: &Schema{
+				OneOf: []*Schema{
+					{
+						Type:     []string{"object"},
+						Required: []string{"kind", "sshUrl"},
+						Properties: []Property{
+							{Name: "kind", Schema: &Schema{Type: []string{"string"}}},
+							{Name: "sshUrl", Schema: &Schema{Type: []string{"string"}}},
+						},
+					},
+					{
+						Type:     []string{"object"},
+						Required: []string{"kind", "http-url", "token"},
+						Properties: []Property{
+							{Name: "kind", Schema: &Schema{Type: []string{"string"}}},
+							{Name: "http-url", Schema: &Schema{Type: []string{"string"}}},
+							{Name: "token", Schema: &Schema{Type: []string{"string"}}},
+						},
+					},
+				},
+			},
+			expectedCodes: []GenerationCode{CodeCompositionMerged},
+			assertSchema: func(t *testing.T, schema *Schema) {
+				assert.Equal(t, []string{"object"}, schema.Type)
+				assert.Equal(t, []string{"kind", "sshUrl", "http-url", "token"}, propertyNames(schema))
+				assert.Equal(t, []string{"kind"}, schema.Required)
+				assert.Nil(t, schema.OneOf)
+
+				rules, ok := schema.Extensions[extensionValidations].([]interface{})
+				require.True(t, ok)
+				require.Len(t, rules, 1)
+				rule := rules[0].(map[string]interface{})
+				assert.Equal(t, "[has(self.sshUrl), has(self.http__dash__url) && has(self.token)].exists_one(v, v)", rule["rule"])
+				assert.Contains(t, rule["message"], "exactly one")
+			},
+		},
+		{
+			name: "anyOf with an always matching variant does not generate a CEL rule",
+			schema: &Schema{
+				Type: []string{"object"},
+				AnyOf: []*Schema{
+					{Properties: []Property{{Name: "a", Schema: &Schema{Type: []string{"string"}}}}},
+					{Required: []string{"b"}, Properties: []Property{{Name: "b", Schema: &Schema{Type: []string{"integer"}}}}},
+				},
+			},
+			expectedCodes: []GenerationCode{CodeCompositionMerged},
+			assertSchema: func(t *testing.T, schema *Schema) {
+				assert.Equal(t, []string{"a", "b"}, propertyNames(schema))
+				assert.Empty(t, schema.Required)
+				assert.NotContains(t, schema.Extensions, extensionValidations)
+			},
+		},
+		{
+			name: "oneOf variants without distinguishing required fields skip the CEL rule",
+			schema: &Schema{
+				OneOf: []*Schema{
+					{Properties: []Property{{Name: "a", Schema: &Schema{Type: []string{"string"}}}}},
+					{Properties: []Property{{Name: "b", Schema: &Schema{Type: []string{"string"}}}}},
+				},
+			},
+			expectedCodes: []GenerationCode{CodeCompositionMerged, CodeCompositionRuleSkipped},
+			assertSchema: func(t *testing.T, schema *Schema) {
+				assert.Equal(t, []string{"a", "b"}, propertyNames(schema))
+				assert.NotContains(t, schema.Extensions, extensionValidations)
+			},
+		},
+		{
+			name: "object variants with conflicting property types preserve unknown fields",
+			schema: &Schema{
+				Type: []string{"object"},
+				Properties: []Property{
+					{Name: "name", Schema: &Schema{Type: []string{"string"}}},
+				},
+				OneOf: []*Schema{
+					{Properties: []Property{{Name: "value", Schema: &Schema{Type: []string{"string"}}}}},
+					{Properties: []Property{{Name: "value", Schema: &Schema{Type: []string{"boolean"}}}}},
+				},
+			},
+			expectedCodes: []GenerationCode{CodeCompositionPreserved},
+			assertSchema: func(t *testing.T, schema *Schema) {
+				assert.Equal(t, []string{"object"}, schema.Type)
+				assert.Equal(t, []string{"name"}, propertyNames(schema))
+				assert.Equal(t, true, schema.Extensions[extensionPreserveUnknownFields])
+			},
+		},
+		{
+			name: "scalar variants of the same type are collapsed and enums merged",
+			schema: &Schema{
+				OneOf: []*Schema{
+					{Type: []string{"string"}, Enum: []interface{}{"small", "medium"}},
+					{Type: []string{"string"}, Enum: []interface{}{"medium", "large"}},
+					{Type: []string{"null"}},
+				},
+			},
+			expectedCodes: []GenerationCode{CodeCompositionMerged},
+			assertSchema: func(t *testing.T, schema *Schema) {
+				assert.Equal(t, []string{"string"}, schema.Type)
+				assert.Equal(t, []interface{}{"small", "medium", "large"}, schema.Enum)
+				assert.True(t, schema.Nullable)
+			},
+		},
+		{
+			name: "integer and string variants are mapped to int-or-string",
+			schema: &Schema{
+				AnyOf: []*Schema{
+					{Type: []string{"integer"}},
+					{Type: []string{"string"}},
+				},
+			},
+			expectedCodes: []GenerationCode{CodeCompositionMerged},
+			assertSchema: func(t *testing.T, schema *Schema) {
+				assert.Empty(t, schema.Type)
+				assert.Equal(t, true, schema.Extensions[extensionIntOrString])
+			},
+		},
+		{
+			name: "mixed variant types preserve unknown fields",
+			schema: &Schema{
+				OneOf: []*Schema{
+					{Type: []string{"boolean"}},
+					{Type: []string{"object"}, Properties: []Property{{Name: "a", Schema: &Schema{Type: []string{"string"}}}}},
+				},
+			},
+			expectedCodes: []GenerationCode{CodeCompositionPreserved},
+			assertSchema: func(t *testing.T, schema *Schema) {
+				assert.Empty(t, schema.Type)
+				assert.Empty(t, schema.Properties)
+				assert.Equal(t, true, schema.Extensions[extensionPreserveUnknownFields])
+			},
+		},
+		{
+			name: "array variants are resolved through their items",
+			schema: &Schema{
+				OneOf: []*Schema{
+					{Type: []string{"array"}, Items: &Schema{Properties: []Property{{Name: "a", Schema: &Schema{Type: []string{"string"}}}}, Required: []string{"a"}}},
+					{Type: []string{"array"}, Items: &Schema{Properties: []Property{{Name: "b", Schema: &Schema{Type: []string{"string"}}}}, Required: []string{"b"}}},
+				},
+			},
+			expectedCodes: []GenerationCode{CodeCompositionMerged},
+			assertSchema: func(t *testing.T, schema *Schema) {
+				assert.Equal(t, []string{"array"}, schema.Type)
+				require.NotNil(t, schema.Items)
+				assert.Equal(t, []string{"a", "b"}, propertyNames(schema.Items))
+				assert.Contains(t, schema.Items.Extensions, extensionValidations)
+			},
+		},
+		{
+			name: "nested oneOf inside a property reports the property path",
+			schema: &Schema{
+				Type: []string{"object"},
+				Properties: []Property{
+					{Name: "source", Schema: &Schema{
+						OneOf: []*Schema{{Type: []string{"integer"}}, {Type: []string{"integer"}}},
+					}},
+				},
+			},
+			expectedCodes: []GenerationCode{CodeCompositionMerged},
+			assertSchema: func(t *testing.T, schema *Schema) {
+				assert.Equal(t, []string{"integer"}, schema.Properties[0].Schema.Type)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			warnings, err := prepareSchemaForCRD(tc.schema, DefaultGeneratorConfig())
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedCodes, generationCodes(warnings))
+			tc.assertSchema(t, tc.schema)
+		})
+	}
+}
+
+func TestPrepareSchemaForCRD_OneOfWarningPath(t *testing.T) {
+	schema := &Schema{
+		Type: []string{"object"},
+		Properties: []Property{
+			{Name: "spec", Schema: &Schema{
+				Type: []string{"object"},
+				Properties: []Property{
+					{Name: "value", Schema: &Schema{OneOf: []*Schema{{Type: []string{"boolean"}}, {Type: []string{"string"}}}}},
+				},
+			}},
+		},
+	}
+
+	warnings, err := prepareSchemaForCRD(schema, DefaultGeneratorConfig())
+	require.NoError(t, err)
+	require.Len(t, warnings, 1)
+
+	var genErr SchemaGenerationError
+	require.True(t, errors.As(warnings[0], &genErr))
+	assert.Equal(t, "spec.value", genErr.Path)
+}
+
+func TestGenerateJsonSchema_OneOfResolved(t *testing.T) {
+	schema := &Schema{
+		OneOf: []*Schema{
+			{Required: []string{"a"}, Properties: []Property{{Name: "a", Schema: &Schema{Type: []string{"string"}}}}},
+			{Required: []string{"b"}, Properties: []Property{{Name: "b", Schema: &Schema{Type: []string{"string"}}}}},
+		},
+	}
+
+	_, err := prepareSchemaForCRD(schema, DefaultGeneratorConfig())
+	require.NoError(t, err)
+
+	out, err := GenerateJsonSchema(schema, DefaultGeneratorConfig())
+	require.NoError(t, err)
+
+	var m map[string]interface{}
+	require.NoError(t, json.Unmarshal(out, &m))
+	assert.NotContains(t, m, "oneOf")
+	assert.Equal(t, "object", m["type"])
+	assert.Contains(t, m, extensionValidations)
+}
+
+func TestEscapeCELFieldName(t *testing.T) {
+	testCases := []struct {
+		name     string
+		expected string
+		ok       bool
+	}{
+		{name: "simple", expected: "simple", ok: true},
+		{name: "camelCase_1", expected: "camelCase_1", ok: true},
+		{name: "namespace", expected: "__namespace__", ok: true},
+		{name: "x-api-key", expected: "x__dash__api__dash__key", ok: true},
+		{name: "a.b/c", expected: "a__dot__b__slash__c", ok: true},
+		{name: "a__b", expected: "a__underscores__b", ok: true},
+		{name: "1abc", expected: "", ok: false},
+		{name: "with space", expected: "", ok: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			escaped, ok := escapeCELFieldName(tc.name)
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.expected, escaped)
+		})
+	}
+}
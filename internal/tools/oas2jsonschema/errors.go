@@ -12,6 +12,10 @@ const (
 	CodeModelBuildError ParserErrorCode = "ModelBuildError"
 	// CodeModelResolutionError indicates an error when resolving references within the model.
 	CodeModelResolutionError ParserErrorCode = "ModelResolutionError"
+	// CodeSwaggerConversionError indicates an error when converting a Swagger 2.0 document to OAS 3.0.
+	CodeSwaggerConversionError ParserErrorCode = "SwaggerConversionError"
+	// CodeExternalReferenceError indicates that a file referenced by the document could not be loaded or is outside the bundle.
+	CodeExternalReferenceError ParserErrorCode = "ExternalReferenceError"
 )
 
 // ParserError represents a structured error from the OAS parser.
@@ -55,6 +59,24 @@ const (
 	CodeNoStatusSchema GenerationCode = "NoStatusSchema"
 	// CodeFieldNotFound indicates that a field was not found in the schema.
 	CodeFieldNotFound GenerationCode = "FieldNotFound"
+	// CodeCompositionMerged indicates that a oneOf/anyOf was merged into a single structural schema.
+	CodeCompositionMerged GenerationCode = "CompositionMerged"
+	// CodeCompositionPreserved indicates that a oneOf/anyOf was replaced by a schema preserving unknown fields.
+	CodeCompositionPreserved GenerationCode = "CompositionPreserved"
+	// CodeCompositionRuleSkipped indicates that no CEL validation rule could be generated for a oneOf/anyOf.
+	CodeCompositionRuleSkipped GenerationCode = "CompositionRuleSkipped"
+	// CodeUniqueItemsConverted indicates that "uniqueItems" was converted to `x-kubernetes-list-type: set`.
+	CodeUniqueItemsConverted GenerationCode = "UniqueItemsConverted"
+	// CodeValidationKeywordDropped indicates that a validation keyword could not be represented in the CRD and was dropped.
+	CodeValidationKeywordDropped GenerationCode = "ValidationKeywordDropped"
+	// CodeReadOnlyFieldRemoved indicates that a readOnly field was removed from the spec schema.
+	CodeReadOnlyFieldRemoved GenerationCode = "ReadOnlyFieldRemoved"
+	// CodeWriteOnlyFieldRemoved indicates that a writeOnly field was removed from the status schema.
+	CodeWriteOnlyFieldRemoved GenerationCode = "WriteOnlyFieldRemoved"
+	// CodeSwaggerConversionLossy indicates that a Swagger 2.0 construct could not be converted to OAS 3.0 without losing information.
+	CodeSwaggerConversionLossy GenerationCode = "SwaggerConversionLossy"
+	// CodeAdditionalPropertiesDropped indicates that the schema form of "additionalProperties" was dropped since "properties" are also defined.
+	CodeAdditionalPropertiesDropped GenerationCode = "AdditionalPropertiesDropped"
 )
 
 // SchemaGenerationError defines a structured error for schema generation warnings.
@@ -83,6 +105,8 @@ const (
 	CodeActionSchemaMissing ValidationCode = "ActionSchemaMissing"
 	// CodeTypeMismatch indicates a type mismatch between two schemas.
 	CodeTypeMismatch ValidationCode = "TypeMismatch"
+	// CodeNumericTypeMismatch indicates that one schema uses "number" while the other uses "integer".
+	CodeNumericTypeMismatch ValidationCode = "NumericTypeMismatch"
 	// CodePropertyMismatch indicates that one schema has properties while the other does not.
 	CodePropertyMismatch ValidationCode = "PropertyMismatch"
 	// CodeMissingArrayItems indicates that one schema has array items while the other does not.
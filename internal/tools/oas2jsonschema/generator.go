@@ -22,7 +22,8 @@ func NewOASSchemaGenerator(doc OASDocument, config *GeneratorConfig, resourceCon
 
 // Generate orchestrates the full schema (spec + status) generation process along with configuration schema if needed.
 func (g *OASSchemaGenerator) Generate() (*GenerationResult, error) {
-	var generationWarnings []error
+	// Warnings from the parsing of the document (e.g., lossy Swagger 2.0 conversion steps)
+	generationWarnings := append([]error{}, g.doc.Warnings()...)
 
 	// Generate Spec Schema
 	specSchema, warnings, err := g.BuildSpecSchema()
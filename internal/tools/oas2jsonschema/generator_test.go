@@ -7,6 +7,10 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	structuralschema "k8s.io/apiextensions-apiserver/pkg/apiserver/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
 func TestGenerateSpecSchema(t *testing.T) {
@@ -54,8 +58,8 @@ func TestGenerateSpecSchema(t *testing.T) {
 		if !strings.Contains(schemaStr, `"size"`) {
 			t.Error("Schema should contain 'size' property from request body")
 		}
-		if !strings.Contains(schemaStr, `"type": "integer"`) {
-			t.Error("Schema should have converted 'number' to 'integer'")
+		if !strings.Contains(schemaStr, `"type": "number"`) {
+			t.Error("Schema should have preserved the 'number' type")
 		}
 	})
 
@@ -724,8 +728,8 @@ func TestGenerateStatusSchema(t *testing.T) {
 		if !strings.Contains(schemaStr, `"id"`) || !strings.Contains(schemaStr, `"version"`) || !strings.Contains(schemaStr, `"last_updated"`) {
 			t.Error("Status schema should contain all specified fields")
 		}
-		if !strings.Contains(schemaStr, `"type": "integer"`) {
-			t.Error("Status schema should have converted 'number' to 'integer'")
+		if !strings.Contains(schemaStr, `"type": "number"`) {
+			t.Error("Status schema should have preserved the 'number' type")
 		}
 	})
 
@@ -934,7 +938,7 @@ func TestPrepareSchemaForCRD(t *testing.T) {
 			},
 		}
 
-		err := prepareSchemaForCRD(schema, DefaultGeneratorConfig())
+		_, err := prepareSchemaForCRD(schema, DefaultGeneratorConfig())
 
 		if err != nil {
 			t.Fatalf("Expected no error, but got: %v", err)
@@ -952,8 +956,8 @@ func TestPrepareSchemaForCRD(t *testing.T) {
 			}
 			if p.Name == "prop2" {
 				prop2Found = true
-				if p.Schema.Type[0] != "integer" {
-					t.Errorf("Expected prop2 to be of type 'integer', but got '%s'", p.Schema.Type[0])
+				if p.Schema.Type[0] != "number" {
+					t.Errorf("Expected prop2 to be of type 'number', but got '%s'", p.Schema.Type[0])
 				}
 			}
 		}
@@ -974,21 +978,46 @@ func TestPrepareSchemaForCRD(t *testing.T) {
 			},
 		}
 
-		err := prepareSchemaForCRD(schema, DefaultGeneratorConfig())
+		_, err := prepareSchemaForCRD(schema, DefaultGeneratorConfig())
 
 		if err != nil {
 			t.Fatalf("Expected no error, but got: %v", err)
 		}
 
-		if schema.Items.Properties[0].Schema.Type[0] != "integer" {
-			t.Errorf("Expected nestedProp to be of type 'integer', but got '%s'", schema.Items.Properties[0].Schema.Type[0])
+		if schema.Items.Properties[0].Schema.Type[0] != "number" {
+			t.Errorf("Expected nestedProp to be of type 'number', but got '%s'", schema.Items.Properties[0].Schema.Type[0])
+		}
+	})
+
+	t.Run("should convert number to integer when the legacy coercion is enabled", func(t *testing.T) {
+		schema := &Schema{
+			Type: []string{"object"},
+			Properties: []Property{
+				{Name: "price", Schema: &Schema{Type: []string{"number"}}},
+				{Name: "ratios", Schema: &Schema{Type: []string{"array"}, Items: &Schema{Type: []string{"number", "null"}}}},
+			},
+		}
+		config := DefaultGeneratorConfig()
+		config.CoerceNumberToInteger = true
+
+		_, err := prepareSchemaForCRD(schema, config)
+
+		if err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+
+		if schema.Properties[0].Schema.Type[0] != "integer" {
+			t.Errorf("Expected price to be of type 'integer', but got '%s'", schema.Properties[0].Schema.Type[0])
+		}
+		if schema.Properties[1].Schema.Items.Type[0] != "integer" {
+			t.Errorf("Expected ratios items to be of type 'integer', but got '%s'", schema.Properties[1].Schema.Items.Type[0])
 		}
 	})
 
 	t.Run("should handle empty schema", func(t *testing.T) {
 		schema := &Schema{}
 
-		err := prepareSchemaForCRD(schema, DefaultGeneratorConfig())
+		_, err := prepareSchemaForCRD(schema, DefaultGeneratorConfig())
 
 		if err != nil {
 			t.Fatalf("Expected no error, but got: %v", err)
@@ -996,7 +1025,7 @@ func TestPrepareSchemaForCRD(t *testing.T) {
 	})
 
 	t.Run("should handle nil schema", func(t *testing.T) {
-		err := prepareSchemaForCRD(nil, DefaultGeneratorConfig())
+		_, err := prepareSchemaForCRD(nil, DefaultGeneratorConfig())
 
 		if err != nil {
 			t.Fatalf("Expected no error, but got: %v", err)
@@ -1104,3 +1133,160 @@ func TestArrayDefaultSupport(t *testing.T) {
 		assert.Equal(t, "ReadOnlyMany", defaultArray[1])
 	})
 }
+
+func TestGenerate_DocumentWarnings(t *testing.T) {
+	conversionWarning := SchemaGenerationError{Path: "schemes", Code: CodeSwaggerConversionLossy, Message: "no schemes defined, https is assumed for the server URL"}
+	mockDoc := &mockOASDocument{
+		Paths: map[string]*mockPathItem{
+			"/widgets": {
+				Ops: map[string]Operation{
+					"post": &mockOperation{
+						RequestBody: RequestBodyInfo{
+							Content: map[string]*Schema{"application/json": {Type: []string{"object"}}},
+						},
+					},
+				},
+			},
+		},
+		warnings: []error{conversionWarning},
+	}
+	resourceConfig := &ResourceConfig{
+		Verbs: []Verb{{Action: "create", Path: "/widgets", Method: "post"}},
+	}
+
+	result, err := NewOASSchemaGenerator(mockDoc, DefaultGeneratorConfig(), resourceConfig).Generate()
+	require.NoError(t, err)
+	require.NotEmpty(t, result.GenerationWarnings)
+	assert.Equal(t, conversionWarning, result.GenerationWarnings[0])
+}
+
+func TestGenerate_PathLevelParameters(t *testing.T) {
+	doc, err := NewLibOASParser().Parse([]byte(pathLevelParametersSpec))
+	require.NoError(t, err)
+
+	resourceConfig := &ResourceConfig{
+		Verbs: []Verb{
+			{Action: "create", Path: "/repos/{owner}", Method: "post"},
+			{Action: "get", Path: "/repos/{owner}/{repo}", Method: "get"},
+			{Action: "update", Path: "/repos/{owner}/{repo}", Method: "patch"},
+			{Action: "delete", Path: "/repos/{owner}/{repo}", Method: "delete"},
+		},
+		ConfigurationFields: []ConfigurationField{
+			{
+				FromOpenAPI:        FromOpenAPI{Name: "X-Api-Version", In: "header"},
+				FromRestDefinition: FromRestDefinition{Actions: []string{"get", "update", "delete"}},
+			},
+		},
+	}
+
+	result, err := NewOASSchemaGenerator(doc, DefaultGeneratorConfig(), resourceConfig).Generate()
+	require.NoError(t, err)
+
+	var spec map[string]interface{}
+	require.NoError(t, json.Unmarshal(result.SpecSchema, &spec))
+	properties := spec["properties"].(map[string]interface{})
+	assert.Contains(t, properties, "owner")
+	assert.Contains(t, properties, "repo")
+	assert.Contains(t, spec["required"], "owner")
+	assert.Contains(t, spec["required"], "repo")
+	// Configuration fields are not part of the spec
+	assert.NotContains(t, properties, "X-Api-Version")
+
+	var configuration map[string]interface{}
+	require.NoError(t, json.Unmarshal(result.ConfigurationSchema, &configuration))
+	assert.Contains(t, string(result.ConfigurationSchema), "X-Api-Version")
+	assert.Contains(t, string(result.ConfigurationSchema), "2022-11-28")
+}
+
+// additionalPropertiesSpec describes a resource mixing typed maps, free-form objects and
+// objects that define both properties and additionalProperties.
+const additionalPropertiesSpec = `
+openapi: 3.0.3
+info:
+  title: Buckets
+  version: 1.0.0
+paths:
+  /buckets:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                name:
+                  type: string
+                labels:
+                  type: object
+                  additionalProperties:
+                    type: string
+                details:
+                  type: object
+                  properties:
+                    owner:
+                      type: string
+                  additionalProperties:
+                    type: string
+                settings:
+                  type: object
+                  properties:
+                    region:
+                      type: string
+                  additionalProperties: true
+      responses:
+        '201':
+          description: Created
+`
+
+func TestGenerate_AdditionalPropertiesStructural(t *testing.T) {
+	doc, err := NewLibOASParser().Parse([]byte(additionalPropertiesSpec))
+	require.NoError(t, err)
+
+	resourceConfig := &ResourceConfig{
+		Verbs: []Verb{{Action: "create", Path: "/buckets", Method: "post"}},
+	}
+
+	result, err := NewOASSchemaGenerator(doc, DefaultGeneratorConfig(), resourceConfig).Generate()
+	require.NoError(t, err)
+	assert.Contains(t, generationCodes(result.GenerationWarnings), CodeAdditionalPropertiesDropped)
+
+	var props apiextensionsv1.JSONSchemaProps
+	require.NoError(t, json.Unmarshal(result.SpecSchema, &props))
+	var internal apiextensions.JSONSchemaProps
+	require.NoError(t, apiextensionsv1.Convert_v1_JSONSchemaProps_To_apiextensions_JSONSchemaProps(&props, &internal, nil))
+
+	// The spec schema must be a valid structural schema
+	structural, err := structuralschema.NewStructural(&internal)
+	require.NoError(t, err)
+	assert.Empty(t, structuralschema.ValidateStructural(field.NewPath("spec"), structural).ToAggregate())
+
+	// Kubernetes rejects properties and additionalProperties in the same schema
+	var walk func(path string, s *structuralschema.Structural)
+	walk = func(path string, s *structuralschema.Structural) {
+		if s == nil {
+			return
+		}
+		if len(s.Properties) > 0 {
+			assert.Nil(t, s.AdditionalProperties, "%s has both properties and additionalProperties", path)
+		}
+		for name, prop := range s.Properties {
+			walk(path+"."+name, &prop)
+		}
+		walk(path+"[]", s.Items)
+		if s.AdditionalProperties != nil {
+			walk(path+"{}", s.AdditionalProperties.Structural)
+		}
+	}
+	walk("spec", structural)
+
+	labels := structural.Properties["labels"]
+	require.NotNil(t, labels.AdditionalProperties)
+	require.NotNil(t, labels.AdditionalProperties.Structural)
+	assert.Equal(t, "string", labels.AdditionalProperties.Structural.Type)
+
+	for name, known := range map[string]string{"details": "owner", "settings": "region"} {
+		prop := structural.Properties[name]
+		assert.True(t, prop.XPreserveUnknownFields, name)
+		assert.Contains(t, prop.Properties, known, name)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"slices"
 
 	"github.com/krateoplatformops/oasgen-provider/internal/tools/safety"
 )
@@ -21,6 +22,20 @@ func getPrimaryType(types []string) string {
 	return ""
 }
 
+// isNullOnlyType checks if a slice of types only allows null (e.g., ["null"]).
+func isNullOnlyType(types []string) bool {
+	return len(types) > 0 && getPrimaryType(types) == ""
+}
+
+// isNumericMismatch checks if two slices of types differ only because one primary type is "number" and the other is "integer".
+func isNumericMismatch(types1, types2 []string) bool {
+	primaryType1 := getPrimaryType(types1)
+	primaryType2 := getPrimaryType(types2)
+
+	return (primaryType1 == "number" && primaryType2 == "integer") ||
+		(primaryType1 == "integer" && primaryType2 == "number")
+}
+
 // areTypesCompatible checks if two slices of types are compatible based on their primary non-null type (OAS 3.1).
 // The opinionated compatibility rules are:
 // 1. If both have a primary type (e.g., "string", "object"), they must be identical.
@@ -37,19 +52,23 @@ func areTypesCompatible(types1, types2 []string) bool {
 
 // prepareSchemaForCRD prepares a schema for Kubernetes CRD generation by applying transformations.
 // Namely:
-// - it converts "number" types to "integer"
+// - it converts "number" types to "integer" (only if the legacy coercion is enabled in the config)
 // - it merges "allOf" schemas for object types.
+// - it resolves "oneOf" and "anyOf" schemas into a structural form (see composition.go).
+// - it adjusts the validation keywords not supported by Kubernetes as is (see validation_keywords.go).
+// - it drops the schema form of "additionalProperties" when "properties" are also defined (not allowed by Kubernetes).
 // It handles circular references by tracking visited schemas to prevent infinite recursion.
-func prepareSchemaForCRD(schema *Schema, config *GeneratorConfig) error {
+// It returns a list of warnings (non-fatal) describing the transformations that changed the meaning of the schema.
+func prepareSchemaForCRD(schema *Schema, config *GeneratorConfig) ([]error, error) {
 	if schema == nil {
-		return nil
+		return nil, nil
 	}
 
 	guard := safety.NewRecursionGuard(config.MaxRecursionDepth, config.MaxRecursionNodes, config.RecursionTimeout)
 	ctx, cancel := guard.WithContext()
 	defer cancel()
 
-	return prepareSchemaForCRDWithVisited(ctx, schema, guard, make(map[*Schema]*Schema), 0)
+	return prepareSchemaForCRDWithVisited(ctx, schema, config, guard, make(map[*Schema]*Schema), 0, ".")
 }
 
 // prepareSchemaForCRDWithVisited is the internal implementation that tracks visited schemas
@@ -57,19 +76,21 @@ func prepareSchemaForCRD(schema *Schema, config *GeneratorConfig) error {
 func prepareSchemaForCRDWithVisited(
 	ctx context.Context,
 	schema *Schema,
+	config *GeneratorConfig,
 	guard *safety.RecursionGuard,
 	visited map[*Schema]*Schema,
 	depth int,
-) error {
+	path string,
+) ([]error, error) {
 	if schema == nil {
-		return nil
+		return nil, nil
 	}
 
 	// Check recursion limits
 	if err := guard.Check(ctx, depth); err != nil {
 		//TODO: consider logging at debug level
 		log.Printf("CRD preparation recursion aborted at depth %d: %v", depth, err)
-		return fmt.Errorf("recursion limit exceeded: %w", err)
+		return nil, fmt.Errorf("recursion limit exceeded: %w", err)
 	}
 
 	// Detect already processed: if we've seen this schema before, skip processing
@@ -77,7 +98,7 @@ func prepareSchemaForCRDWithVisited(
 		//log.Printf("Already processed schema at depth %d: %v", depth, schema)
 		//log.Printf("Schema info: Type=%v, Description=%q, Properties=%d", schema.Type, schema.Description, len(schema.Properties))
 		//log.Printf("Skipping further processing.")
-		return nil // Already processed
+		return nil, nil // Already processed
 	}
 
 	// Mark this schema as being processed
@@ -91,15 +112,29 @@ func prepareSchemaForCRDWithVisited(
 		}
 	}()
 
-	// Convert number types to integer for CRD compatibility
-	if getPrimaryType(schema.Type) == "number" {
+	var warnings []error
+
+	// Convert number types to integer (legacy behaviour, opt-in)
+	// Kubernetes CRDs support the "number" type, so by default it is preserved.
+	if config.CoerceNumberToInteger && getPrimaryType(schema.Type) == "number" {
 		convertNumberToInteger(schema)
 	}
 
 	// Process array items
 	if getPrimaryType(schema.Type) == "array" && schema.Items != nil {
-		if err := prepareSchemaForCRDWithVisited(ctx, schema.Items, guard, visited, depth+1); err != nil {
-			return fmt.Errorf("failed to process array items: %w", err)
+		itemsWarnings, err := prepareSchemaForCRDWithVisited(ctx, schema.Items, config, guard, visited, depth+1, path)
+		warnings = append(warnings, itemsWarnings...)
+		if err != nil {
+			return warnings, fmt.Errorf("failed to process array items: %w", err)
+		}
+	}
+
+	// Process the values of typed maps
+	if schema.AdditionalPropertiesSchema != nil {
+		valuesWarnings, err := prepareSchemaForCRDWithVisited(ctx, schema.AdditionalPropertiesSchema, config, guard, visited, depth+1, path)
+		warnings = append(warnings, valuesWarnings...)
+		if err != nil {
+			return warnings, fmt.Errorf("failed to process additionalProperties schema: %w", err)
 		}
 	}
 
@@ -112,8 +147,10 @@ func prepareSchemaForCRDWithVisited(
 
 		for _, allOfSchema := range schema.AllOf {
 			// Recursively prepare each schema within the allOf list
-			if err := prepareSchemaForCRDWithVisited(ctx, allOfSchema, guard, visited, depth+1); err != nil {
-				return fmt.Errorf("failed to process allOf schema: %w", err)
+			allOfWarnings, err := prepareSchemaForCRDWithVisited(ctx, allOfSchema, config, guard, visited, depth+1, path)
+			warnings = append(warnings, allOfWarnings...)
+			if err != nil {
+				return warnings, fmt.Errorf("failed to process allOf schema: %w", err)
 			}
 
 			// Merge from the child schema
@@ -125,6 +162,13 @@ func prepareSchemaForCRDWithVisited(
 					// Example reference: SubnetType in ArubaCloud Subnet schema
 					mergedEnum = append(mergedEnum, allOfSchema.Enum...)
 				}
+				inheritValidationKeywords(schema, allOfSchema)
+
+				// Inherit the map values schema only if the main schema doesn't have one
+				if schema.AdditionalPropertiesSchema == nil && allOfSchema.AdditionalPropertiesSchema != nil {
+					schema.AdditionalPropertiesSchema = allOfSchema.AdditionalPropertiesSchema
+				}
+				schema.AdditionalProperties = schema.AdditionalProperties || allOfSchema.AdditionalProperties
 
 				// Inherit type only if the main schema doesn't have one
 				if len(schema.Type) == 0 && len(allOfSchema.Type) > 0 {
@@ -158,31 +202,29 @@ func prepareSchemaForCRDWithVisited(
 		}
 
 		// Handle 'required' fields with deduplication
-		// We need to avoid duplicates in the 'required' list like ["id", "name", "id"]
+		// We need to avoid duplicates in the 'required' list like ["id", "name", "id"].
+		// The order of first appearance is kept, so the generated CRD is the same on every run.
 		requiredSet := make(map[string]struct{})
-		for _, req := range schema.Required { // Add existing required fields from the main schema
-			requiredSet[req] = struct{}{}
-		}
-		for _, req := range mergedRequired { // Add merged required fields from allOf schemas
+		newRequired := make([]string, 0, len(schema.Required)+len(mergedRequired))
+		for _, req := range append(append([]string{}, schema.Required...), mergedRequired...) {
+			if _, ok := requiredSet[req]; ok {
+				continue
+			}
 			requiredSet[req] = struct{}{}
-		}
-		newRequired := make([]string, 0, len(requiredSet))
-		for req := range requiredSet {
 			newRequired = append(newRequired, req)
 		}
 		schema.Required = newRequired
 
 		// Handle 'enum' values with deduplication
-		// We need to avoid duplicates in the 'enum' list like ["Basic", "Advanced", "Basic"]
+		// We need to avoid duplicates in the 'enum' list like ["Basic", "Advanced", "Basic"].
+		// As for 'required', the order of first appearance is kept.
 		enumSet := make(map[interface{}]struct{})
-		for _, enumVal := range schema.Enum { // Existing enum values
-			enumSet[enumVal] = struct{}{}
-		}
-		for _, enumVal := range mergedEnum { // Merged enum values from allOf
+		newEnum := make([]interface{}, 0, len(schema.Enum)+len(mergedEnum))
+		for _, enumVal := range append(append([]interface{}{}, schema.Enum...), mergedEnum...) {
+			if _, ok := enumSet[enumVal]; ok {
+				continue
+			}
 			enumSet[enumVal] = struct{}{}
-		}
-		newEnum := make([]interface{}, 0, len(enumSet))
-		for enumVal := range enumSet {
 			newEnum = append(newEnum, enumVal)
 		}
 		schema.Enum = newEnum
@@ -191,17 +233,126 @@ func prepareSchemaForCRDWithVisited(
 		schema.AllOf = nil
 	}
 
+	// Process OneOf and AnyOf schemas.
+	// Variants are prepared first so that nested compositions are already resolved when they are combined.
+	for _, composition := range []struct {
+		keyword  string
+		variants *[]*Schema
+	}{
+		{keyword: "oneOf", variants: &schema.OneOf},
+		{keyword: "anyOf", variants: &schema.AnyOf},
+	} {
+		if len(*composition.variants) == 0 {
+			continue
+		}
+		for _, variant := range *composition.variants {
+			variantWarnings, err := prepareSchemaForCRDWithVisited(ctx, variant, config, guard, visited, depth+1, path)
+			warnings = append(warnings, variantWarnings...)
+			if err != nil {
+				return warnings, fmt.Errorf("failed to process %s schema: %w", composition.keyword, err)
+			}
+		}
+		warnings = append(warnings, resolveComposition(schema, composition.keyword, *composition.variants, path)...)
+		*composition.variants = nil
+	}
+
+	// Adjust validation keywords not supported by Kubernetes as is (see validation_keywords.go)
+	warnings = append(warnings, prepareValidationKeywords(schema, path)...)
+
+	// Kubernetes does not allow both "properties" and "additionalProperties" in the same schema.
+	// The known properties are kept and the unknown ones are preserved without validation (x-kubernetes-preserve-unknown-fields).
+	if len(schema.Properties) > 0 && (schema.AdditionalPropertiesSchema != nil || schema.AdditionalProperties) {
+		if schema.AdditionalPropertiesSchema != nil {
+			warnings = append(warnings, SchemaGenerationError{
+				Path:    path,
+				Code:    CodeAdditionalPropertiesDropped,
+				Message: "additionalProperties schema dropped since properties are also defined, additional properties are preserved without validation",
+			})
+		}
+		schema.AdditionalPropertiesSchema = nil
+		schema.AdditionalProperties = false
+		setExtension(schema, extensionPreserveUnknownFields, true)
+	}
+
 	// Process object properties recursively
 	for _, prop := range schema.Properties {
-		if err := prepareSchemaForCRDWithVisited(ctx, prop.Schema, guard, visited, depth+1); err != nil {
-			return fmt.Errorf("failed to process property '%s': %w", prop.Name, err)
+		propWarnings, err := prepareSchemaForCRDWithVisited(ctx, prop.Schema, config, guard, visited, depth+1, buildPath(path, prop.Name))
+		warnings = append(warnings, propWarnings...)
+		if err != nil {
+			return warnings, fmt.Errorf("failed to process property '%s': %w", prop.Name, err)
+		}
+	}
+
+	return warnings, nil
+}
+
+// removePropertiesWhere removes, at any depth, the properties whose schema matches the given predicate,
+// together with their entries in the required list.
+// Properties are also looked up in array items and in allOf/oneOf/anyOf schemas.
+// It returns a warning with the given code for each removed property.
+func removePropertiesWhere(schema *Schema, config *GeneratorConfig, path string, match func(*Schema) bool, code GenerationCode, message string) []error {
+	if schema == nil {
+		return nil
+	}
+
+	guard := safety.NewRecursionGuard(config.MaxRecursionDepth, config.MaxRecursionNodes, config.RecursionTimeout)
+	ctx, cancel := guard.WithContext()
+	defer cancel()
+
+	return removePropertiesWhereRec(ctx, schema, guard, make(map[*Schema]struct{}), 0, path, match, code, message)
+}
+
+// removePropertiesWhereRec is the recursive implementation of removePropertiesWhere.
+// It tracks visited schemas to handle circular references.
+func removePropertiesWhereRec(
+	ctx context.Context,
+	schema *Schema,
+	guard *safety.RecursionGuard,
+	visited map[*Schema]struct{},
+	depth int,
+	path string,
+	match func(*Schema) bool,
+	code GenerationCode,
+	message string,
+) []error {
+	if schema == nil || guard.Check(ctx, depth) != nil {
+		return nil
+	}
+	if _, ok := visited[schema]; ok {
+		return nil
+	}
+	visited[schema] = struct{}{}
+
+	var warnings []error
+
+	kept := schema.Properties[:0]
+	for _, prop := range schema.Properties {
+		propPath := buildPath(path, prop.Name)
+		if prop.Schema != nil && match(prop.Schema) {
+			schema.Required = slices.DeleteFunc(schema.Required, func(r string) bool { return r == prop.Name })
+			warnings = append(warnings, SchemaGenerationError{Path: propPath, Code: code, Message: message})
+			continue
+		}
+		warnings = append(warnings, removePropertiesWhereRec(ctx, prop.Schema, guard, visited, depth+1, propPath, match, code, message)...)
+		kept = append(kept, prop)
+	}
+	if schema.Properties != nil {
+		schema.Properties = kept
+	}
+
+	warnings = append(warnings, removePropertiesWhereRec(ctx, schema.Items, guard, visited, depth+1, path, match, code, message)...)
+	warnings = append(warnings, removePropertiesWhereRec(ctx, schema.AdditionalPropertiesSchema, guard, visited, depth+1, path, match, code, message)...)
+	for _, subSchemas := range [][]*Schema{schema.AllOf, schema.OneOf, schema.AnyOf} {
+		for _, sub := range subSchemas {
+			warnings = append(warnings, removePropertiesWhereRec(ctx, sub, guard, visited, depth+1, path, match, code, message)...)
 		}
 	}
 
-	return nil
+	return warnings
 }
 
-// convertNumberToInteger converts "number" types to "integer" types for K8s CRD compatibility.
+// convertNumberToInteger converts "number" types to "integer" types.
+// Kept for the legacy coercion mode (see GeneratorConfig.CoerceNumberToInteger).
 func convertNumberToInteger(schema *Schema) {
 	if schema == nil {
 		return
@@ -213,6 +364,16 @@ func convertNumberToInteger(schema *Schema) {
 	}
 }
 
+// appendFormatToDescription appends the format information to the description string.
+// If the format is empty, it returns the original description unchanged.
+// This is a simple utility to enhance schema descriptions with format details.
+func appendFormatToDescription(description, format string) string {
+	if format == "" {
+		return description
+	}
+	return fmt.Sprintf("%s (format: %s)", description, format)
+}
+
 // schemaToMap converts our domain-specific Schema object into a map[string]interface{}
 // suitable for JSON marshalling.
 // It handles circular references to prevent stack overflow.
@@ -225,7 +386,7 @@ func schemaToMap(schema *Schema, config *GeneratorConfig) (map[string]interface{
 	ctx, cancel := guard.WithContext()
 	defer cancel()
 
-	return schemaToMapWithVisited(ctx, schema, guard, make(map[*Schema]map[string]interface{}), 0)
+	return schemaToMapWithVisited(ctx, schema, config, guard, make(map[*Schema]map[string]interface{}), 0)
 }
 
 // schemaToMapWithVisited is the internal implementation that tracks visited schemas
@@ -233,6 +394,7 @@ func schemaToMap(schema *Schema, config *GeneratorConfig) (map[string]interface{
 func schemaToMapWithVisited(
 	ctx context.Context,
 	schema *Schema,
+	config *GeneratorConfig,
 	guard *safety.RecursionGuard,
 	visited map[*Schema]map[string]interface{},
 	depth int,
@@ -273,19 +435,34 @@ func schemaToMapWithVisited(
 	}()
 
 	// Process type field
-	if len(schema.Type) > 0 {
-		if len(schema.Type) == 1 {
-			m["type"] = schema.Type[0]
+	// "null" is not a valid type in Kubernetes, therefore it is emitted as "nullable: true".
+	types := slices.DeleteFunc(slices.Clone(schema.Type), func(t string) bool { return t == "null" })
+	if len(types) > 0 {
+		if len(types) == 1 {
+			m["type"] = types[0]
 		} else {
-			m["type"] = schema.Type
+			m["type"] = types
 		}
 	}
+	if schema.Nullable || len(types) != len(schema.Type) {
+		m["nullable"] = true
+	}
 
 	// Process optional string fields
 	if schema.Description != "" {
 		m["description"] = schema.Description
 	}
 
+	// Process format
+	// Formats not supported by Kubernetes are added to the description as a note.
+	if schema.Format != "" {
+		if slices.Contains(config.SupportedFormats, schema.Format) {
+			m["format"] = schema.Format
+		} else {
+			m["description"] = appendFormatToDescription(schema.Description, schema.Format)
+		}
+	}
+
 	// Process required fields
 	if len(schema.Required) > 0 {
 		m["required"] = schema.Required
@@ -297,7 +474,16 @@ func schemaToMapWithVisited(
 	}
 
 	// Process additional properties
-	if schema.AdditionalProperties {
+	// The schema form (typed map) takes precedence over the boolean form.
+	if schema.AdditionalPropertiesSchema != nil {
+		valuesMap, err := schemaToMapWithVisited(ctx, schema.AdditionalPropertiesSchema, config, guard, visited, depth+1)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert additionalProperties schema: %w", err)
+		}
+		if valuesMap != nil {
+			m["additionalProperties"] = valuesMap
+		}
+	} else if schema.AdditionalProperties {
 		m["additionalProperties"] = true
 	}
 
@@ -306,11 +492,14 @@ func schemaToMapWithVisited(
 		m["maxProperties"] = schema.MaxProperties
 	}
 
+	// Process validation keywords
+	validationKeywordsToMap(schema, m)
+
 	// Process object properties
 	if len(schema.Properties) > 0 {
 		props := make(map[string]interface{})
 		for _, p := range schema.Properties {
-			propMap, err := schemaToMapWithVisited(ctx, p.Schema, guard, visited, depth+1)
+			propMap, err := schemaToMapWithVisited(ctx, p.Schema, config, guard, visited, depth+1)
 			if err != nil {
 				return nil, fmt.Errorf("failed to convert property '%s': %w", p.Name, err)
 			}
@@ -325,7 +514,7 @@ func schemaToMapWithVisited(
 
 	// Process array items
 	if schema.Items != nil {
-		itemsMap, err := schemaToMapWithVisited(ctx, schema.Items, guard, visited, depth+1)
+		itemsMap, err := schemaToMapWithVisited(ctx, schema.Items, config, guard, visited, depth+1)
 		if err != nil {
 			return nil, fmt.Errorf("failed to convert items schema: %w", err)
 		}
@@ -345,7 +534,7 @@ func schemaToMapWithVisited(
 		//log.Printf("[UNEXPTECTED] Schema info: Type=%v, Description=%q, Properties=%d", schema.Type, schema.Description, len(schema.Properties))
 		allOfList := make([]interface{}, 0, len(schema.AllOf))
 		for i, s := range schema.AllOf {
-			allOfMap, err := schemaToMapWithVisited(ctx, s, guard, visited, depth+1)
+			allOfMap, err := schemaToMapWithVisited(ctx, s, config, guard, visited, depth+1)
 			if err != nil {
 				return nil, fmt.Errorf("failed to convert allOf item %d: %w", i, err)
 			}
@@ -358,6 +547,34 @@ func schemaToMapWithVisited(
 		}
 	}
 
+	// Process OneOf and AnyOf
+	// As for AllOf, these are resolved during CRD preparation and should not remain at this point.
+	// Kept here for safety.
+	for _, composition := range []struct {
+		keyword  string
+		variants []*Schema
+	}{
+		{keyword: "oneOf", variants: schema.OneOf},
+		{keyword: "anyOf", variants: schema.AnyOf},
+	} {
+		if len(composition.variants) == 0 {
+			continue
+		}
+		variantList := make([]interface{}, 0, len(composition.variants))
+		for i, s := range composition.variants {
+			variantMap, err := schemaToMapWithVisited(ctx, s, config, guard, visited, depth+1)
+			if err != nil {
+				return nil, fmt.Errorf("failed to convert %s item %d: %w", composition.keyword, i, err)
+			}
+			if variantMap != nil {
+				variantList = append(variantList, variantMap)
+			}
+		}
+		if len(variantList) > 0 {
+			m[composition.keyword] = variantList
+		}
+	}
+
 	// Process enum values
 	if len(schema.Enum) > 0 {
 		m["enum"] = schema.Enum
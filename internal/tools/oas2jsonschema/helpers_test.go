@@ -1,6 +1,7 @@
 package oas2jsonschema
 
 import (
+	"encoding/json"
 	"strings"
 	"testing"
 
@@ -127,7 +128,7 @@ func TestPrepareSchemaForCRD_WithRecursionGuard(t *testing.T) {
 			}
 
 			// Act
-			err := prepareSchemaForCRD(root, defaultConfig)
+			_, err := prepareSchemaForCRD(root, defaultConfig)
 
 			// Assert
 			if tc.expectError {
@@ -171,7 +172,7 @@ func TestPrepareSchemaForCRDWithVisited_MergeAllOfProperties(t *testing.T) {
 		},
 	}
 
-	err := prepareSchemaForCRD(schema, DefaultGeneratorConfig())
+	_, err := prepareSchemaForCRD(schema, DefaultGeneratorConfig())
 	require.NoError(t, err, "prepareSchemaForCRD should not return an error")
 
 	// Verify that properties are merged correctly
@@ -195,6 +196,7 @@ func TestPrepareSchemaForCRDWithVisited_MergeAllOfProperties(t *testing.T) {
 	for _, req := range expectedRequired {
 		require.Contains(t, schema.Required, req, "Required fields should contain %s", req)
 	}
+	require.Equal(t, expectedRequired, schema.Required, "Required fields should keep the order of first appearance")
 }
 
 // Example reference: SubnetType in ArubaCloud Subnet schema
@@ -211,7 +213,7 @@ func TestPrepareSchemaForCRDWithVisited_MergeAllOfEnumOnlySchemas(t *testing.T)
 		},
 	}
 
-	err := prepareSchemaForCRD(schema, DefaultGeneratorConfig())
+	_, err := prepareSchemaForCRD(schema, DefaultGeneratorConfig())
 	require.NoError(t, err, "prepareSchemaForCRD should not return an error")
 
 	// Verify that enum values are merged correctly
@@ -226,4 +228,245 @@ func TestPrepareSchemaForCRDWithVisited_MergeAllOfEnumOnlySchemas(t *testing.T)
 	for _, expectedVal := range expectedEnum {
 		require.True(t, enumMap[expectedVal], "Enum values should contain %v", expectedVal)
 	}
+	require.Equal(t, expectedEnum, schema.Enum, "Enum values should keep the order of first appearance")
+}
+
+func TestPrepareSchemaForCRD_AdditionalProperties(t *testing.T) {
+	t.Run("map values are prepared", func(t *testing.T) {
+		schema := &Schema{
+			Type: []string{"object"},
+			AdditionalPropertiesSchema: &Schema{
+				Type: []string{"object"},
+				AllOf: []*Schema{
+					{Properties: []Property{{Name: "name", Schema: &Schema{Type: []string{"string"}}}}},
+				},
+			},
+		}
+
+		warnings, err := prepareSchemaForCRD(schema, DefaultGeneratorConfig())
+		require.NoError(t, err)
+		assert.Empty(t, warnings)
+		assert.Nil(t, schema.AdditionalPropertiesSchema.AllOf)
+		assert.Equal(t, []string{"name"}, propertyNames(schema.AdditionalPropertiesSchema))
+	})
+
+	t.Run("map values are inherited from allOf", func(t *testing.T) {
+		schema := &Schema{
+			Type: []string{"object"},
+			AllOf: []*Schema{
+				{AdditionalPropertiesSchema: &Schema{Type: []string{"string"}}},
+			},
+		}
+
+		_, err := prepareSchemaForCRD(schema, DefaultGeneratorConfig())
+		require.NoError(t, err)
+		assert.False(t, schema.AdditionalProperties)
+		require.NotNil(t, schema.AdditionalPropertiesSchema)
+		assert.Equal(t, []string{"string"}, schema.AdditionalPropertiesSchema.Type)
+	})
+
+	t.Run("map values are dropped when properties are also defined", func(t *testing.T) {
+		schema := &Schema{
+			Type: []string{"object"},
+			Properties: []Property{
+				{Name: "metadata", Schema: &Schema{
+					Type:                       []string{"object"},
+					Properties:                 []Property{{Name: "name", Schema: &Schema{Type: []string{"string"}}}},
+					AdditionalPropertiesSchema: &Schema{Type: []string{"string"}},
+				}},
+			},
+		}
+
+		warnings, err := prepareSchemaForCRD(schema, DefaultGeneratorConfig())
+		require.NoError(t, err)
+		assert.Equal(t, []GenerationCode{CodeAdditionalPropertiesDropped}, generationCodes(warnings))
+
+		var genErr SchemaGenerationError
+		require.ErrorAs(t, warnings[0], &genErr)
+		assert.Equal(t, "metadata", genErr.Path)
+
+		metadata := schema.Properties[0].Schema
+		assert.Nil(t, metadata.AdditionalPropertiesSchema)
+		assert.False(t, metadata.AdditionalProperties)
+		assert.Equal(t, true, metadata.Extensions[extensionPreserveUnknownFields])
+	})
+
+	t.Run("free-form additional properties are preserved when properties are also defined", func(t *testing.T) {
+		schema := &Schema{
+			Type:                 []string{"object"},
+			Properties:           []Property{{Name: "name", Schema: &Schema{Type: []string{"string"}}}},
+			AdditionalProperties: true,
+		}
+
+		warnings, err := prepareSchemaForCRD(schema, DefaultGeneratorConfig())
+		require.NoError(t, err)
+		assert.Empty(t, warnings)
+		assert.False(t, schema.AdditionalProperties)
+		assert.Equal(t, true, schema.Extensions[extensionPreserveUnknownFields])
+	})
+}
+
+func TestGenerateJsonSchema_AdditionalProperties(t *testing.T) {
+	testCases := []struct {
+		name     string
+		schema   *Schema
+		expected string
+	}{
+		{
+			name:     "boolean form",
+			schema:   &Schema{Type: []string{"object"}, AdditionalProperties: true},
+			expected: `{"type": "object", "additionalProperties": true}`,
+		},
+		{
+			name: "map of strings",
+			schema: &Schema{
+				Type:                       []string{"object"},
+				AdditionalPropertiesSchema: &Schema{Type: []string{"string"}, MaxLength: int64Ptr(63)},
+			},
+			expected: `{"type": "object", "additionalProperties": {"type": "string", "maxLength": 63}}`,
+		},
+		{
+			name: "map of objects",
+			schema: &Schema{
+				Type: []string{"object"},
+				AdditionalPropertiesSchema: &Schema{
+					Type:       []string{"object"},
+					Properties: []Property{{Name: "port", Schema: &Schema{Type: []string{"integer"}}}},
+					Required:   []string{"port"},
+				},
+			},
+			expected: `{
+				"type": "object",
+				"additionalProperties": {
+					"type": "object",
+					"properties": {"port": {"type": "integer"}},
+					"required": ["port"]
+				}
+			}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := GenerateJsonSchema(tc.schema, DefaultGeneratorConfig())
+			require.NoError(t, err)
+			assert.JSONEq(t, tc.expected, string(out))
+		})
+	}
+}
+
+func TestGenerateJsonSchema_Format(t *testing.T) {
+	testCases := []struct {
+		name                string
+		schema              *Schema
+		supportedFormats    []string
+		expectedFormat      interface{}
+		expectedDescription interface{}
+	}{
+		{
+			name:           "supported format is emitted as CRD format",
+			schema:         &Schema{Type: []string{"string"}, Format: "date-time"},
+			expectedFormat: "date-time",
+		},
+		{
+			name:                "supported format does not change the description",
+			schema:              &Schema{Type: []string{"integer"}, Description: "The size", Format: "int64"},
+			expectedFormat:      "int64",
+			expectedDescription: "The size",
+		},
+		{
+			name:                "unsupported format falls back to the description note",
+			schema:              &Schema{Type: []string{"number"}, Description: "The ratio", Format: "double"},
+			expectedDescription: "The ratio (format: double)",
+		},
+		{
+			name:                "format removed from the allowlist falls back to the description note",
+			schema:              &Schema{Type: []string{"string"}, Format: "email"},
+			supportedFormats:    []string{"uuid"},
+			expectedDescription: " (format: email)",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := DefaultGeneratorConfig()
+			if tc.supportedFormats != nil {
+				config.SupportedFormats = tc.supportedFormats
+			}
+
+			out, err := GenerateJsonSchema(tc.schema, config)
+			require.NoError(t, err)
+
+			var m map[string]interface{}
+			require.NoError(t, json.Unmarshal(out, &m))
+			assert.Equal(t, tc.expectedFormat, m["format"])
+			assert.Equal(t, tc.expectedDescription, m["description"])
+		})
+	}
+}
+
+func TestGenerateJsonSchema_Nullable(t *testing.T) {
+	testCases := []struct {
+		name             string
+		schema           *Schema
+		expectedType     interface{}
+		expectedNullable interface{}
+	}{
+		{
+			name:         "not nullable",
+			schema:       &Schema{Type: []string{"string"}},
+			expectedType: "string",
+		},
+		{
+			name:             "OAS 3.0 nullable",
+			schema:           &Schema{Type: []string{"string"}, Nullable: true},
+			expectedType:     "string",
+			expectedNullable: true,
+		},
+		{
+			name:             "OAS 3.1 null type",
+			schema:           &Schema{Type: []string{"integer", "null"}},
+			expectedType:     "integer",
+			expectedNullable: true,
+		},
+		{
+			name:             "null-only type",
+			schema:           &Schema{Type: []string{"null"}},
+			expectedNullable: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := GenerateJsonSchema(tc.schema, DefaultGeneratorConfig())
+			require.NoError(t, err)
+
+			var m map[string]interface{}
+			require.NoError(t, json.Unmarshal(out, &m))
+			assert.Equal(t, tc.expectedType, m["type"])
+			assert.Equal(t, tc.expectedNullable, m["nullable"])
+		})
+	}
+
+	t.Run("the schema is not modified", func(t *testing.T) {
+		schema := &Schema{Type: []string{"string", "null"}}
+		_, err := GenerateJsonSchema(schema, DefaultGeneratorConfig())
+		require.NoError(t, err)
+		assert.Equal(t, []string{"string", "null"}, schema.Type)
+	})
+}
+
+func TestRemovePropertiesWhere_CircularReference(t *testing.T) {
+	node := &Schema{
+		Type: []string{"object"},
+		Properties: []Property{
+			{Name: "id", Schema: &Schema{Type: []string{"string"}, ReadOnly: true}},
+		},
+	}
+	node.Properties = append(node.Properties, Property{Name: "children", Schema: &Schema{Type: []string{"array"}, Items: node}})
+
+	warnings := removePropertiesWhere(node, DefaultGeneratorConfig(), ".", func(s *Schema) bool { return s.ReadOnly }, CodeReadOnlyFieldRemoved, "removed")
+
+	assert.Len(t, warnings, 1)
+	assert.Equal(t, []string{"children"}, propertyNames(node))
 }
@@ -3,12 +3,14 @@ package oas2jsonschema
 // Parser defines the interface for parsing an OpenAPI specification.
 type Parser interface {
 	Parse(content []byte) (OASDocument, error)
+	ParseBundle(bundle *Bundle) (OASDocument, error) // Multi-file documents, with relative $refs between the files.
 }
 
 // OASDocument defines the contract for accessing an OpenAPI specification.
 type OASDocument interface {
 	FindPath(path string) (PathItem, bool)
 	SecuritySchemes() []SecuritySchemeInfo
+	Warnings() []error // Non-fatal issues found while parsing (e.g., lossy Swagger 2.0 conversion steps).
 }
 
 // PathItem defines the contract for a single API path.
@@ -8,6 +8,7 @@ import (
 	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
 	"github.com/pb33f/libopenapi/orderedmap"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestSchemaConversion(t *testing.T) {
@@ -109,6 +110,184 @@ func TestSchemaConversion(t *testing.T) {
 				assert.Len(t, reconvertedLibSchema.AllOf, 2)
 			},
 		},
+		{
+			name: "OneOf and AnyOf Composition",
+			originalLibSchema: func() *base.Schema {
+				cat := &base.Schema{Type: []string{"object"}, Properties: orderedmap.New[string, *base.SchemaProxy]()}
+				cat.Properties.Set("meow", base.CreateSchemaProxy(&base.Schema{Type: []string{"boolean"}}))
+				dog := &base.Schema{Type: []string{"object"}, Properties: orderedmap.New[string, *base.SchemaProxy]()}
+				dog.Properties.Set("bark", base.CreateSchemaProxy(&base.Schema{Type: []string{"boolean"}}))
+				return &base.Schema{
+					OneOf: []*base.SchemaProxy{base.CreateSchemaProxy(cat), base.CreateSchemaProxy(dog)},
+					AnyOf: []*base.SchemaProxy{
+						base.CreateSchemaProxy(&base.Schema{Type: []string{"string"}}),
+						base.CreateSchemaProxy(&base.Schema{Type: []string{"integer"}}),
+					},
+				}
+			}(),
+			assertDomain: func(t *testing.T, domainSchema *Schema) {
+				assert.Len(t, domainSchema.OneOf, 2)
+				assert.Equal(t, "meow", domainSchema.OneOf[0].Properties[0].Name)
+				assert.Equal(t, "bark", domainSchema.OneOf[1].Properties[0].Name)
+				assert.Len(t, domainSchema.AnyOf, 2)
+				assert.Equal(t, []string{"string"}, domainSchema.AnyOf[0].Type)
+				assert.Equal(t, []string{"integer"}, domainSchema.AnyOf[1].Type)
+			},
+			assertReconverted: func(t *testing.T, reconvertedLibSchema *base.Schema) {
+				assert.Len(t, reconvertedLibSchema.OneOf, 2)
+				assert.Len(t, reconvertedLibSchema.AnyOf, 2)
+			},
+		},
+		{
+			name: "Validation Keywords",
+			originalLibSchema: func() *base.Schema {
+				minLength, maxLength := int64(1), int64(63)
+				minimum, maximum, multipleOf := float64(0), float64(100), float64(0.5)
+				minItems, maxItems := int64(1), int64(10)
+				uniqueItems := true
+				return &base.Schema{
+					Type:             []string{"number"},
+					MinLength:        &minLength,
+					MaxLength:        &maxLength,
+					Pattern:          "^[a-z]+$",
+					Minimum:          &minimum,
+					Maximum:          &maximum,
+					ExclusiveMinimum: &base.DynamicValue[bool, float64]{A: true},
+					MultipleOf:       &multipleOf,
+					MinItems:         &minItems,
+					MaxItems:         &maxItems,
+					UniqueItems:      &uniqueItems,
+				}
+			}(),
+			assertDomain: func(t *testing.T, domainSchema *Schema) {
+				assert.Equal(t, int64(1), *domainSchema.MinLength)
+				assert.Equal(t, int64(63), *domainSchema.MaxLength)
+				assert.Equal(t, "^[a-z]+$", domainSchema.Pattern)
+				assert.Equal(t, float64(0), *domainSchema.Minimum)
+				assert.Equal(t, float64(100), *domainSchema.Maximum)
+				assert.True(t, domainSchema.ExclusiveMinimum)
+				assert.False(t, domainSchema.ExclusiveMaximum)
+				assert.Equal(t, 0.5, *domainSchema.MultipleOf)
+				assert.Equal(t, int64(1), *domainSchema.MinItems)
+				assert.Equal(t, int64(10), *domainSchema.MaxItems)
+				assert.True(t, domainSchema.UniqueItems)
+			},
+			assertReconverted: func(t *testing.T, reconvertedLibSchema *base.Schema) {
+				assert.Equal(t, int64(63), *reconvertedLibSchema.MaxLength)
+				assert.Equal(t, "^[a-z]+$", reconvertedLibSchema.Pattern)
+				assert.True(t, reconvertedLibSchema.ExclusiveMinimum.A)
+				assert.Nil(t, reconvertedLibSchema.ExclusiveMaximum)
+				assert.True(t, *reconvertedLibSchema.UniqueItems)
+			},
+		},
+		{
+			name: "OAS 3.1 Numeric Exclusive Bounds",
+			originalLibSchema: &base.Schema{
+				Type:             []string{"integer"},
+				ExclusiveMinimum: &base.DynamicValue[bool, float64]{N: 1, B: 0},
+				ExclusiveMaximum: &base.DynamicValue[bool, float64]{N: 1, B: 65536},
+			},
+			assertDomain: func(t *testing.T, domainSchema *Schema) {
+				require.NotNil(t, domainSchema.Minimum)
+				require.NotNil(t, domainSchema.Maximum)
+				assert.Equal(t, float64(0), *domainSchema.Minimum)
+				assert.Equal(t, float64(65536), *domainSchema.Maximum)
+				assert.True(t, domainSchema.ExclusiveMinimum)
+				assert.True(t, domainSchema.ExclusiveMaximum)
+			},
+			assertReconverted: func(t *testing.T, reconvertedLibSchema *base.Schema) {
+				assert.Equal(t, float64(65536), *reconvertedLibSchema.Maximum)
+				assert.True(t, reconvertedLibSchema.ExclusiveMaximum.A)
+			},
+		},
+		{
+			name: "ReadOnly and WriteOnly",
+			originalLibSchema: func() *base.Schema {
+				readOnly, writeOnly := true, true
+				schema := &base.Schema{Type: []string{"object"}, Properties: orderedmap.New[string, *base.SchemaProxy]()}
+				schema.Properties.Set("id", base.CreateSchemaProxy(&base.Schema{Type: []string{"string"}, ReadOnly: &readOnly}))
+				schema.Properties.Set("password", base.CreateSchemaProxy(&base.Schema{Type: []string{"string"}, WriteOnly: &writeOnly}))
+				return schema
+			}(),
+			assertDomain: func(t *testing.T, domainSchema *Schema) {
+				assert.True(t, domainSchema.Properties[0].Schema.ReadOnly)
+				assert.False(t, domainSchema.Properties[0].Schema.WriteOnly)
+				assert.False(t, domainSchema.Properties[1].Schema.ReadOnly)
+				assert.True(t, domainSchema.Properties[1].Schema.WriteOnly)
+			},
+			assertReconverted: func(t *testing.T, reconvertedLibSchema *base.Schema) {
+				idProp, _ := reconvertedLibSchema.Properties.Get("id")
+				idSchema, _ := idProp.BuildSchema()
+				assert.True(t, *idSchema.ReadOnly)
+				passwordProp, _ := reconvertedLibSchema.Properties.Get("password")
+				passwordSchema, _ := passwordProp.BuildSchema()
+				assert.True(t, *passwordSchema.WriteOnly)
+			},
+		},
+		{
+			name: "Typed Map (AdditionalProperties schema)",
+			originalLibSchema: func() *base.Schema {
+				schema := &base.Schema{Type: []string{"object"}, Properties: orderedmap.New[string, *base.SchemaProxy]()}
+				schema.Properties.Set("labels", base.CreateSchemaProxy(&base.Schema{
+					Type: []string{"object"},
+					AdditionalProperties: &base.DynamicValue[*base.SchemaProxy, bool]{
+						A: base.CreateSchemaProxy(&base.Schema{Type: []string{"string"}}),
+					},
+				}))
+				schema.Properties.Set("free", base.CreateSchemaProxy(&base.Schema{
+					Type:                 []string{"object"},
+					AdditionalProperties: &base.DynamicValue[*base.SchemaProxy, bool]{N: 1, B: true},
+				}))
+				return schema
+			}(),
+			assertDomain: func(t *testing.T, domainSchema *Schema) {
+				labels := domainSchema.Properties[0].Schema
+				assert.False(t, labels.AdditionalProperties)
+				require.NotNil(t, labels.AdditionalPropertiesSchema)
+				assert.Equal(t, []string{"string"}, labels.AdditionalPropertiesSchema.Type)
+
+				free := domainSchema.Properties[1].Schema
+				assert.True(t, free.AdditionalProperties)
+				assert.Nil(t, free.AdditionalPropertiesSchema)
+			},
+			assertReconverted: func(t *testing.T, reconvertedLibSchema *base.Schema) {
+				labelsProp, _ := reconvertedLibSchema.Properties.Get("labels")
+				labelsSchema, _ := labelsProp.BuildSchema()
+				require.True(t, labelsSchema.AdditionalProperties.IsA())
+				valuesSchema, _ := labelsSchema.AdditionalProperties.A.BuildSchema()
+				assert.Equal(t, []string{"string"}, valuesSchema.Type)
+
+				freeProp, _ := reconvertedLibSchema.Properties.Get("free")
+				freeSchema, _ := freeProp.BuildSchema()
+				require.True(t, freeSchema.AdditionalProperties.IsB())
+				assert.True(t, freeSchema.AdditionalProperties.B)
+			},
+		},
+		{
+			name: "Nullable",
+			originalLibSchema: func() *base.Schema {
+				nullable := true
+				schema := &base.Schema{Type: []string{"object"}, Properties: orderedmap.New[string, *base.SchemaProxy]()}
+				schema.Properties.Set("name", base.CreateSchemaProxy(&base.Schema{Type: []string{"string"}, Nullable: &nullable}))
+				schema.Properties.Set("age", base.CreateSchemaProxy(&base.Schema{Type: []string{"integer", "null"}}))
+				return schema
+			}(),
+			assertDomain: func(t *testing.T, domainSchema *Schema) {
+				assert.True(t, domainSchema.Properties[0].Schema.Nullable)
+				assert.False(t, domainSchema.Properties[1].Schema.Nullable)
+				assert.Equal(t, []string{"integer", "null"}, domainSchema.Properties[1].Schema.Type)
+			},
+			assertReconverted: func(t *testing.T, reconvertedLibSchema *base.Schema) {
+				nameProp, _ := reconvertedLibSchema.Properties.Get("name")
+				nameSchema, _ := nameProp.BuildSchema()
+				require.NotNil(t, nameSchema.Nullable)
+				assert.True(t, *nameSchema.Nullable)
+				ageProp, _ := reconvertedLibSchema.Properties.Get("age")
+				ageSchema, _ := ageProp.BuildSchema()
+				assert.Nil(t, ageSchema.Nullable)
+				assert.Equal(t, []string{"integer", "null"}, ageSchema.Type)
+			},
+		},
 		{
 			name:              "Nil Schema",
 			originalLibSchema: nil,
@@ -390,6 +569,145 @@ func TestLibOASOperationAdapter(t *testing.T) {
 	})
 }
 
+// pathLevelParametersSpec describes a repository resource with parameters shared by all the operations
+// of a path, as done in many real world specs (e.g., GitHub).
+const pathLevelParametersSpec = `
+openapi: 3.0.3
+info:
+  title: Repositories
+  version: 1.0.0
+paths:
+  /repos/{owner}:
+    parameters:
+      - $ref: '#/components/parameters/owner'
+      - $ref: '#/components/parameters/apiVersion'
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                name:
+                  type: string
+      responses:
+        '201':
+          description: Created
+  /repos/{owner}/{repo}:
+    parameters:
+      - $ref: '#/components/parameters/owner'
+      - name: repo
+        in: path
+        required: true
+        description: The name of the repository.
+        schema:
+          type: string
+      - $ref: '#/components/parameters/apiVersion'
+    get:
+      parameters:
+        - name: include
+          in: query
+          schema:
+            type: string
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  name:
+                    type: string
+    patch:
+      parameters:
+        - name: repo
+          in: path
+          required: true
+          description: The name of the repository, case insensitive.
+          schema:
+            type: string
+            maxLength: 100
+        - name: repo
+          in: query
+          schema:
+            type: boolean
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                description:
+                  type: string
+      responses:
+        '200':
+          description: OK
+    delete:
+      responses:
+        '204':
+          description: No Content
+components:
+  parameters:
+    owner:
+      name: owner
+      in: path
+      required: true
+      schema:
+        type: string
+    apiVersion:
+      name: X-Api-Version
+      in: header
+      schema:
+        type: string
+        enum: ["2022-11-28"]
+`
+
+func TestParse_PathLevelParameters(t *testing.T) {
+	doc, err := NewLibOASParser().Parse([]byte(pathLevelParametersSpec))
+	require.NoError(t, err)
+
+	path, ok := doc.FindPath("/repos/{owner}/{repo}")
+	require.True(t, ok)
+	ops := path.GetOperations()
+	require.Len(t, ops, 3)
+
+	paramKeys := func(params []ParameterInfo) []string {
+		var keys []string
+		for _, p := range params {
+			keys = append(keys, p.In+":"+p.Name)
+		}
+		return keys
+	}
+
+	t.Run("path-level parameters are inherited", func(t *testing.T) {
+		params := ops["delete"].GetParameters()
+		assert.Equal(t, []string{"path:owner", "path:repo", "header:X-Api-Version"}, paramKeys(params))
+		assert.True(t, params[0].Required)
+		assert.Equal(t, "The name of the repository.", params[1].Description)
+		assert.Equal(t, []interface{}{"2022-11-28"}, params[2].Schema.Enum)
+	})
+
+	t.Run("operation-level parameters are appended", func(t *testing.T) {
+		params := ops["get"].GetParameters()
+		assert.Equal(t, []string{"path:owner", "path:repo", "header:X-Api-Version", "query:include"}, paramKeys(params))
+	})
+
+	t.Run("operation-level parameters override path-level ones by name and location", func(t *testing.T) {
+		params := ops["patch"].GetParameters()
+		assert.Equal(t, []string{"path:owner", "path:repo", "header:X-Api-Version", "query:repo"}, paramKeys(params))
+		assert.Equal(t, "The name of the repository, case insensitive.", params[1].Description)
+		assert.Equal(t, int64(100), *params[1].Schema.MaxLength)
+		assert.Equal(t, []string{"boolean"}, params[3].Schema.Type)
+	})
+
+	t.Run("the other paths are not affected", func(t *testing.T) {
+		path, ok := doc.FindPath("/repos/{owner}")
+		require.True(t, ok)
+		assert.Equal(t, []string{"path:owner", "header:X-Api-Version"}, paramKeys(path.GetOperations()["post"].GetParameters()))
+	})
+}
+
 func TestAdapterEdgeCases(t *testing.T) {
 	t.Run("SecuritySchemes returns nil if components are nil", func(t *testing.T) {
 		docModel := &v3.Document{Components: nil}
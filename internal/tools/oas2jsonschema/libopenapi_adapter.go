@@ -5,12 +5,15 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"path"
+	"slices"
 	"strconv"
 	"time"
 
 	"github.com/krateoplatformops/oasgen-provider/internal/tools/safety"
 	"github.com/pb33f/libopenapi"
 
+	"github.com/pb33f/libopenapi/datamodel"
 	"github.com/pb33f/libopenapi/datamodel/high/base"
 	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
 	"github.com/pb33f/libopenapi/orderedmap"
@@ -27,9 +30,42 @@ func NewLibOASParser() Parser {
 
 // Parse takes raw OpenAPI specification content and returns a document
 // that conforms to the OASDocument interface.
+// Swagger 2.0 documents are converted to OAS 3.0 (see swagger2.go), the lossy conversion steps
+// are reported as warnings of the document.
 func (p *libOASParser) Parse(content []byte) (OASDocument, error) {
+	return p.parse(content, nil)
+}
+
+// ParseBundle parses the entry document of a bundle, resolving the relative $refs
+// to the other files of the bundle. Files outside the bundle are never read.
+func (p *libOASParser) ParseBundle(bundle *Bundle) (OASDocument, error) {
+	content, ok := bundle.Files[bundle.Entry]
+	if !ok {
+		return nil, ParserError{
+			Code:    CodeExternalReferenceError,
+			Message: fmt.Sprintf("entry document '%s' not found in the bundle", bundle.Entry),
+		}
+	}
+
+	bundleFS, err := newBundleFS(bundle)
+	if err != nil {
+		return nil, ParserError{
+			Code:    CodeExternalReferenceError,
+			Message: "failed to load the files of the bundle",
+			Err:     err,
+		}
+	}
 
-	d, err := libopenapi.NewDocument(content)
+	config := datamodel.NewDocumentConfiguration()
+	config.BasePath = path.Join(bundleRoot, path.Dir(bundle.Entry))
+	config.AllowFileReferences = true
+	config.LocalFS = bundleFS
+
+	return p.parse(content, config)
+}
+
+func (p *libOASParser) parse(content []byte, config *datamodel.DocumentConfiguration) (OASDocument, error) {
+	d, err := libopenapi.NewDocumentWithConfiguration(content, config)
 	if err != nil {
 		return nil, ParserError{
 			Code:    CodeDocumentCreationError,
@@ -38,6 +74,28 @@ func (p *libOASParser) Parse(content []byte) (OASDocument, error) {
 		}
 	}
 
+	var warnings []error
+	if info := d.GetSpecInfo(); info != nil && info.SpecFormat == datamodel.OAS2 {
+		converted, conversionWarnings, err := convertSwagger2(content)
+		if err != nil {
+			return nil, ParserError{
+				Code:    CodeSwaggerConversionError,
+				Message: "failed to convert Swagger 2.0 document to OAS 3.0",
+				Err:     err,
+			}
+		}
+		warnings = conversionWarnings
+
+		d, err = libopenapi.NewDocumentWithConfiguration(converted, config)
+		if err != nil {
+			return nil, ParserError{
+				Code:    CodeDocumentCreationError,
+				Message: "failed to create new libopenapi document from the converted Swagger 2.0 document",
+				Err:     err,
+			}
+		}
+	}
+
 	doc, modelErrors := d.BuildV3Model()
 	if len(modelErrors) > 0 {
 		return nil, ParserError{
@@ -69,13 +127,14 @@ func (p *libOASParser) Parse(content []byte) (OASDocument, error) {
 		}
 	}
 
-	return NewLibOASDocumentAdapter(doc), nil
+	return &libOASDocumentAdapter{doc: doc, warnings: warnings}, nil
 }
 
 // --- Adapter Implementation ---
 
 type libOASDocumentAdapter struct {
-	doc *libopenapi.DocumentModel[v3.Document]
+	doc      *libopenapi.DocumentModel[v3.Document]
+	warnings []error
 }
 
 // We implement the OASDocument interface for the libopenapi DocumentModel
@@ -91,6 +150,10 @@ func (a *libOASDocumentAdapter) FindPath(path string) (PathItem, bool) {
 	return &libOASPathItemAdapter{path: p}, true
 }
 
+func (a *libOASDocumentAdapter) Warnings() []error {
+	return a.warnings
+}
+
 func (a *libOASDocumentAdapter) SecuritySchemes() []SecuritySchemeInfo {
 	if a.doc.Model.Components == nil || a.doc.Model.Components.SecuritySchemes == nil {
 		return nil
@@ -117,25 +180,47 @@ func (a *libOASPathItemAdapter) GetOperations() map[string]Operation {
 	ops := make(map[string]Operation)
 	rawOps := a.path.GetOperations()
 	for pair := rawOps.First(); pair != nil; pair = pair.Next() {
-		ops[pair.Key()] = &libOASOperationAdapter{op: pair.Value()}
+		ops[pair.Key()] = &libOASOperationAdapter{op: pair.Value(), pathParameters: a.path.Parameters}
 	}
 	return ops
 }
 
 type libOASOperationAdapter struct {
-	op *v3.Operation
+	op             *v3.Operation
+	pathParameters []*v3.Parameter // Parameters defined at path level, shared by all the operations of the path
+}
+
+// parameterKey uniquely identifies a parameter, as per the OpenAPI specification.
+type parameterKey struct {
+	name string
+	in   string
 }
 
+// GetParameters returns the parameters of the operation, including the ones defined at path level.
+// As per the OpenAPI specification, an operation-level parameter overrides the path-level one
+// with the same name and location (it takes its position in the list).
 func (a *libOASOperationAdapter) GetParameters() []ParameterInfo {
 	var params []ParameterInfo
-	for _, p := range a.op.Parameters {
-		params = append(params, ParameterInfo{
+	index := make(map[parameterKey]int)
+	for _, p := range slices.Concat(a.pathParameters, a.op.Parameters) {
+		if p == nil {
+			continue
+		}
+		param := ParameterInfo{
 			Name:        p.Name,
 			In:          p.In,
 			Description: p.Description,
 			Required:    p.Required != nil && *p.Required,
 			Schema:      convertLibopenapiSchema(p.Schema),
-		})
+		}
+
+		key := parameterKey{name: p.Name, in: p.In}
+		if i, ok := index[key]; ok {
+			params[i] = param
+			continue
+		}
+		index[key] = len(params)
+		params = append(params, param)
 	}
 	return params
 }
@@ -186,6 +271,20 @@ func convertLibopenapiSchema(proxy *base.SchemaProxy) *Schema {
 	return convertLibopenapiSchemaWithVisited(ctx, proxy, guard, make(map[string]*Schema), 0)
 }
 
+// referenceKey returns the key of a referenced schema in the 'visited' map.
+// References are qualified with the file they appear in, since in a bundle the same reference
+// (e.g., "#/Repo" or "./repo.yaml#/Repo") can point to different schemas depending on the file.
+func referenceKey(proxy *base.SchemaProxy) string {
+	ref := proxy.GetReference()
+	if ref == "" {
+		return ref
+	}
+	if origin := proxy.GetReferenceOrigin(); origin != nil && origin.AbsoluteLocation != "" {
+		return origin.AbsoluteLocation + ref
+	}
+	return ref
+}
+
 // convertLibopenapiSchemaWithVisited is the recursive implementation of the schema conversion.
 // It uses the 'visited' map to detect and handle circular references.
 func convertLibopenapiSchemaWithVisited(
@@ -212,7 +311,7 @@ func convertLibopenapiSchemaWithVisited(
 	// If the schema is a reference, we use its $ref value as the key.
 	// If it's not a reference, we use its memory address to uniquely identify it.
 	if proxy.IsReference() {
-		key := proxy.GetReference()
+		key := referenceKey(proxy)
 		if key == "" {
 			key = fmt.Sprintf("%p", proxy)
 		}
@@ -237,7 +336,7 @@ func convertLibopenapiSchemaWithVisited(
 	domainSchema := &Schema{}
 
 	if proxy.IsReference() {
-		ref := proxy.GetReference()
+		ref := referenceKey(proxy)
 		if ref != "" {
 			visited[ref] = domainSchema
 		}
@@ -297,9 +396,8 @@ func convertLibopenapiSchemaWithVisited(
 			// Boolean form: allows or disallows any additional properties
 			domainSchema.AdditionalProperties = s.AdditionalProperties.B
 		case s.AdditionalProperties.IsA():
-			// Schema form: recurse to handle nested schemas properly
-			// Currently not handled in `oasgen-provider`, ignored
-			//log.Print("Warning: Schema form of AdditionalProperties is not currently handled")
+			// Schema form: the values of a map (e.g., map[string]string), recurse to convert the value schema
+			domainSchema.AdditionalPropertiesSchema = convertLibopenapiSchemaWithVisited(ctx, s.AdditionalProperties.A, guard, visited, depth+1)
 		default:
 			//log.Print("Warning: Unknown AdditionalProperties type")
 		}
@@ -311,13 +409,55 @@ func convertLibopenapiSchemaWithVisited(
 		domainSchema.MaxProperties = int(*s.MaxProperties)
 	}
 
-	// Format handling
-	// If a format is specified, append it to the description for additional context.
-	// There is no format validation
-	if s.Format != "" {
-		domainSchema.Description = appendFormatToDescription(domainSchema.Description, s.Format)
+	// Validation keywords handling
+	// These are copied as is, Kubernetes-specific adjustments are done later in the pipeline (in helpers.go).
+	domainSchema.MinLength = s.MinLength
+	domainSchema.MaxLength = s.MaxLength
+	domainSchema.Pattern = s.Pattern
+	domainSchema.Minimum = s.Minimum
+	domainSchema.Maximum = s.Maximum
+	domainSchema.MultipleOf = s.MultipleOf
+	domainSchema.MinItems = s.MinItems
+	domainSchema.MaxItems = s.MaxItems
+	domainSchema.UniqueItems = s.UniqueItems != nil && *s.UniqueItems
+
+	// ExclusiveMinimum and ExclusiveMaximum are booleans in OAS 3.0 (modifiers of minimum and maximum)
+	// and numbers in OAS 3.1 (the bound itself).
+	// The OAS 3.1 form is converted to the OAS 3.0 one, which is the one supported by Kubernetes.
+	if s.ExclusiveMinimum != nil {
+		switch {
+		case s.ExclusiveMinimum.IsA():
+			domainSchema.ExclusiveMinimum = s.ExclusiveMinimum.A
+		case s.ExclusiveMinimum.IsB():
+			bound := s.ExclusiveMinimum.B
+			domainSchema.Minimum = &bound
+			domainSchema.ExclusiveMinimum = true
+		}
+	}
+	if s.ExclusiveMaximum != nil {
+		switch {
+		case s.ExclusiveMaximum.IsA():
+			domainSchema.ExclusiveMaximum = s.ExclusiveMaximum.A
+		case s.ExclusiveMaximum.IsB():
+			bound := s.ExclusiveMaximum.B
+			domainSchema.Maximum = &bound
+			domainSchema.ExclusiveMaximum = true
+		}
 	}
 
+	// Nullable handling (OAS 3.0)
+	// In OAS 3.1 "nullable" was removed in favor of a "null" entry in the "type" array, which is kept in Type.
+	domainSchema.Nullable = s.Nullable != nil && *s.Nullable
+
+	// ReadOnly and WriteOnly handling
+	domainSchema.ReadOnly = s.ReadOnly != nil && *s.ReadOnly
+	domainSchema.WriteOnly = s.WriteOnly != nil && *s.WriteOnly
+
+	// Format handling
+	// Whether the format is emitted as a CRD format or as a note in the description
+	// depends on the generator configuration, therefore it is decided later in the pipeline (in helpers.go).
+	domainSchema.Format = s.Format
+
 	// Properties handling
 	if s.Properties != nil {
 		domainSchema.Properties = make([]Property, 0, s.Properties.Len())
@@ -356,17 +496,23 @@ func convertLibopenapiSchemaWithVisited(
 		}
 	}
 
-	return domainSchema
-}
-
-// appendFormatToDescription appends the format information to the description string.
-// If the format is empty, it returns the original description unchanged.
-// This is a simple utility to enhance schema descriptions with format details.
-func appendFormatToDescription(description, format string) string {
-	if format == "" {
-		return description
+	// OneOf / AnyOf handling
+	// As for allOf, only the recursive conversion of the variants is done here.
+	// The variants are resolved into a CRD-compatible form later in the pipeline (in composition.go).
+	if len(s.OneOf) > 0 {
+		domainSchema.OneOf = make([]*Schema, 0, len(s.OneOf))
+		for _, oneOfProxy := range s.OneOf {
+			domainSchema.OneOf = append(domainSchema.OneOf, convertLibopenapiSchemaWithVisited(ctx, oneOfProxy, guard, visited, depth+1))
+		}
 	}
-	return fmt.Sprintf("%s (format: %s)", description, format)
+	if len(s.AnyOf) > 0 {
+		domainSchema.AnyOf = make([]*Schema, 0, len(s.AnyOf))
+		for _, anyOfProxy := range s.AnyOf {
+			domainSchema.AnyOf = append(domainSchema.AnyOf, convertLibopenapiSchemaWithVisited(ctx, anyOfProxy, guard, visited, depth+1))
+		}
+	}
+
+	return domainSchema
 }
 
 // Note: function currently not used
@@ -379,6 +525,34 @@ func convertToLibopenapiSchema(schema *Schema) *base.Schema {
 		Type:        schema.Type,
 		Description: schema.Description,
 		Required:    schema.Required,
+		MinLength:   schema.MinLength,
+		MaxLength:   schema.MaxLength,
+		Pattern:     schema.Pattern,
+		Format:      schema.Format,
+		Minimum:     schema.Minimum,
+		Maximum:     schema.Maximum,
+		MultipleOf:  schema.MultipleOf,
+		MinItems:    schema.MinItems,
+		MaxItems:    schema.MaxItems,
+	}
+
+	if schema.UniqueItems {
+		libSchema.UniqueItems = &schema.UniqueItems
+	}
+	if schema.Nullable {
+		libSchema.Nullable = &schema.Nullable
+	}
+	if schema.ReadOnly {
+		libSchema.ReadOnly = &schema.ReadOnly
+	}
+	if schema.WriteOnly {
+		libSchema.WriteOnly = &schema.WriteOnly
+	}
+	if schema.ExclusiveMinimum {
+		libSchema.ExclusiveMinimum = &base.DynamicValue[bool, float64]{A: true}
+	}
+	if schema.ExclusiveMaximum {
+		libSchema.ExclusiveMaximum = &base.DynamicValue[bool, float64]{A: true}
 	}
 
 	if len(schema.Properties) > 0 {
@@ -394,9 +568,25 @@ func convertToLibopenapiSchema(schema *Schema) *base.Schema {
 		}
 	}
 
+	if schema.AdditionalPropertiesSchema != nil {
+		libSchema.AdditionalProperties = &base.DynamicValue[*base.SchemaProxy, bool]{
+			A: base.CreateSchemaProxy(convertToLibopenapiSchema(schema.AdditionalPropertiesSchema)),
+		}
+	} else if schema.AdditionalProperties {
+		libSchema.AdditionalProperties = &base.DynamicValue[*base.SchemaProxy, bool]{N: 1, B: true}
+	}
+
 	for _, allOfSchema := range schema.AllOf {
 		libSchema.AllOf = append(libSchema.AllOf, base.CreateSchemaProxy(convertToLibopenapiSchema(allOfSchema)))
 	}
 
+	for _, oneOfSchema := range schema.OneOf {
+		libSchema.OneOf = append(libSchema.OneOf, base.CreateSchemaProxy(convertToLibopenapiSchema(oneOfSchema)))
+	}
+
+	for _, anyOfSchema := range schema.AnyOf {
+		libSchema.AnyOf = append(libSchema.AnyOf, base.CreateSchemaProxy(convertToLibopenapiSchema(anyOfSchema)))
+	}
+
 	return libSchema
 }
@@ -26,6 +26,7 @@ func (m *mockPathItem) GetOperations() map[string]Operation { return m.Ops }
 type mockOASDocument struct {
 	Paths           map[string]*mockPathItem
 	securitySchemes []SecuritySchemeInfo
+	warnings        []error
 }
 
 func (m *mockOASDocument) FindPath(path string) (PathItem, bool) {
@@ -36,3 +37,7 @@ func (m *mockOASDocument) FindPath(path string) (PathItem, bool) {
 func (m *mockOASDocument) SecuritySchemes() []SecuritySchemeInfo {
 	return m.securitySchemes
 }
+
+func (m *mockOASDocument) Warnings() []error {
+	return m.warnings
+}
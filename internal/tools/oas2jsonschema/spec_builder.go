@@ -22,6 +22,10 @@ func (g *OASSchemaGenerator) BuildSpecSchema() ([]byte, []error, error) {
 		return nil, nil, fmt.Errorf("could not determine base schema for spec: %w", err)
 	}
 
+	// Remove readOnly fields (e.g., server-generated ids and timestamps) from the spec schema.
+	warnings = append(warnings, removePropertiesWhere(baseSchema, g.generatorConfig, ".",
+		func(s *Schema) bool { return s.ReadOnly }, CodeReadOnlyFieldRemoved, "readOnly field removed from spec")...)
+
 	// Add parameters to the spec schema.
 	warnings = append(warnings, g.addParametersToSpec(baseSchema)...)
 
@@ -32,7 +36,9 @@ func (g *OASSchemaGenerator) BuildSpecSchema() ([]byte, []error, error) {
 	}
 
 	// Schema preparation for CRD compatibility.
-	if err := prepareSchemaForCRD(baseSchema, g.generatorConfig); err != nil {
+	prepareWarnings, err := prepareSchemaForCRD(baseSchema, g.generatorConfig)
+	warnings = append(warnings, prepareWarnings...)
+	if err != nil {
 		return nil, warnings, fmt.Errorf("could not prepare spec schema for CRD: %w", err)
 	}
 
@@ -48,6 +54,9 @@ func (g *OASSchemaGenerator) BuildSpecSchema() ([]byte, []error, error) {
 	// Remove excluded fields from the spec schema.
 	warnings = append(warnings, g.removeExcludedSpecFields(baseSchema)...)
 
+	// Remove the validation rules generated for oneOf/anyOf (see prepareSchemaForCRD) that reference removed fields.
+	warnings = append(warnings, dropStaleValidationRules(baseSchema, g.generatorConfig, ".")...)
+
 	// Convert the schema to JSON schema format.
 	byteSchema, err := GenerateJsonSchema(baseSchema, g.generatorConfig)
 	if err != nil {
@@ -2,6 +2,7 @@ package oas2jsonschema
 
 import (
 	"encoding/json"
+	"errors"
 	"testing"
 
 	pathparsing "github.com/krateoplatformops/oasgen-provider/internal/tools/pathparsing"
@@ -555,6 +556,148 @@ func TestBuildSpecSchema(t *testing.T) {
 	assert.NotContains(t, properties, "Authorization", "Should NOT contain 'Authorization' header")
 }
 
+func TestBuildSpecSchema_OneOfWithRemovedFields(t *testing.T) {
+	tests := []struct {
+		name          string
+		excluded      []string
+		expectRule    bool
+		expectWarning bool
+	}{
+		{name: "Unrelated field excluded", excluded: []string{"description"}, expectRule: true},
+		{name: "Field of a variant excluded", excluded: []string{"org"}, expectWarning: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDoc := &mockOASDocument{
+				Paths: map[string]*mockPathItem{
+					"/repos": {
+						Ops: map[string]Operation{
+							"post": &mockOperation{
+								RequestBody: RequestBodyInfo{
+									Content: map[string]*Schema{
+										"application/json": {
+											Type: []string{"object"},
+											Properties: []Property{
+												{Name: "name", Schema: &Schema{Type: []string{"string"}}},
+												{Name: "description", Schema: &Schema{Type: []string{"string"}}},
+											},
+											OneOf: []*Schema{
+												{
+													Type:       []string{"object"},
+													Properties: []Property{{Name: "user", Schema: &Schema{Type: []string{"string"}}}},
+													Required:   []string{"user"},
+												},
+												{
+													Type:       []string{"object"},
+													Properties: []Property{{Name: "org", Schema: &Schema{Type: []string{"string"}}}},
+													Required:   []string{"org"},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			}
+			resourceConfig := &ResourceConfig{
+				Verbs:              []Verb{{Action: "create", Method: "post", Path: "/repos"}},
+				ExcludedSpecFields: tt.excluded,
+			}
+
+			specBytes, warnings, err := NewOASSchemaGenerator(mockDoc, DefaultGeneratorConfig(), resourceConfig).BuildSpecSchema()
+			require.NoError(t, err)
+
+			var schemaMap map[string]interface{}
+			require.NoError(t, json.Unmarshal(specBytes, &schemaMap))
+			if tt.expectRule {
+				assert.Contains(t, schemaMap, "x-kubernetes-validations")
+			} else {
+				assert.NotContains(t, schemaMap, "x-kubernetes-validations", "rules referencing removed fields should be dropped")
+			}
+
+			skipped := false
+			for _, w := range warnings {
+				var genErr SchemaGenerationError
+				if errors.As(w, &genErr) && genErr.Code == CodeCompositionRuleSkipped {
+					skipped = true
+				}
+			}
+			assert.Equal(t, tt.expectWarning, skipped)
+		})
+	}
+}
+
+func TestBuildSpecSchema_ReadOnlyFields(t *testing.T) {
+	mockDoc := &mockOASDocument{
+		Paths: map[string]*mockPathItem{
+			"/items": {
+				Ops: map[string]Operation{
+					"post": &mockOperation{
+						RequestBody: RequestBodyInfo{
+							Content: map[string]*Schema{
+								"application/json": {
+									Type: []string{"object"},
+									Properties: []Property{
+										{Name: "id", Schema: &Schema{Type: []string{"string"}, ReadOnly: true}},
+										{Name: "name", Schema: &Schema{Type: []string{"string"}}},
+										{Name: "metadata", Schema: &Schema{
+											Type: []string{"object"},
+											Properties: []Property{
+												{Name: "labels", Schema: &Schema{Type: []string{"object"}}},
+												{Name: "created_at", Schema: &Schema{Type: []string{"string"}, ReadOnly: true}},
+											},
+										}},
+									},
+									AllOf: []*Schema{
+										{Properties: []Property{{Name: "updated_at", Schema: &Schema{Type: []string{"string"}, ReadOnly: true}}}},
+									},
+									Required: []string{"id", "name"},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	resourceConfig := &ResourceConfig{
+		Verbs: []Verb{{Action: "create", Method: "post", Path: "/items"}},
+	}
+
+	g := NewOASSchemaGenerator(mockDoc, DefaultGeneratorConfig(), resourceConfig)
+
+	specBytes, warnings, err := g.BuildSpecSchema()
+	require.NoError(t, err)
+
+	var schemaMap struct {
+		Properties map[string]struct {
+			Properties map[string]interface{} `json:"properties"`
+		} `json:"properties"`
+		Required []string `json:"required"`
+	}
+	require.NoError(t, json.Unmarshal(specBytes, &schemaMap))
+
+	assert.NotContains(t, schemaMap.Properties, "id")
+	assert.NotContains(t, schemaMap.Properties, "updated_at")
+	assert.Contains(t, schemaMap.Properties, "name")
+	assert.NotContains(t, schemaMap.Properties["metadata"].Properties, "created_at")
+	assert.Contains(t, schemaMap.Properties["metadata"].Properties, "labels")
+	assert.Equal(t, []string{"name"}, schemaMap.Required)
+
+	var paths []string
+	for _, w := range warnings {
+		var genErr SchemaGenerationError
+		require.ErrorAs(t, w, &genErr)
+		assert.Equal(t, CodeReadOnlyFieldRemoved, genErr.Code)
+		paths = append(paths, genErr.Path)
+	}
+	assert.ElementsMatch(t, []string{"id", "metadata.created_at", "updated_at"}, paths)
+}
+
 func TestRemoveFieldAtPath(t *testing.T) {
 	gen := &OASSchemaGenerator{
 		generatorConfig: DefaultGeneratorConfig(),
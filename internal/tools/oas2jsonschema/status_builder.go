@@ -25,7 +25,9 @@ func (g *OASSchemaGenerator) BuildStatusSchema() ([]byte, []error, error) {
 		warnings = append(warnings, SchemaGenerationError{Code: CodeNoStatusSchema, Message: "could not find a GET or FINDBY response schema for status generation"})
 	}
 
-	if err := prepareSchemaForCRD(responseSchema, g.generatorConfig); err != nil {
+	prepareWarnings, err := prepareSchemaForCRD(responseSchema, g.generatorConfig)
+	warnings = append(warnings, prepareWarnings...)
+	if err != nil {
 		return nil, warnings, fmt.Errorf("could not prepare status schema for CRD: %w", err)
 	}
 
@@ -57,6 +59,10 @@ func (g *OASSchemaGenerator) composeStatusSchema(allStatusFields []string, respo
 		foundProp, found := g.findPropertyByPath(responseSchema, pathSegments)
 		if found {
 			// `findPropertyByPath` returns a deep-copied property, so we can use it directly.
+			// The field itself is kept even if writeOnly, since it was explicitly requested,
+			// but writeOnly fields nested in it (e.g., passwords) are removed.
+			warnings = append(warnings, removePropertiesWhere(foundProp.Schema, g.generatorConfig, fieldName,
+				func(s *Schema) bool { return s.WriteOnly }, CodeWriteOnlyFieldRemoved, "writeOnly field removed from status")...)
 			g.addPropertyByPath(statusSchema, pathSegments, foundProp)
 		} else {
 			// Fallback for fields not found in the response schema.
@@ -72,7 +72,7 @@ func TestComposeStatusSchema(t *testing.T) {
 				"properties": {
 					"metadata": {
 						"properties": {
-							"creationTimestamp": { "type": "string" }
+							"creationTimestamp": { "type": "string", "format": "date-time" }
 						},
 						"type": "object"
 					}
@@ -121,7 +121,7 @@ func TestComposeStatusSchema(t *testing.T) {
 								},
 								"type": "object"
 							},
-							"creationTimestamp": { "type": "string" }
+							"creationTimestamp": { "type": "string", "format": "date-time" }
 						},
 						"type": "object"
 					}
@@ -290,6 +290,45 @@ func TestComposeStatusSchema(t *testing.T) {
 	}
 }
 
+func TestComposeStatusSchema_WriteOnlyFields(t *testing.T) {
+	responseSchema := &Schema{
+		Type: []string{"object"},
+		Properties: []Property{
+			{Name: "user", Schema: &Schema{
+				Type: []string{"object"},
+				Properties: []Property{
+					{Name: "name", Schema: &Schema{Type: []string{"string"}}},
+					{Name: "password", Schema: &Schema{Type: []string{"string"}, WriteOnly: true}},
+				},
+				Required: []string{"name", "password"},
+			}},
+			{Name: "token", Schema: &Schema{Type: []string{"string"}, WriteOnly: true}},
+		},
+	}
+
+	g := &OASSchemaGenerator{generatorConfig: DefaultGeneratorConfig()}
+	statusSchema, warnings := g.composeStatusSchema([]string{"user", "token"}, responseSchema)
+
+	require.Len(t, warnings, 1)
+	var genErr SchemaGenerationError
+	require.ErrorAs(t, warnings[0], &genErr)
+	assert.Equal(t, CodeWriteOnlyFieldRemoved, genErr.Code)
+	assert.Equal(t, "user.password", genErr.Path)
+
+	require.Len(t, statusSchema.Properties, 2)
+
+	user := statusSchema.Properties[0]
+	assert.Equal(t, "user", user.Name)
+	assert.Equal(t, []string{"name"}, propertyNames(user.Schema))
+	assert.Equal(t, []string{"name"}, user.Schema.Required)
+
+	// An explicitly requested writeOnly field is kept
+	assert.Equal(t, "token", statusSchema.Properties[1].Name)
+
+	// The response schema is not modified
+	assert.Len(t, responseSchema.Properties[0].Schema.Properties, 2)
+}
+
 func TestFindPropertyByPath(t *testing.T) {
 	// Arrange
 	schema := &Schema{
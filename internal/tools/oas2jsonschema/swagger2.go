@@ -0,0 +1,714 @@
+package oas2jsonschema
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Swagger 2.0 to OAS 3.0 conversion.
+// Only the V3 model of libopenapi is used by the generator, so Swagger 2.0 documents are converted
+// in-process before building it. The conversion works on the YAML node tree of the document,
+// preserving the order of the keys (and therefore the order of the properties in the generated schemas).
+// Reference: https://swagger.io/specification/v2/ and https://swagger.io/specification/v3/
+//
+// The main steps are:
+// - "host", "basePath" and "schemes" become "servers";
+// - "definitions", "parameters", "responses" and "securityDefinitions" are moved under "components";
+// - "body" and "formData" parameters become request bodies, with a content entry for each media type in "consumes";
+// - response schemas get a content entry for each media type in "produces";
+// - the type keywords of the other parameters and of the headers are moved into a "schema";
+// - "$ref" pointers are rewritten to the new locations.
+//
+// Each step that cannot be converted without losing information is reported as a warning.
+
+const (
+	// convertedOASVersion is the OAS version of the documents converted from Swagger 2.0.
+	convertedOASVersion = "3.0.3"
+
+	defaultMediaType        = "application/json"
+	formURLEncodedMediaType = "application/x-www-form-urlencoded"
+	multipartFormMediaType  = "multipart/form-data"
+)
+
+// swagger2OperationMethods are the HTTP methods allowed as operations in a Swagger 2.0 path item.
+var swagger2OperationMethods = []string{"get", "put", "post", "delete", "options", "head", "patch"}
+
+// swagger2TypeKeywords are the keywords of non-body parameters, items and headers
+// that describe the value and are moved into a "schema" in OAS 3.0.
+var swagger2TypeKeywords = []string{
+	"type", "format", "items", "default", "maximum", "exclusiveMaximum", "minimum", "exclusiveMinimum",
+	"maxLength", "minLength", "pattern", "maxItems", "minItems", "uniqueItems", "enum", "multipleOf",
+}
+
+// swagger2RefPrefixes maps the Swagger 2.0 "$ref" prefixes to the OAS 3.0 ones.
+// Parameters are handled separately since body parameters are moved to "requestBodies".
+var swagger2RefPrefixes = map[string]string{
+	"#/definitions/": "#/components/schemas/",
+	"#/responses/":   "#/components/responses/",
+}
+
+// swagger2Converter holds the state of the conversion of a single document.
+type swagger2Converter struct {
+	consumes []string
+	produces []string
+
+	// Global parameters by name, used to resolve the references to body and formData parameters.
+	parameters map[string]*yaml.Node
+
+	warnings []error
+}
+
+// convertSwagger2 converts a Swagger 2.0 document to an OAS 3.0 document.
+// It returns the converted document and a warning for each lossy conversion step.
+func convertSwagger2(content []byte) ([]byte, []error, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse document: %w", err)
+	}
+	root := &document
+	if root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+		root = root.Content[0]
+	}
+	if root.Kind != yaml.MappingNode {
+		return nil, nil, fmt.Errorf("document root is not an object")
+	}
+
+	c := &swagger2Converter{parameters: make(map[string]*yaml.Node)}
+	c.convertDocument(root)
+
+	out, err := yaml.Marshal(&document)
+	if err != nil {
+		return nil, c.warnings, fmt.Errorf("failed to marshal converted document: %w", err)
+	}
+	return out, c.warnings, nil
+}
+
+func (c *swagger2Converter) warn(path, format string, args ...any) {
+	c.warnings = append(c.warnings, SchemaGenerationError{
+		Path:    path,
+		Code:    CodeSwaggerConversionLossy,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (c *swagger2Converter) convertDocument(root *yaml.Node) {
+	// "swagger: 2.0" becomes "openapi: 3.0.3", in the same position.
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value == "swagger" {
+			root.Content[i].Value = "openapi"
+			root.Content[i+1] = yamlString(convertedOASVersion)
+		}
+	}
+
+	c.consumes = yamlStrings(yamlDelete(root, "consumes"))
+	c.produces = yamlStrings(yamlDelete(root, "produces"))
+
+	servers := c.convertServers(yamlDelete(root, "host"), yamlDelete(root, "basePath"), yamlDelete(root, "schemes"))
+	if servers != nil {
+		yamlInsertAfter(root, "info", "servers", servers)
+	}
+
+	components := yamlMapping()
+	if definitions := yamlDelete(root, "definitions"); definitions != nil {
+		yamlSet(components, "schemas", definitions)
+	}
+	if parameters := yamlDelete(root, "parameters"); parameters != nil {
+		c.convertGlobalParameters(parameters, components)
+	}
+	if responses := yamlDelete(root, "responses"); responses != nil {
+		for i := 1; i < len(responses.Content); i += 2 {
+			c.convertResponse(responses.Content[i], c.produces, buildPath("responses", responses.Content[i-1].Value))
+		}
+		yamlSet(components, "responses", responses)
+	}
+	if securityDefinitions := yamlDelete(root, "securityDefinitions"); securityDefinitions != nil {
+		for i := 1; i < len(securityDefinitions.Content); i += 2 {
+			convertSecurityScheme(securityDefinitions.Content[i])
+		}
+		yamlSet(components, "securitySchemes", securityDefinitions)
+	}
+
+	if paths := yamlGet(root, "paths"); paths != nil {
+		for i := 1; i < len(paths.Content); i += 2 {
+			c.convertPathItem(paths.Content[i], buildPath("paths", paths.Content[i-1].Value))
+		}
+	}
+
+	if len(components.Content) > 0 {
+		yamlSet(root, "components", components)
+	}
+
+	convertSchemaKeywords(root)
+}
+
+// convertServers builds the "servers" of the document from "host", "basePath" and "schemes".
+func (c *swagger2Converter) convertServers(host, basePath, schemes *yaml.Node) *yaml.Node {
+	if host == nil && basePath == nil {
+		return nil
+	}
+
+	base := "/"
+	if basePath != nil && basePath.Value != "" {
+		base = basePath.Value
+	}
+	if host == nil {
+		// A relative server URL, resolved against the URL of the document as in Swagger 2.0.
+		return yamlSequence(yamlMappingOf("url", yamlString(base)))
+	}
+
+	schemeList := yamlStrings(schemes)
+	if len(schemeList) == 0 {
+		// In Swagger 2.0 the default scheme is the one used to access the document, which is not known here.
+		c.warn("schemes", "no schemes defined, https is assumed for the server URL")
+		schemeList = []string{"https"}
+	}
+
+	servers := yamlSequence()
+	for _, scheme := range schemeList {
+		servers.Content = append(servers.Content, yamlMappingOf("url", yamlString(fmt.Sprintf("%s://%s%s", scheme, host.Value, strings.TrimSuffix(base, "/")))))
+	}
+	return servers
+}
+
+// convertGlobalParameters moves the global parameters under "components".
+// Body parameters become "requestBodies", formData parameters are inlined where referenced
+// since they are merged into a single request body schema.
+func (c *swagger2Converter) convertGlobalParameters(parameters, components *yaml.Node) {
+	converted := yamlMapping()
+	requestBodies := yamlMapping()
+	for i := 0; i+1 < len(parameters.Content); i += 2 {
+		name, parameter := parameters.Content[i].Value, parameters.Content[i+1]
+		c.parameters[name] = parameter
+
+		switch yamlValue(parameter, "in") {
+		case "body":
+			yamlSet(requestBodies, name, c.bodyToRequestBody(parameter, c.consumes))
+		case "formData":
+			// inlined in the request bodies of the operations referencing it
+		default:
+			c.convertParameter(parameter, buildPath("parameters", name))
+			yamlSet(converted, name, parameter)
+		}
+	}
+	if len(converted.Content) > 0 {
+		yamlSet(components, "parameters", converted)
+	}
+	if len(requestBodies.Content) > 0 {
+		yamlSet(components, "requestBodies", requestBodies)
+	}
+}
+
+// convertPathItem converts the parameters, request bodies and responses of all the operations of a path item.
+func (c *swagger2Converter) convertPathItem(pathItem *yaml.Node, path string) {
+	if pathItem.Kind != yaml.MappingNode {
+		return
+	}
+
+	// Path-level body and formData parameters are moved to the operations,
+	// since request bodies can only be defined at operation level in OAS 3.0.
+	var pathBody *yaml.Node
+	var pathForm []*yaml.Node
+	if parameters := yamlGet(pathItem, "parameters"); parameters != nil {
+		pathBody, pathForm = c.splitParameters(parameters, buildPath(path, "parameters"))
+		if len(parameters.Content) == 0 {
+			yamlDelete(pathItem, "parameters")
+		}
+	}
+
+	for i := 0; i+1 < len(pathItem.Content); i += 2 {
+		method := pathItem.Content[i].Value
+		if !slices.Contains(swagger2OperationMethods, method) {
+			continue
+		}
+		c.convertOperation(pathItem.Content[i+1], buildPath(path, method), pathBody, pathForm)
+	}
+}
+
+func (c *swagger2Converter) convertOperation(operation *yaml.Node, path string, pathBody *yaml.Node, pathForm []*yaml.Node) {
+	consumes := c.consumes
+	if n := yamlDelete(operation, "consumes"); n != nil {
+		consumes = yamlStrings(n)
+	}
+	produces := c.produces
+	if n := yamlDelete(operation, "produces"); n != nil {
+		produces = yamlStrings(n)
+	}
+
+	body, form := pathBody, pathForm
+	if parameters := yamlGet(operation, "parameters"); parameters != nil {
+		opBody, opForm := c.splitParameters(parameters, buildPath(path, "parameters"))
+		if opBody != nil {
+			body = opBody
+		}
+		// Operation-level formData parameters override the path-level ones with the same name.
+		form = mergeFormParameters(pathForm, opForm)
+		if len(parameters.Content) == 0 {
+			yamlDelete(operation, "parameters")
+		}
+	}
+
+	var requestBody *yaml.Node
+	switch {
+	case body != nil && len(form) > 0:
+		c.warn(path, "body and formData parameters cannot be used together, formData parameters dropped")
+		fallthrough
+	case body != nil:
+		if ref := yamlValue(body, "$ref"); ref != "" {
+			requestBody = yamlMappingOf("$ref", yamlString("#/components/requestBodies/"+strings.TrimPrefix(ref, "#/parameters/")))
+		} else {
+			requestBody = c.bodyToRequestBody(body, consumes)
+		}
+	case len(form) > 0:
+		requestBody = c.formToRequestBody(form, consumes, path)
+	}
+	if requestBody != nil {
+		yamlInsertAfter(operation, "parameters", "requestBody", requestBody)
+	}
+
+	if responses := yamlGet(operation, "responses"); responses != nil {
+		for i := 1; i < len(responses.Content); i += 2 {
+			c.convertResponse(responses.Content[i], produces, buildPath(buildPath(path, "responses"), responses.Content[i-1].Value))
+		}
+	}
+}
+
+// splitParameters converts in place the non-body parameters of a parameter list and removes
+// the body and formData ones from it, returning them (resolved, if referenced).
+func (c *swagger2Converter) splitParameters(parameters *yaml.Node, path string) (*yaml.Node, []*yaml.Node) {
+	var body *yaml.Node
+	var form []*yaml.Node
+
+	kept := parameters.Content[:0]
+	for _, parameter := range parameters.Content {
+		resolved := parameter
+		if ref := yamlValue(parameter, "$ref"); strings.HasPrefix(ref, "#/parameters/") {
+			if global, ok := c.parameters[strings.TrimPrefix(ref, "#/parameters/")]; ok {
+				resolved = global
+			}
+		}
+
+		name := yamlValue(resolved, "name")
+		switch yamlValue(resolved, "in") {
+		case "body":
+			if body != nil {
+				c.warn(buildPath(path, name), "only one body parameter is allowed, parameter dropped")
+				continue
+			}
+			// A reference is kept as is and points to the converted global request body.
+			body = parameter
+		case "formData":
+			form = append(form, resolved)
+		default:
+			if ref := yamlValue(parameter, "$ref"); ref != "" {
+				yamlSet(parameter, "$ref", yamlString(strings.Replace(ref, "#/parameters/", "#/components/parameters/", 1)))
+			} else {
+				c.convertParameter(parameter, buildPath(path, name))
+			}
+			kept = append(kept, parameter)
+		}
+	}
+	parameters.Content = kept
+
+	return body, form
+}
+
+// convertParameter converts a non-body parameter in place, moving its type keywords into a "schema".
+func (c *swagger2Converter) convertParameter(parameter *yaml.Node, path string) {
+	in := yamlValue(parameter, "in")
+	schema := c.extractSchema(parameter, path)
+
+	// collectionFormat becomes "style" and "explode".
+	// The default (csv) is the default style of every location, except query and cookie where "explode" must be disabled.
+	collectionFormat := yamlValue(yamlDelete(parameter, "collectionFormat"), "")
+	if yamlValue(schema, "type") == "array" {
+		switch collectionFormat {
+		case "", "csv":
+			if in == "query" || in == "cookie" {
+				yamlSet(parameter, "style", yamlString("form"))
+				yamlSet(parameter, "explode", yamlBool(false))
+			}
+		case "multi":
+			yamlSet(parameter, "style", yamlString("form"))
+			yamlSet(parameter, "explode", yamlBool(true))
+		case "ssv":
+			yamlSet(parameter, "style", yamlString("spaceDelimited"))
+			yamlSet(parameter, "explode", yamlBool(false))
+		case "pipes":
+			yamlSet(parameter, "style", yamlString("pipeDelimited"))
+			yamlSet(parameter, "explode", yamlBool(false))
+		default:
+			c.warn(path, "collectionFormat '%s' has no equivalent in OAS 3.0, the default serialization is used", collectionFormat)
+		}
+	}
+
+	yamlSet(parameter, "schema", schema)
+}
+
+// extractSchema removes the type keywords from a parameter, items or header object and returns them as a schema.
+func (c *swagger2Converter) extractSchema(node *yaml.Node, path string) *yaml.Node {
+	schema := yamlMapping()
+	for _, keyword := range swagger2TypeKeywords {
+		value := yamlDelete(node, keyword)
+		if value == nil {
+			continue
+		}
+		if keyword == "items" && value.Kind == yaml.MappingNode {
+			// Nested items objects have the same keywords, except the collectionFormat which is not supported for nested arrays.
+			if collectionFormat := yamlDelete(value, "collectionFormat"); collectionFormat != nil {
+				c.warn(path, "collectionFormat '%s' of nested items has no equivalent in OAS 3.0, dropped", collectionFormat.Value)
+			}
+			value = c.extractSchema(value, path)
+		}
+		yamlSet(schema, keyword, value)
+	}
+	if yamlValue(schema, "type") == "file" {
+		yamlSet(schema, "type", yamlString("string"))
+		yamlSet(schema, "format", yamlString("binary"))
+	}
+	return schema
+}
+
+// bodyToRequestBody builds a request body from a body parameter, with a content entry for each media type.
+func (c *swagger2Converter) bodyToRequestBody(parameter *yaml.Node, consumes []string) *yaml.Node {
+	requestBody := yamlMapping()
+	if description := yamlGet(parameter, "description"); description != nil {
+		yamlSet(requestBody, "description", description)
+	}
+
+	schema := yamlGet(parameter, "schema")
+	if schema == nil {
+		schema = yamlMapping()
+	}
+	if len(consumes) == 0 {
+		consumes = []string{defaultMediaType}
+	}
+	content := yamlMapping()
+	for _, mediaType := range consumes {
+		yamlSet(content, mediaType, yamlMappingOf("schema", schema))
+	}
+	yamlSet(requestBody, "content", content)
+
+	if required := yamlGet(parameter, "required"); required != nil {
+		yamlSet(requestBody, "required", required)
+	}
+	return requestBody
+}
+
+// formToRequestBody builds a request body from the formData parameters of an operation:
+// each parameter becomes a property of an object schema.
+func (c *swagger2Converter) formToRequestBody(parameters []*yaml.Node, consumes []string, path string) *yaml.Node {
+	schema := yamlMappingOf("type", yamlString("object"))
+	properties := yamlMapping()
+	var required []string
+	hasFile := false
+	for _, parameter := range parameters {
+		// The parameter is copied since it may be a global one, inlined in several operations.
+		parameter = yamlCopy(parameter)
+		name := yamlValue(parameter, "name")
+		propertyPath := buildPath(buildPath(path, "parameters"), name)
+
+		if yamlValue(parameter, "type") == "file" {
+			hasFile = true
+		}
+		if collectionFormat := yamlDelete(parameter, "collectionFormat"); collectionFormat != nil && collectionFormat.Value != "multi" {
+			c.warn(propertyPath, "collectionFormat '%s' of formData parameters is not converted, dropped", collectionFormat.Value)
+		}
+		if allowEmptyValue := yamlGet(parameter, "allowEmptyValue"); allowEmptyValue != nil {
+			c.warn(propertyPath, "allowEmptyValue of formData parameters has no equivalent in OAS 3.0, dropped")
+		}
+
+		property := c.extractSchema(parameter, propertyPath)
+		if description := yamlGet(parameter, "description"); description != nil {
+			yamlSet(property, "description", description)
+		}
+		yamlSet(properties, name, property)
+		if yamlValue(parameter, "required") == "true" {
+			required = append(required, name)
+		}
+	}
+	yamlSet(schema, "properties", properties)
+	if len(required) > 0 {
+		list := yamlSequence()
+		for _, r := range required {
+			list.Content = append(list.Content, yamlString(r))
+		}
+		yamlSet(schema, "required", list)
+	}
+
+	var mediaTypes []string
+	for _, mediaType := range consumes {
+		if mediaType == formURLEncodedMediaType || mediaType == multipartFormMediaType {
+			mediaTypes = append(mediaTypes, mediaType)
+		}
+	}
+	if len(mediaTypes) == 0 {
+		if hasFile {
+			mediaTypes = []string{multipartFormMediaType}
+		} else {
+			mediaTypes = []string{formURLEncodedMediaType}
+		}
+	}
+
+	content := yamlMapping()
+	for _, mediaType := range mediaTypes {
+		yamlSet(content, mediaType, yamlMappingOf("schema", schema))
+	}
+	return yamlMappingOf("content", content)
+}
+
+// convertResponse converts a response in place: the schema and the examples are moved into a content entry
+// for each media type and the type keywords of the headers are moved into a "schema".
+func (c *swagger2Converter) convertResponse(response *yaml.Node, produces []string, path string) {
+	if response.Kind != yaml.MappingNode || yamlGet(response, "$ref") != nil {
+		return
+	}
+
+	if headers := yamlGet(response, "headers"); headers != nil {
+		for i := 0; i+1 < len(headers.Content); i += 2 {
+			header, headerPath := headers.Content[i+1], buildPath(buildPath(path, "headers"), headers.Content[i].Value)
+			if collectionFormat := yamlDelete(header, "collectionFormat"); collectionFormat != nil && collectionFormat.Value != "csv" {
+				c.warn(headerPath, "collectionFormat '%s' of headers has no equivalent in OAS 3.0, dropped", collectionFormat.Value)
+			}
+			yamlSet(header, "schema", c.extractSchema(header, headerPath))
+		}
+	}
+
+	schema := yamlDelete(response, "schema")
+	examples := yamlDelete(response, "examples")
+	if schema == nil {
+		if examples != nil {
+			c.warn(path, "examples of a response without schema have no equivalent in OAS 3.0, dropped")
+		}
+		return
+	}
+	if len(produces) == 0 {
+		produces = []string{defaultMediaType}
+	}
+
+	content := yamlMapping()
+	for _, mediaType := range produces {
+		mediaTypeObject := yamlMappingOf("schema", schema)
+		if example := yamlGet(examples, mediaType); example != nil {
+			yamlSet(mediaTypeObject, "example", example)
+		}
+		yamlSet(content, mediaType, mediaTypeObject)
+	}
+	if examples != nil {
+		for i := 0; i+1 < len(examples.Content); i += 2 {
+			if !slices.Contains(produces, examples.Content[i].Value) {
+				c.warn(path, "example for media type '%s' not listed in produces, dropped", examples.Content[i].Value)
+			}
+		}
+	}
+	yamlSet(response, "content", content)
+}
+
+// convertSecurityScheme converts a security definition in place.
+// Basic authentication becomes an "http" scheme and the OAuth2 flow becomes a "flows" object.
+func convertSecurityScheme(scheme *yaml.Node) {
+	switch yamlValue(scheme, "type") {
+	case "basic":
+		yamlSet(scheme, "type", yamlString(string(SchemeTypeHTTP)))
+		yamlSet(scheme, "scheme", yamlString("basic"))
+	case "oauth2":
+		flowNames := map[string]string{
+			"implicit":    "implicit",
+			"password":    "password",
+			"application": "clientCredentials",
+			"accessCode":  "authorizationCode",
+		}
+		flow := yamlMapping()
+		for _, keyword := range []string{"authorizationUrl", "tokenUrl", "scopes"} {
+			if value := yamlDelete(scheme, keyword); value != nil {
+				yamlSet(flow, keyword, value)
+			}
+		}
+		if yamlGet(flow, "scopes") == nil {
+			yamlSet(flow, "scopes", yamlMapping())
+		}
+		if name, ok := flowNames[yamlValue(yamlDelete(scheme, "flow"), "")]; ok {
+			yamlSet(scheme, "flows", yamlMappingOf(name, flow))
+		}
+	}
+}
+
+// swagger2NamedMappings are the keywords whose values map names (e.g., of properties or responses) to objects.
+// Their keys are names chosen by the author of the document, so they are never treated as keywords.
+var swagger2NamedMappings = []string{
+	"properties", "patternProperties", "schemas", "responses", "parameters", "requestBodies",
+	"headers", "content", "paths", "securitySchemes",
+}
+
+// convertSchemaKeywords walks the whole document and converts the Swagger 2.0 schema keywords
+// that changed in OAS 3.0, and rewrites the "$ref" pointers to the new locations.
+// Values (examples, defaults, enums) and extensions are not walked, since they are not schemas.
+func convertSchemaKeywords(node *yaml.Node) {
+	if node == nil {
+		return
+	}
+	if node.Kind != yaml.MappingNode {
+		for _, child := range node.Content {
+			convertSchemaKeywords(child)
+		}
+		return
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		switch {
+		case key.Value == "$ref" && value.Kind == yaml.ScalarNode:
+			// Only local references are rewritten, the files of a bundle are not converted.
+			for prefix, replacement := range swagger2RefPrefixes {
+				if rest, ok := strings.CutPrefix(value.Value, prefix); ok {
+					value.Value = replacement + rest
+				}
+			}
+		case slices.Contains(swagger2NamedMappings, key.Value) && value.Kind == yaml.MappingNode:
+			// Every entry is walked, whatever its name (e.g., a property named 'default' or 'x-nullable').
+			for j := 1; j < len(value.Content); j += 2 {
+				convertSchemaKeywords(value.Content[j])
+			}
+		case key.Value == "x-nullable":
+			key.Value = "nullable"
+		case key.Value == "discriminator" && value.Kind == yaml.ScalarNode:
+			// In Swagger 2.0 the discriminator is the name of the property.
+			node.Content[i+1] = yamlMappingOf("propertyName", yamlString(value.Value))
+		case key.Value == "type" && value.Value == "file":
+			value.Value = "string"
+			yamlSet(node, "format", yamlString("binary"))
+		case slices.Contains([]string{"example", "examples", "default", "enum"}, key.Value), strings.HasPrefix(key.Value, "x-"):
+			// not a schema
+		default:
+			convertSchemaKeywords(value)
+		}
+	}
+}
+
+// mergeFormParameters merges two lists of formData parameters, the second one overriding the first by name.
+func mergeFormParameters(base, override []*yaml.Node) []*yaml.Node {
+	merged := slices.Clone(override)
+	for _, parameter := range base {
+		if !slices.ContainsFunc(override, func(p *yaml.Node) bool { return yamlValue(p, "name") == yamlValue(parameter, "name") }) {
+			merged = append(merged, parameter)
+		}
+	}
+	return merged
+}
+
+// --- YAML node helpers ---
+
+func yamlMapping() *yaml.Node {
+	return &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+}
+
+func yamlMappingOf(key string, value *yaml.Node) *yaml.Node {
+	m := yamlMapping()
+	yamlSet(m, key, value)
+	return m
+}
+
+func yamlSequence(items ...*yaml.Node) *yaml.Node {
+	return &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Content: items}
+}
+
+func yamlString(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
+
+func yamlBool(value bool) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: fmt.Sprint(value)}
+}
+
+// yamlGet returns the value of a key of a mapping node, nil if the node is not a mapping or the key is not found.
+func yamlGet(m *yaml.Node, key string) *yaml.Node {
+	if m == nil || m.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// yamlValue returns the scalar value of a key of a mapping node, or of the node itself if the key is empty.
+func yamlValue(m *yaml.Node, key string) string {
+	if key != "" {
+		m = yamlGet(m, key)
+	}
+	if m == nil || m.Kind != yaml.ScalarNode {
+		return ""
+	}
+	return m.Value
+}
+
+// yamlStrings returns the values of a sequence of scalars.
+func yamlStrings(n *yaml.Node) []string {
+	if n == nil || n.Kind != yaml.SequenceNode {
+		return nil
+	}
+	values := make([]string, 0, len(n.Content))
+	for _, item := range n.Content {
+		values = append(values, item.Value)
+	}
+	return values
+}
+
+// yamlSet sets the value of a key of a mapping node, appending the key if not found.
+func yamlSet(m *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			m.Content[i+1] = value
+			return
+		}
+	}
+	m.Content = append(m.Content, yamlString(key), value)
+}
+
+// yamlInsertAfter sets the value of a key of a mapping node, inserting the key after the given one if not found
+// (or at the end if the given one is not found either).
+func yamlInsertAfter(m *yaml.Node, after, key string, value *yaml.Node) {
+	if yamlGet(m, key) != nil {
+		yamlSet(m, key, value)
+		return
+	}
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == after {
+			m.Content = slices.Insert(m.Content, i+2, yamlString(key), value)
+			return
+		}
+	}
+	m.Content = append(m.Content, yamlString(key), value)
+}
+
+// yamlDelete removes a key from a mapping node and returns its value, nil if not found.
+func yamlDelete(m *yaml.Node, key string) *yaml.Node {
+	if m == nil || m.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			value := m.Content[i+1]
+			m.Content = slices.Delete(m.Content, i, i+2)
+			return value
+		}
+	}
+	return nil
+}
+
+// yamlCopy returns a deep copy of a node.
+func yamlCopy(n *yaml.Node) *yaml.Node {
+	if n == nil {
+		return nil
+	}
+	c := *n
+	c.Content = make([]*yaml.Node, len(n.Content))
+	for i, child := range n.Content {
+		c.Content[i] = yamlCopy(child)
+	}
+	return &c
+}
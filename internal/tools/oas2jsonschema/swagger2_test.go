@@ -0,0 +1,439 @@
+package oas2jsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+const swagger2Petstore = `
+swagger: "2.0"
+info:
+  title: Petstore
+  version: 1.0.0
+host: petstore.example.com
+basePath: /v2
+schemes:
+  - https
+consumes:
+  - application/json
+produces:
+  - application/json
+securityDefinitions:
+  basicAuth:
+    type: basic
+  apiKey:
+    type: apiKey
+    name: X-API-Key
+    in: header
+  oauth:
+    type: oauth2
+    flow: accessCode
+    authorizationUrl: https://petstore.example.com/oauth/authorize
+    tokenUrl: https://petstore.example.com/oauth/token
+    scopes:
+      write:pets: modify pets
+parameters:
+  petId:
+    name: petId
+    in: path
+    required: true
+    type: integer
+    format: int64
+  petBody:
+    name: pet
+    in: body
+    required: true
+    schema:
+      $ref: '#/definitions/Pet'
+paths:
+  /pets:
+    get:
+      parameters:
+        - name: tags
+          in: query
+          type: array
+          items:
+            type: string
+          collectionFormat: multi
+        - name: limit
+          in: query
+          type: integer
+          maximum: 100
+      responses:
+        '200':
+          description: OK
+          headers:
+            X-Total-Count:
+              type: integer
+          schema:
+            type: array
+            items:
+              $ref: '#/definitions/Pet'
+    post:
+      parameters:
+        - $ref: '#/parameters/petBody'
+      responses:
+        '201':
+          description: Created
+          schema:
+            $ref: '#/definitions/Pet'
+  /pets/{petId}:
+    parameters:
+      - $ref: '#/parameters/petId'
+    get:
+      responses:
+        '200':
+          $ref: '#/responses/PetResponse'
+    put:
+      consumes:
+        - application/json
+        - application/xml
+      parameters:
+        - name: pet
+          in: body
+          schema:
+            $ref: '#/definitions/Pet'
+      responses:
+        '200':
+          $ref: '#/responses/PetResponse'
+  /pets/{petId}/photo:
+    post:
+      parameters:
+        - $ref: '#/parameters/petId'
+        - name: file
+          in: formData
+          type: file
+          required: true
+        - name: caption
+          in: formData
+          type: string
+      responses:
+        '204':
+          description: No Content
+responses:
+  PetResponse:
+    description: A pet
+    schema:
+      $ref: '#/definitions/Pet'
+definitions:
+  Pet:
+    type: object
+    discriminator: kind
+    required:
+      - name
+      - kind
+    properties:
+      id:
+        type: integer
+        format: int64
+        readOnly: true
+      name:
+        type: string
+      kind:
+        type: string
+      nickname:
+        type: string
+        x-nullable: true
+`
+
+func TestParse_Swagger2(t *testing.T) {
+	doc, err := NewLibOASParser().Parse([]byte(swagger2Petstore))
+	require.NoError(t, err)
+	require.NotNil(t, doc)
+	assert.Empty(t, doc.Warnings())
+
+	t.Run("definitions and response schemas", func(t *testing.T) {
+		path, ok := doc.FindPath("/pets")
+		require.True(t, ok)
+
+		resp, ok := path.GetOperations()["get"].GetResponses()[200]
+		require.True(t, ok)
+		schema := resp.Content["application/json"]
+		require.NotNil(t, schema)
+		assert.Equal(t, []string{"array"}, schema.Type)
+		require.NotNil(t, schema.Items)
+		assert.Equal(t, []string{"id", "name", "kind", "nickname"}, propertyNames(schema.Items))
+		assert.True(t, schema.Items.Properties[0].Schema.ReadOnly)
+		assert.True(t, schema.Items.Properties[3].Schema.Nullable)
+	})
+
+	t.Run("non-body parameters", func(t *testing.T) {
+		path, ok := doc.FindPath("/pets")
+		require.True(t, ok)
+
+		params := path.GetOperations()["get"].GetParameters()
+		require.Len(t, params, 2)
+		assert.Equal(t, "tags", params[0].Name)
+		assert.Equal(t, "query", params[0].In)
+		require.NotNil(t, params[0].Schema)
+		assert.Equal(t, []string{"array"}, params[0].Schema.Type)
+		assert.Equal(t, []string{"string"}, params[0].Schema.Items.Type)
+		assert.Equal(t, []string{"integer"}, params[1].Schema.Type)
+		assert.Equal(t, float64(100), *params[1].Schema.Maximum)
+	})
+
+	t.Run("body parameters become request bodies", func(t *testing.T) {
+		path, ok := doc.FindPath("/pets")
+		require.True(t, ok)
+
+		post := path.GetOperations()["post"]
+		assert.Empty(t, post.GetParameters())
+		body := post.GetRequestBody().Content["application/json"]
+		require.NotNil(t, body)
+		assert.Equal(t, []string{"id", "name", "kind", "nickname"}, propertyNames(body))
+
+		path, ok = doc.FindPath("/pets/{petId}")
+		require.True(t, ok)
+
+		put := path.GetOperations()["put"]
+		assert.Contains(t, put.GetRequestBody().Content, "application/json")
+		assert.Contains(t, put.GetRequestBody().Content, "application/xml")
+	})
+
+	t.Run("formData parameters become a request body", func(t *testing.T) {
+		path, ok := doc.FindPath("/pets/{petId}/photo")
+		require.True(t, ok)
+
+		post := path.GetOperations()["post"]
+		params := post.GetParameters()
+		require.Len(t, params, 1)
+		assert.Equal(t, "petId", params[0].Name)
+		assert.Equal(t, []string{"integer"}, params[0].Schema.Type)
+
+		body := post.GetRequestBody().Content["multipart/form-data"]
+		require.NotNil(t, body)
+		assert.Equal(t, []string{"file", "caption"}, propertyNames(body))
+		assert.Equal(t, []string{"string"}, body.Properties[0].Schema.Type)
+		assert.Equal(t, "binary", body.Properties[0].Schema.Format)
+		assert.Equal(t, []string{"file"}, body.Required)
+	})
+
+	t.Run("referenced responses", func(t *testing.T) {
+		path, ok := doc.FindPath("/pets/{petId}")
+		require.True(t, ok)
+
+		resp, ok := path.GetOperations()["get"].GetResponses()[200]
+		require.True(t, ok)
+		assert.Equal(t, []string{"id", "name", "kind", "nickname"}, propertyNames(resp.Content["application/json"]))
+	})
+
+	t.Run("security definitions", func(t *testing.T) {
+		schemes := doc.SecuritySchemes()
+		require.Len(t, schemes, 3)
+		assert.Equal(t, SecuritySchemeInfo{Name: "basicAuth", Type: SchemeTypeHTTP, Scheme: "basic"}, schemes[0])
+		assert.Equal(t, SecuritySchemeInfo{Name: "apiKey", Type: SchemeTypeAPIKey, In: "header", ParamName: "X-API-Key"}, schemes[1])
+		assert.Equal(t, SchemeTypeOAuth2, schemes[2].Type)
+	})
+}
+
+func TestConvertSwagger2(t *testing.T) {
+	out, warnings, err := convertSwagger2([]byte(swagger2Petstore))
+	require.NoError(t, err)
+	assert.Empty(t, warnings)
+
+	var converted map[string]interface{}
+	require.NoError(t, yaml.Unmarshal(out, &converted))
+
+	assert.Equal(t, "3.0.3", converted["openapi"])
+	assert.NotContains(t, converted, "swagger")
+	assert.NotContains(t, converted, "definitions")
+	assert.Equal(t, []interface{}{map[string]interface{}{"url": "https://petstore.example.com/v2"}}, converted["servers"])
+
+	components := converted["components"].(map[string]interface{})
+	assert.Contains(t, components["schemas"], "Pet")
+	assert.Contains(t, components["parameters"], "petId")
+	assert.Contains(t, components["requestBodies"], "petBody")
+	assert.Contains(t, components["responses"], "PetResponse")
+
+	pet := components["schemas"].(map[string]interface{})["Pet"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"propertyName": "kind"}, pet["discriminator"])
+
+	oauth := components["securitySchemes"].(map[string]interface{})["oauth"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{
+		"authorizationCode": map[string]interface{}{
+			"authorizationUrl": "https://petstore.example.com/oauth/authorize",
+			"tokenUrl":         "https://petstore.example.com/oauth/token",
+			"scopes":           map[string]interface{}{"write:pets": "modify pets"},
+		},
+	}, oauth["flows"])
+
+	pets := converted["paths"].(map[string]interface{})["/pets"].(map[string]interface{})
+	tags := pets["get"].(map[string]interface{})["parameters"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "form", tags["style"])
+	assert.Equal(t, true, tags["explode"])
+	assert.NotContains(t, tags, "collectionFormat")
+
+	post := pets["post"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"$ref": "#/components/requestBodies/petBody"}, post["requestBody"])
+}
+
+func TestConvertSwagger2_PropertiesNamedAsKeywords(t *testing.T) {
+	doc := `swagger: "2.0"
+info:
+  title: Settings
+  version: "1.0"
+paths:
+  /settings:
+    get:
+      responses:
+        default:
+          description: Settings
+          schema:
+            $ref: '#/definitions/Settings'
+definitions:
+  Value:
+    type: string
+  Settings:
+    type: object
+    properties:
+      default:
+        $ref: '#/definitions/Value'
+      example:
+        $ref: '#/definitions/Value'
+      x-nullable:
+        type: string
+        x-nullable: true
+`
+	out, _, err := convertSwagger2([]byte(doc))
+	require.NoError(t, err)
+
+	var converted map[string]interface{}
+	require.NoError(t, yaml.Unmarshal(out, &converted))
+
+	settings := converted["components"].(map[string]interface{})["schemas"].(map[string]interface{})["Settings"].(map[string]interface{})
+	properties := settings["properties"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"$ref": "#/components/schemas/Value"}, properties["default"])
+	assert.Equal(t, map[string]interface{}{"$ref": "#/components/schemas/Value"}, properties["example"])
+	assert.Equal(t, map[string]interface{}{"type": "string", "nullable": true}, properties["x-nullable"])
+
+	get := converted["paths"].(map[string]interface{})["/settings"].(map[string]interface{})["get"].(map[string]interface{})
+	response := get["responses"].(map[string]interface{})["default"].(map[string]interface{})
+	schema := response["content"].(map[string]interface{})["application/json"].(map[string]interface{})["schema"]
+	assert.Equal(t, map[string]interface{}{"$ref": "#/components/schemas/Settings"}, schema)
+}
+
+func TestConvertSwagger2_LossyWarnings(t *testing.T) {
+	testCases := []struct {
+		name          string
+		document      string
+		expectedPaths []string
+	}{
+		{
+			name: "missing schemes",
+			document: `
+swagger: "2.0"
+info: {title: t, version: "1"}
+host: example.com
+paths: {}
+`,
+			expectedPaths: []string{"schemes"},
+		},
+		{
+			name: "tsv collection format",
+			document: `
+swagger: "2.0"
+info: {title: t, version: "1"}
+paths:
+  /items:
+    get:
+      parameters:
+        - {name: ids, in: query, type: array, items: {type: string}, collectionFormat: tsv}
+      responses:
+        '200': {description: OK}
+`,
+			expectedPaths: []string{"paths./items.get.parameters.ids"},
+		},
+		{
+			name: "nested items collection format",
+			document: `
+swagger: "2.0"
+info: {title: t, version: "1"}
+paths:
+  /items:
+    get:
+      parameters:
+        - name: matrix
+          in: query
+          type: array
+          items: {type: array, items: {type: integer}, collectionFormat: pipes}
+      responses:
+        '200': {description: OK}
+`,
+			expectedPaths: []string{"paths./items.get.parameters.matrix"},
+		},
+		{
+			name: "body and formData parameters together",
+			document: `
+swagger: "2.0"
+info: {title: t, version: "1"}
+paths:
+  /items:
+    post:
+      parameters:
+        - {name: item, in: body, schema: {type: object}}
+        - {name: note, in: formData, type: string}
+      responses:
+        '200': {description: OK}
+`,
+			expectedPaths: []string{"paths./items.post"},
+		},
+		{
+			name: "formData allowEmptyValue",
+			document: `
+swagger: "2.0"
+info: {title: t, version: "1"}
+paths:
+  /items:
+    post:
+      parameters:
+        - {name: note, in: formData, type: string, allowEmptyValue: true}
+      responses:
+        '200': {description: OK}
+`,
+			expectedPaths: []string{"paths./items.post.parameters.note"},
+		},
+		{
+			name: "example for a media type not produced",
+			document: `
+swagger: "2.0"
+info: {title: t, version: "1"}
+produces: [application/json]
+paths:
+  /items:
+    get:
+      responses:
+        '200':
+          description: OK
+          schema: {type: string}
+          examples:
+            application/json: "a"
+            text/plain: "a"
+`,
+			expectedPaths: []string{"paths./items.get.responses.200"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, warnings, err := convertSwagger2([]byte(tc.document))
+			require.NoError(t, err)
+
+			var paths []string
+			for _, w := range warnings {
+				var genErr SchemaGenerationError
+				require.ErrorAs(t, w, &genErr)
+				assert.Equal(t, CodeSwaggerConversionLossy, genErr.Code)
+				paths = append(paths, genErr.Path)
+			}
+			assert.Equal(t, tc.expectedPaths, paths)
+
+			// The converted document is still a valid OAS 3.0 document
+			doc, err := NewLibOASParser().Parse([]byte(tc.document))
+			require.NoError(t, err)
+			assert.Len(t, doc.Warnings(), len(tc.expectedPaths))
+		})
+	}
+}
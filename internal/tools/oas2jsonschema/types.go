@@ -14,6 +14,11 @@ type GeneratorConfig struct {
 	MaxRecursionDepth        int
 	MaxRecursionNodes        int32
 	RecursionTimeout         time.Duration
+	// CoerceNumberToInteger enables the legacy behaviour of converting every "number" type to "integer".
+	CoerceNumberToInteger bool
+	// SupportedFormats is the allowlist of formats emitted as CRD "format" values.
+	// Other formats are appended to the description as a note.
+	SupportedFormats []string
 }
 
 // DefaultGeneratorConfig returns a new GeneratorConfig with default values.
@@ -25,6 +30,10 @@ func DefaultGeneratorConfig() *GeneratorConfig {
 		MaxRecursionDepth:        50,
 		MaxRecursionNodes:        5000,
 		RecursionTimeout:         30 * time.Second,
+		CoerceNumberToInteger:    false,
+		// Formats supported by Kubernetes.
+		// Source: https://kubernetes.io/docs/tasks/extend-kubernetes/custom-resources/custom-resource-definitions/#format
+		SupportedFormats: []string{"date-time", "date", "int32", "int64", "byte", "uuid", "email", "hostname", "ipv4", "ipv6"},
 	}
 }
 
@@ -68,18 +77,40 @@ type Verb struct {
 // Potentially, this struct could be modified to include more fields in the future.
 // It is the domainSchema defined in this domain (oas2jsonschema).
 type Schema struct {
-	Type                 []string // OAS 3.1 allows multiple types (e.g., ["string", "null"])
-	Description          string
-	Properties           []Property // Using a slice to preserve order of properties (TODO: consider using a map)
-	Items                *Schema    // For array types, this defines the schema of items in the array
-	AllOf                []*Schema
-	Required             []string
-	Default              interface{} // Default value for the schema
-	Enum                 []interface{}
-	AdditionalProperties bool
-	MaxProperties        int
-	Format               string                 // Not validated but added value to description if present
-	Extensions           map[string]interface{} // Currently not used but can hold custom extensions
+	Type                       []string // OAS 3.1 allows multiple types (e.g., ["string", "null"])
+	Description                string
+	Properties                 []Property // Using a slice to preserve order of properties (TODO: consider using a map)
+	Items                      *Schema    // For array types, this defines the schema of items in the array
+	AllOf                      []*Schema
+	OneOf                      []*Schema // Resolved into a CRD-compatible form by prepareSchemaForCRD
+	AnyOf                      []*Schema // Resolved into a CRD-compatible form by prepareSchemaForCRD
+	Required                   []string
+	Default                    interface{} // Default value for the schema
+	Enum                       []interface{}
+	AdditionalProperties       bool
+	AdditionalPropertiesSchema *Schema // Schema form of additionalProperties (e.g., map[string]string), takes precedence over the boolean form
+	MaxProperties              int
+	Format                     string                 // Emitted as CRD format if in GeneratorConfig.SupportedFormats, otherwise added to the description
+	Extensions                 map[string]interface{} // Holds custom extensions (e.g., x-kubernetes-validations)
+
+	// Validation keywords, propagated to the CRD so that they are enforced at admission time.
+	// Pointers are used for numeric keywords to distinguish "not set" from the zero value.
+	MinLength        *int64
+	MaxLength        *int64
+	Pattern          string
+	Minimum          *float64
+	Maximum          *float64
+	ExclusiveMinimum bool // OAS 3.0 (boolean) form, the OAS 3.1 numeric form is converted by the adapter
+	ExclusiveMaximum bool // OAS 3.0 (boolean) form, the OAS 3.1 numeric form is converted by the adapter
+	MultipleOf       *float64
+	MinItems         *int64
+	MaxItems         *int64
+	UniqueItems      bool // Not supported by Kubernetes as is, converted by prepareSchemaForCRD
+
+	// Other keywords, handled by the generator rather than propagated as is.
+	Nullable  bool // OAS 3.0 "nullable", the OAS 3.1 form is a "null" entry in Type
+	ReadOnly  bool // Server-generated field (e.g., id): removed from the spec schema
+	WriteOnly bool // Input-only field (e.g., password): removed from the status schema
 }
 
 // Property represents a single key-value pair in a schema's properties.
@@ -188,6 +219,22 @@ func (s *Schema) deepCopyRec(visited map[*Schema]*Schema) *Schema {
 	newSchema.Default = s.Default
 	newSchema.AdditionalProperties = s.AdditionalProperties
 	newSchema.MaxProperties = s.MaxProperties
+	newSchema.Format = s.Format
+
+	newSchema.MinLength = copyPtr(s.MinLength)
+	newSchema.MaxLength = copyPtr(s.MaxLength)
+	newSchema.Pattern = s.Pattern
+	newSchema.Minimum = copyPtr(s.Minimum)
+	newSchema.Maximum = copyPtr(s.Maximum)
+	newSchema.ExclusiveMinimum = s.ExclusiveMinimum
+	newSchema.ExclusiveMaximum = s.ExclusiveMaximum
+	newSchema.MultipleOf = copyPtr(s.MultipleOf)
+	newSchema.MinItems = copyPtr(s.MinItems)
+	newSchema.MaxItems = copyPtr(s.MaxItems)
+	newSchema.UniqueItems = s.UniqueItems
+	newSchema.Nullable = s.Nullable
+	newSchema.ReadOnly = s.ReadOnly
+	newSchema.WriteOnly = s.WriteOnly
 
 	if s.Enum != nil {
 		newSchema.Enum = make([]interface{}, len(s.Enum))
@@ -199,6 +246,10 @@ func (s *Schema) deepCopyRec(visited map[*Schema]*Schema) *Schema {
 		newSchema.Items = s.Items.deepCopyRec(visited)
 	}
 
+	if s.AdditionalPropertiesSchema != nil {
+		newSchema.AdditionalPropertiesSchema = s.AdditionalPropertiesSchema.deepCopyRec(visited)
+	}
+
 	if s.Properties != nil {
 		newSchema.Properties = make([]Property, len(s.Properties))
 		for i, p := range s.Properties {
@@ -220,5 +271,58 @@ func (s *Schema) deepCopyRec(visited map[*Schema]*Schema) *Schema {
 		}
 	}
 
+	if s.OneOf != nil {
+		newSchema.OneOf = make([]*Schema, len(s.OneOf))
+		for i, oneOfSchema := range s.OneOf {
+			newSchema.OneOf[i] = oneOfSchema.deepCopyRec(visited)
+		}
+	}
+
+	if s.AnyOf != nil {
+		newSchema.AnyOf = make([]*Schema, len(s.AnyOf))
+		for i, anyOfSchema := range s.AnyOf {
+			newSchema.AnyOf[i] = anyOfSchema.deepCopyRec(visited)
+		}
+	}
+
+	// Extension values are deep-copied, since some of them (e.g., x-kubernetes-validations) are
+	// extended in place during CRD preparation.
+	if s.Extensions != nil {
+		newSchema.Extensions = make(map[string]interface{}, len(s.Extensions))
+		for k, v := range s.Extensions {
+			newSchema.Extensions[k] = copyExtensionValue(v)
+		}
+	}
+
 	return newSchema
 }
+
+// copyExtensionValue returns a deep copy of an extension value, copying nested maps and slices.
+// Any other value is returned as is.
+func copyExtensionValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			m[k] = copyExtensionValue(item)
+		}
+		return m
+	case []interface{}:
+		s := make([]interface{}, len(val))
+		for i, item := range val {
+			s[i] = copyExtensionValue(item)
+		}
+		return s
+	default:
+		return val
+	}
+}
+
+// copyPtr returns a pointer to a copy of the value pointed by p, or nil if p is nil.
+func copyPtr[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+	v := *p
+	return &v
+}
@@ -17,6 +17,9 @@ func TestDefaultGeneratorConfig(t *testing.T) {
 		assert.NotNil(t, config)
 		assert.Equal(t, []string{"application/json"}, config.AcceptedMIMETypes)
 		assert.Equal(t, []int{http.StatusOK, http.StatusCreated}, config.SuccessCodes)
+		assert.False(t, config.CoerceNumberToInteger)
+		assert.Contains(t, config.SupportedFormats, "date-time")
+		assert.NotContains(t, config.SupportedFormats, "double")
 	})
 }
 
@@ -132,6 +135,80 @@ func TestSchema_DeepCopy(t *testing.T) {
 		assert.Equal(t, "allOf schema 1", original.AllOf[0].Description)
 	})
 
+	t.Run("should correctly copy a schema with OneOf, AnyOf and Extensions", func(t *testing.T) {
+		original := &Schema{
+			OneOf:      []*Schema{{Description: "oneOf schema 1"}},
+			AnyOf:      []*Schema{{Description: "anyOf schema 1"}},
+			Extensions: map[string]interface{}{"x-kubernetes-preserve-unknown-fields": true},
+		}
+		copied := original.deepCopy()
+
+		require.Len(t, copied.OneOf, 1)
+		require.Len(t, copied.AnyOf, 1)
+		assert.NotSame(t, original.OneOf[0], copied.OneOf[0])
+		assert.NotSame(t, original.AnyOf[0], copied.AnyOf[0])
+		assert.Equal(t, "oneOf schema 1", copied.OneOf[0].Description)
+		assert.Equal(t, "anyOf schema 1", copied.AnyOf[0].Description)
+
+		// Modify the copy and check the original is unchanged
+		copied.Extensions["x-kubernetes-preserve-unknown-fields"] = false
+		assert.Equal(t, true, original.Extensions["x-kubernetes-preserve-unknown-fields"])
+	})
+
+	t.Run("should deep copy the extension values", func(t *testing.T) {
+		original := &Schema{Type: []string{"object"}}
+		addValidationRule(original, "has(self.name)", "name is required")
+		copied := original.deepCopy()
+
+		// Adding a rule to the copy must not change the rules of the original, even if the slice has spare capacity
+		addValidationRule(copied, "has(self.id)", "id is required")
+		rules := copied.Extensions[extensionValidations].([]interface{})
+		rules[0].(map[string]interface{})["message"] = "modified"
+
+		originalRules := original.Extensions[extensionValidations].([]interface{})
+		require.Len(t, originalRules, 1)
+		assert.Equal(t, "name is required", originalRules[0].(map[string]interface{})["message"])
+		assert.Len(t, rules, 2)
+	})
+
+	t.Run("should correctly copy a schema with validation keywords", func(t *testing.T) {
+		minLength, maximum := int64(1), float64(10)
+		original := &Schema{
+			MinLength:        &minLength,
+			Pattern:          "^a",
+			Format:           "uuid",
+			Maximum:          &maximum,
+			ExclusiveMaximum: true,
+			UniqueItems:      true,
+			Nullable:         true,
+		}
+		copied := original.deepCopy()
+
+		assert.Equal(t, original, copied)
+		assert.NotSame(t, original.MinLength, copied.MinLength)
+		assert.NotSame(t, original.Maximum, copied.Maximum)
+
+		// Modify the copy and check the original is unchanged
+		*copied.MinLength = 5
+		assert.Equal(t, int64(1), *original.MinLength)
+	})
+
+	t.Run("should correctly copy a schema with AdditionalPropertiesSchema", func(t *testing.T) {
+		original := &Schema{
+			Type:                       []string{"object"},
+			AdditionalProperties:       true,
+			AdditionalPropertiesSchema: &Schema{Type: []string{"string"}},
+		}
+		copied := original.deepCopy()
+
+		assert.Equal(t, original, copied)
+		assert.NotSame(t, original.AdditionalPropertiesSchema, copied.AdditionalPropertiesSchema)
+
+		// Modify the copy and check the original is unchanged
+		copied.AdditionalPropertiesSchema.Type[0] = "integer"
+		assert.Equal(t, "string", original.AdditionalPropertiesSchema.Type[0])
+	})
+
 	t.Run("should correctly copy a schema with Enum", func(t *testing.T) {
 		original := &Schema{
 			Enum: []interface{}{"a", 1, "c"},
@@ -0,0 +1,139 @@
+package oas2jsonschema
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// Validation keywords (minLength, maximum, pattern, ...) are propagated to the CRD so that
+// invalid resources are rejected by the Kubernetes API server at admission time,
+// instead of by the external API at reconcile time.
+// Most of them are supported by Kubernetes as is, with the following exceptions:
+//   - "uniqueItems" is not allowed in CRDs: it is converted to `x-kubernetes-list-type: set`
+//     for arrays of scalars and dropped otherwise;
+//   - "pattern" must be a valid Go (RE2) regular expression: patterns using unsupported
+//     constructs (e.g., lookarounds) are dropped.
+//
+// Every change is reported as a SchemaGenerationError warning.
+
+const extensionListType = "x-kubernetes-list-type"
+
+// prepareValidationKeywords adjusts the validation keywords of a single schema (not recursive)
+// so that they can be used in a CRD.
+func prepareValidationKeywords(schema *Schema, path string) []error {
+	var warnings []error
+
+	if schema.UniqueItems {
+		schema.UniqueItems = false
+		if schema.Items != nil && isScalarType(getPrimaryType(schema.Items.Type)) {
+			setExtension(schema, extensionListType, "set")
+			warnings = append(warnings, SchemaGenerationError{
+				Path:    path,
+				Code:    CodeUniqueItemsConverted,
+				Message: "uniqueItems converted to x-kubernetes-list-type: set",
+			})
+		} else {
+			warnings = append(warnings, SchemaGenerationError{
+				Path:    path,
+				Code:    CodeValidationKeywordDropped,
+				Message: "uniqueItems dropped, it is supported only for arrays of scalar items",
+			})
+		}
+	}
+
+	if schema.Pattern != "" {
+		if _, err := regexp.Compile(schema.Pattern); err != nil {
+			warnings = append(warnings, SchemaGenerationError{
+				Path:    path,
+				Code:    CodeValidationKeywordDropped,
+				Message: fmt.Sprintf("pattern %q dropped, it is not a valid RE2 regular expression: %v", schema.Pattern, err),
+			})
+			schema.Pattern = ""
+		}
+	}
+
+	return warnings
+}
+
+// inheritValidationKeywords copies the validation keywords of an allOf schema into the parent schema.
+// Keywords already set in the parent schema are not overwritten.
+func inheritValidationKeywords(schema, allOfSchema *Schema) {
+	if schema.MinLength == nil {
+		schema.MinLength = allOfSchema.MinLength
+	}
+	if schema.MaxLength == nil {
+		schema.MaxLength = allOfSchema.MaxLength
+	}
+	if schema.Pattern == "" {
+		schema.Pattern = allOfSchema.Pattern
+	}
+	if schema.Format == "" {
+		schema.Format = allOfSchema.Format
+	}
+	if schema.Minimum == nil {
+		schema.Minimum = allOfSchema.Minimum
+		schema.ExclusiveMinimum = allOfSchema.ExclusiveMinimum
+	}
+	if schema.Maximum == nil {
+		schema.Maximum = allOfSchema.Maximum
+		schema.ExclusiveMaximum = allOfSchema.ExclusiveMaximum
+	}
+	if schema.MultipleOf == nil {
+		schema.MultipleOf = allOfSchema.MultipleOf
+	}
+	if schema.MinItems == nil {
+		schema.MinItems = allOfSchema.MinItems
+	}
+	if schema.MaxItems == nil {
+		schema.MaxItems = allOfSchema.MaxItems
+	}
+	if !schema.UniqueItems {
+		schema.UniqueItems = allOfSchema.UniqueItems
+	}
+}
+
+// validationKeywordsToMap adds the validation keywords of the schema to the map used for JSON marshalling.
+func validationKeywordsToMap(schema *Schema, m map[string]interface{}) {
+	if schema.MinLength != nil {
+		m["minLength"] = *schema.MinLength
+	}
+	if schema.MaxLength != nil {
+		m["maxLength"] = *schema.MaxLength
+	}
+	if schema.Pattern != "" {
+		m["pattern"] = schema.Pattern
+	}
+	if schema.Minimum != nil {
+		m["minimum"] = *schema.Minimum
+		if schema.ExclusiveMinimum {
+			m["exclusiveMinimum"] = true
+		}
+	}
+	if schema.Maximum != nil {
+		m["maximum"] = *schema.Maximum
+		if schema.ExclusiveMaximum {
+			m["exclusiveMaximum"] = true
+		}
+	}
+	if schema.MultipleOf != nil {
+		m["multipleOf"] = *schema.MultipleOf
+	}
+	if schema.MinItems != nil {
+		m["minItems"] = *schema.MinItems
+	}
+	if schema.MaxItems != nil {
+		m["maxItems"] = *schema.MaxItems
+	}
+	if schema.UniqueItems {
+		m["uniqueItems"] = true
+	}
+}
+
+// isScalarType checks if the given type is a JSON scalar type.
+func isScalarType(t string) bool {
+	switch t {
+	case "string", "integer", "number", "boolean":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,133 @@
+package oas2jsonschema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func int64Ptr(v int64) *int64 { return &v }
+
+func float64Ptr(v float64) *float64 { return &v }
+
+func TestPrepareSchemaForCRD_ValidationKeywords(t *testing.T) {
+	testCases := []struct {
+		name          string
+		schema        *Schema
+		expectedCodes []GenerationCode
+		assertSchema  func(t *testing.T, schema *Schema)
+	}{
+		{
+			name: "supported keywords are kept as is",
+			schema: &Schema{
+				Type:      []string{"string"},
+				MinLength: int64Ptr(1),
+				MaxLength: int64Ptr(63),
+				Pattern:   "^[a-z0-9-]+$",
+			},
+			assertSchema: func(t *testing.T, schema *Schema) {
+				assert.Equal(t, int64(1), *schema.MinLength)
+				assert.Equal(t, int64(63), *schema.MaxLength)
+				assert.Equal(t, "^[a-z0-9-]+$", schema.Pattern)
+			},
+		},
+		{
+			name: "uniqueItems on an array of scalars becomes a set list type",
+			schema: &Schema{
+				Type:        []string{"array"},
+				Items:       &Schema{Type: []string{"string"}},
+				UniqueItems: true,
+			},
+			expectedCodes: []GenerationCode{CodeUniqueItemsConverted},
+			assertSchema: func(t *testing.T, schema *Schema) {
+				assert.False(t, schema.UniqueItems)
+				assert.Equal(t, "set", schema.Extensions[extensionListType])
+			},
+		},
+		{
+			name: "uniqueItems on an array of objects is dropped",
+			schema: &Schema{
+				Type:        []string{"array"},
+				Items:       &Schema{Type: []string{"object"}, Properties: []Property{{Name: "a", Schema: &Schema{Type: []string{"string"}}}}},
+				UniqueItems: true,
+			},
+			expectedCodes: []GenerationCode{CodeValidationKeywordDropped},
+			assertSchema: func(t *testing.T, schema *Schema) {
+				assert.False(t, schema.UniqueItems)
+				assert.NotContains(t, schema.Extensions, extensionListType)
+			},
+		},
+		{
+			name: "pattern not supported by RE2 is dropped",
+			schema: &Schema{
+				Type:    []string{"string"},
+				Pattern: "^(?!admin).*$",
+			},
+			expectedCodes: []GenerationCode{CodeValidationKeywordDropped},
+			assertSchema: func(t *testing.T, schema *Schema) {
+				assert.Empty(t, schema.Pattern)
+			},
+		},
+		{
+			name: "allOf keywords are inherited without overwriting the parent ones",
+			schema: &Schema{
+				Description: "the name",
+				MaxLength:   int64Ptr(10),
+				AllOf: []*Schema{
+					{Type: []string{"string"}, MinLength: int64Ptr(3), MaxLength: int64Ptr(253)},
+				},
+			},
+			assertSchema: func(t *testing.T, schema *Schema) {
+				assert.Equal(t, []string{"string"}, schema.Type)
+				assert.Equal(t, int64(3), *schema.MinLength)
+				assert.Equal(t, int64(10), *schema.MaxLength)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			warnings, err := prepareSchemaForCRD(tc.schema, DefaultGeneratorConfig())
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedCodes, generationCodes(warnings))
+			tc.assertSchema(t, tc.schema)
+		})
+	}
+}
+
+func TestGenerateJsonSchema_ValidationKeywords(t *testing.T) {
+	schema := &Schema{
+		Type: []string{"object"},
+		Properties: []Property{
+			{Name: "name", Schema: &Schema{Type: []string{"string"}, MinLength: int64Ptr(0), MaxLength: int64Ptr(63), Pattern: "^[a-z]+$"}},
+			{Name: "replicas", Schema: &Schema{Type: []string{"integer"}, Minimum: float64Ptr(1), Maximum: float64Ptr(10), ExclusiveMaximum: true}},
+			{Name: "ratio", Schema: &Schema{Type: []string{"number"}, MultipleOf: float64Ptr(0.25), ExclusiveMinimum: true}},
+			{Name: "tags", Schema: &Schema{Type: []string{"array"}, Items: &Schema{Type: []string{"string"}}, MinItems: int64Ptr(1), MaxItems: int64Ptr(5)}},
+		},
+	}
+
+	out, err := GenerateJsonSchema(schema, DefaultGeneratorConfig())
+	require.NoError(t, err)
+
+	var m struct {
+		Properties map[string]map[string]interface{} `json:"properties"`
+	}
+	require.NoError(t, json.Unmarshal(out, &m))
+
+	assert.Equal(t, float64(0), m.Properties["name"]["minLength"])
+	assert.Equal(t, float64(63), m.Properties["name"]["maxLength"])
+	assert.Equal(t, "^[a-z]+$", m.Properties["name"]["pattern"])
+
+	assert.Equal(t, float64(1), m.Properties["replicas"]["minimum"])
+	assert.NotContains(t, m.Properties["replicas"], "exclusiveMinimum")
+	assert.Equal(t, float64(10), m.Properties["replicas"]["maximum"])
+	assert.Equal(t, true, m.Properties["replicas"]["exclusiveMaximum"])
+
+	assert.Equal(t, 0.25, m.Properties["ratio"]["multipleOf"])
+	assert.NotContains(t, m.Properties["ratio"], "exclusiveMinimum", "exclusiveMinimum without minimum must not be emitted")
+
+	assert.Equal(t, float64(1), m.Properties["tags"]["minItems"])
+	assert.Equal(t, float64(5), m.Properties["tags"]["maxItems"])
+}
@@ -95,10 +95,41 @@ func compareSchemas(path string, schema1, schema2 *Schema, action1, action2 stri
 	defer cancel()
 
 	visited := make(map[schemaPair]bool)
-	return compareSchemasRec(ctx, guard, visited, 0, path, schema1, schema2, action1, action2)
+	return compareSchemasRec(ctx, guard, visited, 0, path, schema1, schema2, action1, action2, config.CoerceNumberToInteger)
 }
 
-func compareSchemasRec(ctx context.Context, guard *safety.RecursionGuard, visited map[schemaPair]bool, depth int, path string, schema1, schema2 *Schema, action1, action2 string) []error {
+// compareTypes returns a validation error if the two slices of types are not compatible, nil otherwise.
+// A null-only branch (e.g., ["null"]) is compatible with any type, since it only makes the field nullable.
+// A "number" vs "integer" difference is reported with a dedicated code, unless the legacy coercion
+// of numbers to integers is enabled (in that case both end up as "integer" in the CRD).
+func compareTypes(path, message string, types1, types2 []string, coerceNumbers bool) error {
+	if areTypesCompatible(types1, types2) || isNullOnlyType(types1) || isNullOnlyType(types2) {
+		return nil
+	}
+
+	if isNumericMismatch(types1, types2) {
+		if coerceNumbers {
+			return nil
+		}
+		return SchemaValidationError{
+			Path:     path,
+			Code:     CodeNumericTypeMismatch,
+			Message:  fmt.Sprintf("numeric %s", message),
+			Got:      types1,
+			Expected: types2,
+		}
+	}
+
+	return SchemaValidationError{
+		Path:     path,
+		Code:     CodeTypeMismatch,
+		Message:  message,
+		Got:      types1,
+		Expected: types2,
+	}
+}
+
+func compareSchemasRec(ctx context.Context, guard *safety.RecursionGuard, visited map[schemaPair]bool, depth int, path string, schema1, schema2 *Schema, action1, action2 string, coerceNumbers bool) []error {
 	if err := guard.Check(ctx, depth); err != nil {
 		return []error{SchemaValidationError{
 			Path:    path,
@@ -130,14 +161,13 @@ func compareSchemasRec(ctx context.Context, guard *safety.RecursionGuard, visite
 	schema2HasProps := len(schema2.Properties) > 0
 
 	if !schema1HasProps && !schema2HasProps {
-		if !areTypesCompatible(schema1.Type, schema2.Type) {
-			errors = append(errors, SchemaValidationError{
-				Path:     path,
-				Code:     CodeTypeMismatch,
-				Message:  fmt.Sprintf("type mismatch: first schema types are '%v', second are '%v'", schema1.Type, schema2.Type),
-				Got:      schema1.Type,
-				Expected: schema2.Type,
-			})
+		if err := compareTypes(path, fmt.Sprintf("type mismatch: first schema types are '%v', second are '%v'", schema1.Type, schema2.Type), schema1.Type, schema2.Type, coerceNumbers); err != nil {
+			errors = append(errors, err)
+			return errors
+		}
+		if schema1.AdditionalPropertiesSchema != nil && schema2.AdditionalPropertiesSchema != nil {
+			// recursively compare the values of typed maps
+			errors = append(errors, compareSchemasRec(ctx, guard, visited, depth+1, path, schema1.AdditionalPropertiesSchema, schema2.AdditionalPropertiesSchema, action1, action2, coerceNumbers)...)
 		}
 		return errors
 	}
@@ -180,24 +210,20 @@ func compareSchemasRec(ctx context.Context, guard *safety.RecursionGuard, visite
 		}
 
 		if !areTypesCompatible(prop1.Schema.Type, prop2.Schema.Type) {
-			errors = append(errors, SchemaValidationError{
-				Path:     currentPath,
-				Code:     CodeTypeMismatch,
-				Message:  fmt.Sprintf("type mismatch for field '%s': first schema types are '%v', second are '%v'", currentPath, prop1.Schema.Type, prop2.Schema.Type),
-				Got:      prop1.Schema.Type,
-				Expected: prop2.Schema.Type,
-			})
+			if err := compareTypes(currentPath, fmt.Sprintf("type mismatch for field '%s': first schema types are '%v', second are '%v'", currentPath, prop1.Schema.Type, prop2.Schema.Type), prop1.Schema.Type, prop2.Schema.Type, coerceNumbers); err != nil {
+				errors = append(errors, err)
+			}
 			continue
 		}
 
 		switch getPrimaryType(prop1.Schema.Type) {
 		case "object":
 			// recursively compare object schemas
-			errors = append(errors, compareSchemasRec(ctx, guard, visited, depth+1, currentPath, prop1.Schema, prop2.Schema, action1, action2)...)
+			errors = append(errors, compareSchemasRec(ctx, guard, visited, depth+1, currentPath, prop1.Schema, prop2.Schema, action1, action2, coerceNumbers)...)
 		case "array":
 			if prop1.Schema.Items != nil && prop2.Schema.Items != nil {
 				// recursively compare array item schemas
-				errors = append(errors, compareSchemasRec(ctx, guard, visited, depth+1, currentPath, prop1.Schema.Items, prop2.Schema.Items, action1, action2)...)
+				errors = append(errors, compareSchemasRec(ctx, guard, visited, depth+1, currentPath, prop1.Schema.Items, prop2.Schema.Items, action1, action2, coerceNumbers)...)
 			} else if prop1.Schema.Items != nil && prop2.Schema.Items == nil {
 				errors = append(errors, SchemaValidationError{
 					Path:    currentPath,
@@ -1,10 +1,12 @@
 package oas2jsonschema
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // TestCompareSchemas
@@ -251,6 +253,36 @@ func TestCompareSchemas(t *testing.T) {
 			},
 			expectErr: false,
 		},
+		{
+			name: "Incompatible map values",
+			schema1: &Schema{
+				Properties: []Property{
+					{Name: "labels", Schema: &Schema{Type: []string{"object"}, AdditionalPropertiesSchema: &Schema{Type: []string{"string"}}}},
+				},
+			},
+			schema2: &Schema{
+				Properties: []Property{
+					{Name: "labels", Schema: &Schema{Type: []string{"object"}, AdditionalPropertiesSchema: &Schema{Type: []string{"boolean"}}}},
+				},
+			},
+			expectErr:   true,
+			errCount:    1,
+			errContains: "type mismatch",
+		},
+		{
+			name: "Compatible schemas with null-only branch",
+			schema1: &Schema{
+				Properties: []Property{
+					{Name: "deletedAt", Schema: &Schema{Type: []string{"null"}}},
+				},
+			},
+			schema2: &Schema{
+				Properties: []Property{
+					{Name: "deletedAt", Schema: &Schema{Type: []string{"string"}}},
+				},
+			},
+			expectErr: false,
+		},
 		{
 			name: "Incompatible array of primitives",
 			schema1: &Schema{
@@ -290,6 +322,52 @@ func TestCompareSchemas(t *testing.T) {
 	}
 }
 
+func TestCompareSchemas_NumericMismatch(t *testing.T) {
+	schema1 := &Schema{
+		Properties: []Property{
+			{Name: "price", Schema: &Schema{Type: []string{"number"}}},
+		},
+	}
+	schema2 := &Schema{
+		Properties: []Property{
+			{Name: "price", Schema: &Schema{Type: []string{"integer"}}},
+		},
+	}
+
+	t.Run("number vs integer is reported with a dedicated code", func(t *testing.T) {
+		errs := compareSchemas(".", schema1, schema2, "create", "get", DefaultGeneratorConfig())
+		require.Len(t, errs, 1)
+
+		var validationErr SchemaValidationError
+		require.True(t, errors.As(errs[0], &validationErr))
+		assert.Equal(t, CodeNumericTypeMismatch, validationErr.Code)
+		assert.Equal(t, "price", validationErr.Path)
+	})
+
+	t.Run("number vs integer is compatible with the legacy coercion", func(t *testing.T) {
+		config := DefaultGeneratorConfig()
+		config.CoerceNumberToInteger = true
+
+		errs := compareSchemas(".", schema1, schema2, "create", "get", config)
+		assert.Empty(t, errs)
+	})
+
+	t.Run("number vs string is still a type mismatch", func(t *testing.T) {
+		stringSchema := &Schema{
+			Properties: []Property{
+				{Name: "price", Schema: &Schema{Type: []string{"string"}}},
+			},
+		}
+
+		errs := compareSchemas(".", schema1, stringSchema, "create", "get", DefaultGeneratorConfig())
+		require.Len(t, errs, 1)
+
+		var validationErr SchemaValidationError
+		require.True(t, errors.As(errs[0], &validationErr))
+		assert.Equal(t, CodeTypeMismatch, validationErr.Code)
+	})
+}
+
 func TestCompareSchemas_RecursionLimit(t *testing.T) {
 	// Create a schema that is deeply nested
 	var createDeepSchema func(depth int) *Schema
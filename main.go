@@ -16,6 +16,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	"github.com/krateoplatformops/oasgen-provider/apis"
 	"github.com/krateoplatformops/provider-runtime/pkg/controller"
@@ -39,6 +40,8 @@ func main() {
 	leaderElection := flag.Bool("leader-election", env.Bool(fmt.Sprintf("%s_LEADER_ELECTION", envVarPrefix), false), "Use leader election for the controller manager.")
 	maxErrorRetryInterval := flag.Duration("max-error-retry-interval", env.Duration(fmt.Sprintf("%s_MAX_ERROR_RETRY_INTERVAL", envVarPrefix), 1*time.Minute), "The maximum interval between retries when an error occurs. This should be less than the half of the poll interval.")
 	minErrorRetryInterval := flag.Duration("min-error-retry-interval", env.Duration(fmt.Sprintf("%s_MIN_ERROR_RETRY_INTERVAL", envVarPrefix), 1*time.Second), "The minimum interval between retries when an error occurs. This should be less than max-error-retry-interval.")
+	webhookPort := flag.Int("webhook-port", env.Int(fmt.Sprintf("%s_WEBHOOK_PORT", envVarPrefix), 9443), "The port the conversion webhook server listens on. The server is started only if the conversion webhook is enabled (CONVERSION_WEBHOOK_SERVICE is set).")
+	webhookCertDir := flag.String("webhook-cert-dir", env.String(fmt.Sprintf("%s_WEBHOOK_CERT_DIR", envVarPrefix), ""), "The directory containing the TLS certificate (tls.crt) and key (tls.key) of the conversion webhook server. Defaults to <temp-dir>/k8s-webhook-server/serving-certs.")
 
 	flag.Parse()
 
@@ -104,6 +107,10 @@ func main() {
 		Metrics: metricsserver.Options{
 			BindAddress: ":8080",
 		},
+		WebhookServer: webhook.NewServer(webhook.Options{
+			Port:    *webhookPort,
+			CertDir: *webhookCertDir,
+		}),
 	})
 	if err != nil {
 		log.Error(err, "Cannot create controller manager")